@@ -0,0 +1,170 @@
+// Package agenda groups notes by dates given in tags such as deadline:2020-10-01 and handles recurring tasks
+package agenda
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/elgopher/noteo/date"
+	"github.com/elgopher/noteo/notes"
+	"github.com/elgopher/noteo/seq"
+	"github.com/elgopher/noteo/tag"
+)
+
+type Group string
+
+const (
+	Overdue  Group = "overdue"
+	Today    Group = "today"
+	ThisWeek Group = "this week"
+	Later    Group = "later"
+)
+
+// Groups in the order they should be presented
+var Groups = []Group{Overdue, Today, ThisWeek, Later}
+
+// DoneTag is set by Done on notes without a repeat tag. Notes with this tag are not listed in agenda.
+const DoneTag = "done"
+
+// Entry is a note with one of its date tags
+type Entry struct {
+	Note  notes.Note
+	Tag   tag.Tag
+	Date  time.Time
+	Group Group
+}
+
+// Collect returns entries for all notes having tags with given names, sorted by date ascending. Notes tagged
+// with DoneTag are skipped. Errors for notes having invalid dates are sent to errs.
+func Collect(ctx context.Context, all <-chan notes.Note, tagNames []string, errs chan<- error) ([]Entry, error) {
+	var entries []Entry
+	CollectSeq(seq.FromChannel(ctx, all), tagNames)(func(entry Entry, err error) bool {
+		if err != nil {
+			select {
+			case <-ctx.Done():
+				return false
+			case errs <- err:
+				return true
+			}
+		}
+		entries = append(entries, entry)
+		return true
+	})
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return entries, nil
+}
+
+// CollectSeq yields entries for all notes having tags with given names, sorted by date ascending. Notes tagged
+// with DoneTag are skipped. Errors for notes having invalid dates are yielded as they occur, before any entry.
+func CollectSeq(all seq.Seq[notes.Note], tagNames []string) seq.Seq[Entry] {
+	return func(yield func(Entry, error) bool) {
+		today, err := date.Parse("today")
+		if err != nil {
+			yield(Entry{}, err)
+			return
+		}
+		endOfWeek, err := date.Parse("end of week")
+		if err != nil {
+			yield(Entry{}, err)
+			return
+		}
+		var entries []Entry
+		stopped := false
+		all(func(note notes.Note, err error) bool {
+			if err == nil {
+				var noteEntries []Entry
+				noteEntries, err = entriesOf(note, tagNames, today, endOfWeek)
+				entries = append(entries, noteEntries...)
+			}
+			if err != nil && !yield(Entry{}, err) {
+				stopped = true
+				return false
+			}
+			return true
+		})
+		if stopped {
+			return
+		}
+		sorted, errs := sortByDate(entries)
+		for _, err = range errs {
+			if !yield(Entry{}, err) {
+				return
+			}
+		}
+		for _, entry := range sorted {
+			if !yield(entry, nil) {
+				return
+			}
+		}
+	}
+}
+
+// entryTag is the name of tag which entryNote has, so entries can be sorted using notes.TagDateAsc
+const entryTag = "date"
+
+// entryNote is a note having only the tag of the entry
+type entryNote struct {
+	notes.Note
+	entry Entry
+}
+
+func (n entryNote) Tags() ([]tag.Tag, error) {
+	return []tag.Tag{n.entry.Tag.WithName(entryTag)}, nil
+}
+
+func sortByDate(entries []Entry) ([]Entry, []error) {
+	slice := make([]notes.Note, len(entries))
+	for i, e := range entries {
+		slice[i] = entryNote{Note: e.Note, entry: e}
+	}
+	errs := notes.Sort(slice, notes.TagDateAsc(entryTag))
+	sorted := make([]Entry, len(slice))
+	for i, n := range slice {
+		sorted[i] = n.(entryNote).entry
+	}
+	return sorted, errs
+}
+
+func entriesOf(note notes.Note, tagNames []string, today, endOfWeek time.Time) ([]Entry, error) {
+	if _, done, err := notes.FindTagByName(note, DoneTag); err != nil || done {
+		return nil, err
+	}
+	var entries []Entry
+	for _, name := range tagNames {
+		t, found, err := notes.FindTagByName(note, name)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			continue
+		}
+		d, err := t.AbsoluteDate()
+		if err != nil {
+			return nil, fmt.Errorf("%s: error getting date from tag \"%s\": %w", note.Path(), t, err)
+		}
+		entries = append(entries, Entry{
+			Note:  note,
+			Tag:   t,
+			Date:  d,
+			Group: groupOf(d, today, endOfWeek),
+		})
+	}
+	return entries, nil
+}
+
+func groupOf(d, today, endOfWeek time.Time) Group {
+	tomorrow := today.AddDate(0, 0, 1)
+	switch {
+	case d.Before(today):
+		return Overdue
+	case d.Before(tomorrow):
+		return Today
+	case d.Before(endOfWeek.AddDate(0, 0, 1)):
+		return ThisWeek
+	default:
+		return Later
+	}
+}
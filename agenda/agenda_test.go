@@ -0,0 +1,156 @@
+package agenda_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/noteo/agenda"
+	"github.com/elgopher/noteo/date"
+	"github.com/elgopher/noteo/note"
+	"github.com/elgopher/noteo/notes"
+	"github.com/elgopher/noteo/seq"
+)
+
+func TestCollect(t *testing.T) {
+	// Wednesday
+	date.SetNow(func() time.Time {
+		return time.Date(2020, 10, 14, 16, 30, 0, 0, time.Local)
+	})
+	defer date.SetNow(time.Now)
+
+	overdue := noteWithContent(t, "---\nTags: deadline:2020-10-13\n---\n")
+	today := noteWithContent(t, "---\nTags: scheduled:2020-10-14\n---\n")
+	thisWeek := noteWithContent(t, "---\nTags: deadline:2020-10-18\n---\n")
+	later := noteWithContent(t, "---\nTags: deadline:2020-10-19\n---\n")
+	done := noteWithContent(t, "---\nTags: deadline:2020-10-13 done\n---\n")
+	noDate := noteWithContent(t, "---\nTags: idea\n---\n")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	all := make(chan notes.Note, 6)
+	for _, n := range []notes.Note{later, done, today, noDate, thisWeek, overdue} {
+		all <- n
+	}
+	close(all)
+	errs := make(chan error, 6)
+	// when
+	entries, err := agenda.Collect(ctx, all, []string{"deadline", "scheduled"}, errs)
+	// then
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+	assert.Equal(t, overdue, entries[0].Note)
+	assert.Equal(t, agenda.Overdue, entries[0].Group)
+	assert.Equal(t, today, entries[1].Note)
+	assert.Equal(t, agenda.Today, entries[1].Group)
+	assert.Equal(t, thisWeek, entries[2].Note)
+	assert.Equal(t, agenda.ThisWeek, entries[2].Group)
+	assert.Equal(t, later, entries[3].Note)
+	assert.Equal(t, agenda.Later, entries[3].Group)
+	assert.Empty(t, errs)
+}
+
+func TestCollectSeq(t *testing.T) {
+	date.SetNow(func() time.Time {
+		return time.Date(2020, 10, 14, 16, 30, 0, 0, time.Local)
+	})
+	defer date.SetNow(time.Now)
+
+	valid := noteWithContent(t, "---\nTags: deadline:2020-10-13\n---\n")
+	invalid := noteWithContent(t, "---\nTags: deadline:2020-13-45\n---\n")
+	all := func(yield func(notes.Note, error) bool) {
+		_ = yield(valid, nil) && yield(invalid, nil)
+	}
+	// when
+	entries, errs := seq.Collect(agenda.CollectSeq(all, []string{"deadline"}))
+	// then
+	require.Len(t, entries, 1)
+	assert.Equal(t, valid, entries[0].Note)
+	require.Len(t, errs, 1)
+	var parseError *time.ParseError
+	assert.ErrorAs(t, errs[0], &parseError)
+}
+
+func TestDone(t *testing.T) {
+	date.SetNow(func() time.Time {
+		return time.Date(2020, 10, 14, 16, 30, 0, 0, time.Local)
+	})
+	defer date.SetNow(time.Now)
+
+	tests := map[string]struct {
+		content      string
+		expectedTags []string
+	}{
+		"not recurring": {
+			content:      "---\nTags: deadline:2020-10-14\n---\n",
+			expectedTags: []string{"done"},
+		},
+		"weekly": {
+			content:      "---\nTags: deadline:2020-10-14 repeat:weekly\n---\n",
+			expectedTags: []string{"deadline:2020-10-21"},
+		},
+		"done before deadline": {
+			content:      "---\nTags: deadline:2020-10-20 repeat:weekly\n---\n",
+			expectedTags: []string{"deadline:2020-10-27"},
+		},
+		"overdue": {
+			content:      "---\nTags: deadline:2020-09-01 repeat:monthly\n---\n",
+			expectedTags: []string{"deadline:2020-11-01"},
+		},
+		"two date tags": {
+			content:      "---\nTags: scheduled:2020-10-12 deadline:2020-10-15 repeat:3d\n---\n",
+			expectedTags: []string{"deadline:2020-10-18", "scheduled:2020-10-15"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			n := noteWithContent(t, test.content)
+			// when
+			tags, err := agenda.Done(n, []string{"deadline", "scheduled"})
+			// then
+			require.NoError(t, err)
+			var actual []string
+			for _, tg := range tags {
+				actual = append(actual, tg.String())
+			}
+			assert.Equal(t, test.expectedTags, actual)
+		})
+	}
+
+	t.Run("should return error for invalid repeat", func(t *testing.T) {
+		n := noteWithContent(t, "---\nTags: deadline:2020-10-14 repeat:sometimes\n---\n")
+		_, err := agenda.Done(n, []string{"deadline"})
+		assert.Error(t, err)
+	})
+}
+
+func TestParseRepeat(t *testing.T) {
+	given := time.Date(2020, 1, 31, 0, 0, 0, 0, time.UTC)
+	tests := map[string]time.Time{
+		"daily":   time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC),
+		"weekly":  time.Date(2020, 2, 7, 0, 0, 0, 0, time.UTC),
+		"monthly": time.Date(2020, 3, 2, 0, 0, 0, 0, time.UTC),
+		"yearly":  time.Date(2021, 1, 31, 0, 0, 0, 0, time.UTC),
+		"2w":      time.Date(2020, 2, 14, 0, 0, 0, 0, time.UTC),
+		"10d":     time.Date(2020, 2, 10, 0, 0, 0, 0, time.UTC),
+		"6m":      time.Date(2020, 7, 31, 0, 0, 0, 0, time.UTC),
+	}
+	for value, expected := range tests {
+		t.Run(value, func(t *testing.T) {
+			repeat, err := agenda.ParseRepeat(value)
+			require.NoError(t, err)
+			assert.Equal(t, expected, repeat.Next(given))
+		})
+	}
+}
+
+func noteWithContent(t *testing.T, content string) *note.Note {
+	file, err := os.CreateTemp("", "noteo-test")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(file.Name(), []byte(content), os.ModePerm))
+	return note.New(file.Name())
+}
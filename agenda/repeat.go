@@ -0,0 +1,120 @@
+package agenda
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/elgopher/noteo/date"
+	"github.com/elgopher/noteo/note"
+	"github.com/elgopher/noteo/notes"
+	"github.com/elgopher/noteo/tag"
+)
+
+// RepeatTag makes a note recurring, e.g. repeat:weekly or repeat:2w
+const RepeatTag = "repeat"
+
+// Repeat is a recurrence period
+type Repeat struct {
+	days, months, years int
+}
+
+var repeatRegex = regexp.MustCompile(`^(\d+)([dwmy])$`)
+
+// ParseRepeat parses daily, weekly, monthly, yearly or period such as 3d, 2w, 6m or 1y
+func ParseRepeat(value string) (Repeat, error) {
+	switch value {
+	case "daily":
+		return Repeat{days: 1}, nil
+	case "weekly":
+		return Repeat{days: 7}, nil
+	case "monthly":
+		return Repeat{months: 1}, nil
+	case "yearly":
+		return Repeat{years: 1}, nil
+	}
+	match := repeatRegex.FindStringSubmatch(value)
+	if match == nil {
+		return Repeat{}, fmt.Errorf("unsupported repeat %s. Use daily, weekly, monthly, yearly or period such as 2w", value)
+	}
+	amount, err := strconv.Atoi(match[1])
+	if err != nil || amount == 0 {
+		return Repeat{}, fmt.Errorf("invalid repeat amount %s", value)
+	}
+	switch match[2] {
+	case "d":
+		return Repeat{days: amount}, nil
+	case "w":
+		return Repeat{days: 7 * amount}, nil
+	case "m":
+		return Repeat{months: amount}, nil
+	default:
+		return Repeat{years: amount}, nil
+	}
+}
+
+// Next returns the date of the next occurrence
+func (r Repeat) Next(d time.Time) time.Time {
+	return d.AddDate(r.years, r.months, r.days)
+}
+
+// Advance moves the date to the next occurrence. Overdue dates are moved until they are after today.
+func (r Repeat) Advance(d, today time.Time) time.Time {
+	d = r.Next(d)
+	for d.Before(today.AddDate(0, 0, 1)) {
+		d = r.Next(d)
+	}
+	return d
+}
+
+// Done marks note as done. Date tags of recurring notes (having repeat tag) are moved to the next occurrence,
+// other notes are tagged with DoneTag. Returned tags are the new ones.
+func Done(n *note.Note, tagNames []string) ([]tag.Tag, error) {
+	repeatTag, recurring, err := notes.FindTagByName(n, RepeatTag)
+	if err != nil {
+		return nil, err
+	}
+	if !recurring {
+		doneTag, err := tag.New(DoneTag)
+		if err != nil {
+			return nil, err
+		}
+		return []tag.Tag{doneTag}, n.SetTag(doneTag)
+	}
+	value, err := repeatTag.Value()
+	if err != nil {
+		return nil, err
+	}
+	repeat, err := ParseRepeat(value)
+	if err != nil {
+		return nil, err
+	}
+	today, err := date.Parse("today")
+	if err != nil {
+		return nil, err
+	}
+	var advanced []tag.Tag
+	for _, name := range tagNames {
+		t, found, err := notes.FindTagByName(n, name)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			continue
+		}
+		d, err := t.AbsoluteDate()
+		if err != nil {
+			return nil, fmt.Errorf("error getting date from tag \"%s\": %v", t, err)
+		}
+		next := t.WithDate(repeat.Advance(d, today))
+		if err := n.SetTag(next); err != nil {
+			return nil, err
+		}
+		advanced = append(advanced, next)
+	}
+	if len(advanced) == 0 {
+		return nil, fmt.Errorf("%s has %s tag but none of date tags: %v", n.Path(), RepeatTag, tagNames)
+	}
+	return advanced, nil
+}
@@ -6,6 +6,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,65 +14,104 @@ import (
 	"github.com/spf13/pflag"
 
 	"github.com/elgopher/noteo/config"
+	"github.com/elgopher/noteo/note"
+	"github.com/elgopher/noteo/repository"
+	noteotag "github.com/elgopher/noteo/tag"
 )
 
-var add = &cobra.Command{
-	Use:   "add [TEXT]",
-	Short: "Add a new note",
-	Long:  "Add a new note in a current working directory",
-	Aliases: []string{
-		"create", "new",
-	},
-	RunE: func(cmd *cobra.Command, args []string) error {
-		repo, err := workingDirRepository()
-		if err != nil {
-			return err
-		}
-		repoConfig, err := repo.Config()
-		if err != nil {
-			return err
-		}
-		cfg := config.New(repoConfig)
-		text, err := readNoteText(cmd.Flags(), cfg)
-		if err != nil {
-			return err
-		}
-		if text == "" {
-			fmt.Println("no new file added")
+func addCmd() *cobra.Command {
+	var naming string
+	add := &cobra.Command{
+		Use:   "add [TEXT]",
+		Short: "Add a new note",
+		Long: `Add a new note in a current working directory.
+
+Text of the note starts with a template given in add.template setting, and tags from add.tags setting are added.
+Both can be set in .noteo.yml in the working directory or its parent directories, e.g. to tag all notes added
+in journal directory with "journal".`,
+		Aliases: []string{
+			"create", "new",
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, err := workingDirRepository()
+			if err != nil {
+				return err
+			}
+			cfg, err := loadConfig(cmd, repo)
+			if err != nil {
+				return err
+			}
+			if naming != "" {
+				cfg.SetFlag("naming", naming)
+			}
+			text, err := readNoteText(cmd.Flags(), cfg)
+			if err != nil {
+				return err
+			}
+			if text == "" {
+				fmt.Println("no new file added")
+				return nil
+			}
+			f, err := repo.AddNamed(text, repository.Naming(cfg.Naming()))
+			if err != nil {
+				return err
+			}
+			printer := NewPrinter()
+			printer.PrintFile(f)
+			printer.Println(" created")
 			return nil
-		}
-		f, err := repo.Add(text)
-		if err != nil {
-			return err
-		}
-		printer := NewPrinter()
-		printer.PrintFile(f)
-		printer.Println(" created")
-		return nil
-	},
+		},
+	}
+	add.Flags().StringVar(&naming, "naming", "", "file name of the note: title (default), date-title or timestamp")
+	return add
 }
 
+// readNoteText returns text of a new note with tags from add.tags setting. Tags are rendered by the template, so
+// the ones removed in the editor are not added. Tags are added to the front matter when the template does not use them.
 func readNoteText(flags *pflag.FlagSet, cfg *config.Config) (string, error) {
+	initial, tagsRendered, err := newFileTemplate(cfg, time.Now())
+	if err != nil {
+		return "", err
+	}
 	var text string
-	if len(flags.Args()) == 0 {
-		template := newFileTemplate(time.Now()) + "\n"
+	if len(flags.Args()) > 0 {
+		text = initial + strings.Join(flags.Args(), " ")
+	} else {
+		initial += "\n"
 		tmpFile := filepath.Join(os.TempDir(), uuid.New().String()+" .md")
-		if err := os.WriteFile(tmpFile, []byte(template), 0664); err != nil {
+		if err = os.WriteFile(tmpFile, []byte(initial), 0664); err != nil {
 			return "", err
 		}
-		text, err := textFromEditor(tmpFile, cfg.EditorCommand())
+		text, err = textFromEditor(tmpFile, cfg.EditorCommand())
 		if err != nil {
 			return "", err
 		}
-		if text == template {
+		if text == initial {
 			return "", nil
 		}
+	}
+	if tagsRendered {
+		return text, nil
+	}
+	return withTags(text, cfg.AddTags())
+}
+
+// withTags adds tags to the front matter of text
+func withTags(text string, tags []string) (string, error) {
+	if len(tags) == 0 {
 		return text, nil
-	} else {
-		template := newFileTemplate(time.Now())
-		text = template + strings.Join(flags.Args(), " ")
 	}
-	return text, nil
+	n := note.NewFromText("", text)
+	for _, t := range tags {
+		newTag, err := noteotag.New(t)
+		if err != nil {
+			return "", err
+		}
+		if err = n.SetTag(newTag); err != nil {
+			return "", err
+		}
+	}
+	return n.Content()
 }
 
 func textFromEditor(file, editorCommand string) (string, error) {
@@ -92,11 +132,43 @@ func textFromEditor(file, editorCommand string) (string, error) {
 	return string(text), nil
 }
 
-func newFileTemplate(created time.Time) string {
-	return `---
-Created: ` + created.Format(time.UnixDate) + `
-Tags: 
+const defaultTemplate = `---
+Created: {{.Created}}
+Tags: {{.Tags}}
 ---
 
 `
+
+// newFileTemplate returns beginning of a new note made from the template given in add.template setting. Returns true
+// when the template rendered tags given in add.tags setting.
+func newFileTemplate(cfg *config.Config, created time.Time) (string, bool, error) {
+	text := defaultTemplate
+	if file := cfg.AddTemplate(); file != "" {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return "", false, err
+		}
+		text = string(content)
+	}
+	t, err := template.New("note").Parse(text)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid template: %v", err)
+	}
+	var b strings.Builder
+	data := &templateData{Created: created.Format(time.UnixDate), tags: cfg.AddTags()}
+	err = t.Execute(&b, data)
+	return b.String(), data.tagsRendered, err
+}
+
+// templateData is given to the template of a new note
+type templateData struct {
+	Created      string
+	tags         []string
+	tagsRendered bool
+}
+
+// Tags returns tags given in add.tags setting separated with spaces
+func (d *templateData) Tags() string {
+	d.tagsRendered = true
+	return strings.Join(d.tags, " ")
 }
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elgopher/noteo/agenda"
+	"github.com/elgopher/noteo/output/jayson"
+)
+
+func agendaCmd() *cobra.Command {
+	var (
+		outputFormat string
+		tagNames     []string
+		strict       bool
+	)
+	agendaCmd := &cobra.Command{
+		Use:   "agenda",
+		Short: "List notes with deadlines grouped into overdue, today, this week and later",
+		Long: `List notes with date tags grouped into overdue, today, this week and later.
+
+By default deadline and scheduled tags are used. Names can be changed in .noteo.yml:
+
+  agenda:
+    tags: [deadline, scheduled]
+
+Notes tagged with "done" are not listed.`,
+		Args: cobra.RangeArgs(0, 1),
+		Example: `
+  # List agenda of notes in current directory
+  noteo agenda
+
+  # List agenda using only "due" tag in JSON format
+  noteo agenda --tag due -o json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, err := repo(args)
+			if err != nil {
+				return err
+			}
+			repoConfig, err := repo.Config()
+			if err != nil {
+				return err
+			}
+			if len(tagNames) == 0 {
+				tagNames = repoConfig.AgendaTags()
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			errs := newNoteErrors(cmd.ErrOrStderr(), strict)
+			var entries []agenda.Entry
+			stopped := false
+			agenda.CollectSeq(toNotesSeq(repo.NotesSeq(ctx)), tagNames)(func(entry agenda.Entry, err error) bool {
+				if err != nil {
+					stopped = !errs.report(err)
+					return !stopped
+				}
+				entries = append(entries, entry)
+				return true
+			})
+			if stopped {
+				return errs.err()
+			}
+			switch strings.ToLower(outputFormat) {
+			case "table":
+				printAgenda(entries)
+			case "json":
+				if err = printAgendaJSON(entries); err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("unsupported output format in --output flag: %s", outputFormat)
+			}
+			return errs.err()
+		},
+	}
+	agendaCmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "output format: table or json")
+	agendaCmd.Flags().StringArrayVarP(&tagNames, "tag", "t", nil, "name of tag with date. Flag can be specified multiple times")
+	agendaCmd.Flags().BoolVar(&strict, "strict", false, "stop on first note which could not be read")
+	return agendaCmd
+}
+
+func printAgenda(entries []agenda.Entry) {
+	printer := NewPrinter()
+	tagWidth := 0
+	for _, e := range entries {
+		if len(e.Tag.Name()) > tagWidth {
+			tagWidth = len(e.Tag.Name())
+		}
+	}
+	for _, group := range agenda.Groups {
+		printedHeader := false
+		for _, e := range entries {
+			if e.Group != group {
+				continue
+			}
+			if !printedHeader {
+				printer.PrintCommand(strings.ToUpper(string(group)))
+				printer.Println()
+				printedHeader = true
+			}
+			printer.Print(fmt.Sprintf("  %s  %-*s  ", e.Date.Format("Mon 2006-01-02"), tagWidth, e.Tag.Name()))
+			printer.PrintFile(e.Note.Path())
+			printer.Println()
+		}
+	}
+}
+
+type agendaEntry struct {
+	Group string    `json:"group"`
+	Tag   string    `json:"tag"`
+	Date  time.Time `json:"date"`
+	*jayson.Note
+}
+
+func printAgendaJSON(entries []agenda.Entry) error {
+	formatter := jayson.Formatter{}
+	for _, e := range entries {
+		n, err := formatter.Convert(e.Note)
+		if err != nil {
+			return err
+		}
+		bytes, err := json.Marshal(agendaEntry{
+			Group: string(e.Group),
+			Tag:   e.Tag.String(),
+			Date:  e.Date,
+			Note:  n,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(bytes))
+	}
+	return nil
+}
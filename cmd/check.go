@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elgopher/noteo/note"
+)
+
+func check() *cobra.Command {
+	check := &cobra.Command{
+		Use:     "check",
+		Short:   "Check notes for problems",
+		Aliases: []string{"doctor"},
+	}
+	check.AddCommand(checkTags)
+	check.AddCommand(checkFrontMatter())
+	return check
+}
+
+func checkFrontMatter() *cobra.Command {
+	var fix bool
+	checkFrontMatter := &cobra.Command{
+		Use:   "front-matter",
+		Short: "Report front matter problems, such as invalid YAML, unsupported Created layout or duplicate tags",
+		Example: `
+  # Report problems in all notes
+  noteo check front-matter
+
+  # Fix problems which can be fixed safely, e.g. duplicate tags or Created in a known layout
+  noteo check front-matter --fix`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, err := workingDirRepository()
+			if err != nil {
+				return err
+			}
+			ctx := context.Background()
+			printer := NewPrinter()
+			remaining := 0
+			repo.AllNotesSeq(ctx)(func(n *note.Note, err error) bool {
+				if err != nil {
+					_, _ = fmt.Fprintln(cmd.ErrOrStderr(), err)
+					return true
+				}
+				problems, err := n.Check()
+				if err != nil {
+					_, _ = fmt.Fprintln(cmd.ErrOrStderr(), err)
+					return true
+				}
+				fixed := 0
+				for _, problem := range problems {
+					status := ""
+					switch {
+					case fix && problem.Fixable():
+						if err = problem.Fix(n); err != nil {
+							status = fmt.Sprintf(" (fix failed: %v)", err)
+							remaining++
+						} else {
+							status = " (fixed)"
+							fixed++
+						}
+					case problem.Warning:
+						status = " (warning)"
+					default:
+						remaining++
+					}
+					printProblem(printer, n.Path(), problem.Line, problem.Message+status, problem.Suggestion)
+				}
+				if fixed > 0 {
+					if _, err = n.Save(); err != nil {
+						_, _ = fmt.Fprintln(cmd.ErrOrStderr(), err)
+						remaining += fixed
+					}
+				}
+				return true
+			})
+			repo.NonNoteFilesSeq(ctx)(func(file string, err error) bool {
+				if err != nil {
+					_, _ = fmt.Fprintln(cmd.ErrOrStderr(), err)
+					return true
+				}
+				printProblem(printer, file, 0, "not a note (warning)",
+					"rename to *.md, move outside the repository or add to .noteoignore")
+				return true
+			})
+			if remaining > 0 {
+				return exitError{code: exitCheck, message: fmt.Sprintf("%d front matter problems found", remaining)}
+			}
+			return nil
+		},
+	}
+	checkFrontMatter.Flags().BoolVar(&fix, "fix", false, "fix problems which can be fixed safely and save notes")
+	return checkFrontMatter
+}
+
+// printProblem prints file:line: message, followed by suggested fix in the next line. Line 0 is omitted.
+func printProblem(printer *Printer, file string, line int, message, suggestion string) {
+	printer.PrintFile(file)
+	if line > 0 {
+		printer.Print(fmt.Sprintf(":%d", line))
+	}
+	printer.Println(": " + message)
+	if suggestion != "" {
+		printer.Println("  suggestion: " + suggestion)
+	}
+}
+
+var checkTags = &cobra.Command{
+	Use:   "tags",
+	Short: "Report tags violating vocabulary defined in .noteo.yml",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repo, err := workingDirRepository()
+		if err != nil {
+			return err
+		}
+		repoConfig, err := repo.Config()
+		if err != nil {
+			return err
+		}
+		vocabulary, err := repoConfig.Vocabulary()
+		if err != nil {
+			return err
+		}
+		printer := NewPrinter()
+		violations := 0
+		repo.AllNotesSeq(context.Background())(func(n *note.Note, err error) bool {
+			if err != nil {
+				_, _ = fmt.Fprintln(cmd.ErrOrStderr(), err)
+				return true
+			}
+			tags, err := n.Tags()
+			if err != nil {
+				_, _ = fmt.Fprintln(cmd.ErrOrStderr(), err)
+				return true
+			}
+			for _, t := range tags {
+				normalized, err := vocabulary.Normalize(t)
+				var problem string
+				switch {
+				case err != nil:
+					problem = err.Error()
+				case normalized != t:
+					problem = fmt.Sprintf("tag %s is an alias, use %s instead", t, normalized)
+				default:
+					continue
+				}
+				violations++
+				printer.PrintFile(n.Path())
+				printer.Println(": " + problem)
+			}
+			return true
+		})
+		if violations > 0 {
+			return fmt.Errorf("%d tag violations found", violations)
+		}
+		return nil
+	},
+}
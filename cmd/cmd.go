@@ -9,10 +9,23 @@ import (
 	"github.com/elgopher/noteo/note"
 	"github.com/elgopher/noteo/notes"
 	"github.com/elgopher/noteo/repository"
+	"github.com/elgopher/noteo/seq"
 )
 
+// namedRepoDir is a directory of the repository given in --repo flag, which commands use instead of the working
+// directory
+var namedRepoDir string
+
+// workDir returns directory of the repository given in --repo flag or the working directory
+func workDir() (string, error) {
+	if namedRepoDir != "" {
+		return namedRepoDir, nil
+	}
+	return os.Getwd()
+}
+
 func repo(commandArgs []string) (*repository.Repository, error) {
-	wd, err := os.Getwd()
+	wd, err := workDir()
 	if err != nil {
 		return nil, err
 	}
@@ -28,7 +41,7 @@ func repo(commandArgs []string) (*repository.Repository, error) {
 }
 
 func workingDirRepository() (*repository.Repository, error) {
-	wd, err := os.Getwd()
+	wd, err := workDir()
 	if err != nil {
 		return nil, err
 	}
@@ -39,20 +52,10 @@ func workingDirRepository() (*repository.Repository, error) {
 	return repo, nil
 }
 
-func toNotes(all <-chan *note.Note) <-chan notes.Note {
-	ret := make(chan notes.Note)
-	go func() {
-		defer close(ret)
-		for {
-			note, ok := <-all
-			if !ok {
-				return
-			}
-			ret <- note
-		}
-
-	}()
-	return ret
+func toNotesSeq(all seq.Seq[*note.Note]) seq.Seq[notes.Note] {
+	return seq.Map(all, func(n *note.Note) notes.Note {
+		return n
+	})
 }
 
 func printErrors(ctx context.Context, errors ...<-chan error) {
@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/elgopher/noteo/config"
+	"github.com/elgopher/noteo/repository"
+)
+
+// colorMode is auto, always or never. It is resolved from settings before running any command.
+var colorMode = "auto"
+
+// resolveColorMode returns color setting. Settings are loaded for every command, so errors are ignored here and
+// malformed config does not break commands which do not use it, such as init or config set. Commands using the
+// config report errors themselves.
+func resolveColorMode(cmd *cobra.Command) string {
+	cfg, err := loadWorkingDirConfig(cmd)
+	if err != nil {
+		cfg, err = loadConfig(cmd, nil)
+	}
+	if err != nil {
+		if flag, _ := cmd.Flags().GetString("color"); flag != "" {
+			return flag
+		}
+		return "auto"
+	}
+	return cfg.Color()
+}
+
+// colorEnabled returns true when output should be colored. In auto mode colors are used only in a terminal.
+func colorEnabled() bool {
+	switch colorMode {
+	case "always":
+		return true
+	case "never":
+		return false
+	default:
+		return term.IsTerminal(int(os.Stdout.Fd()))
+	}
+}
+
+// loadConfig returns settings of the repository, or only user settings when repo is nil. Global --color flag
+// overrides settings.
+func loadConfig(cmd *cobra.Command, repo *repository.Repository) (*config.Config, error) {
+	var repoFiles []string
+	if repo != nil {
+		repoFiles = repo.ConfigFiles()
+	}
+	cfg, err := config.Load(repoFiles...)
+	if err != nil {
+		return nil, err
+	}
+	if flag := cmd.Flags().Lookup("color"); flag != nil && flag.Changed {
+		cfg.SetFlag("color", flag.Value.String())
+	}
+	return cfg, nil
+}
+
+// selectRepo makes commands use the repository given in --repo flag instead of the working directory
+func selectRepo(cmd *cobra.Command) error {
+	name, _ := cmd.Flags().GetString("repo")
+	if name == "" {
+		return nil
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	r, err := cfg.Repo(name)
+	if err != nil {
+		return err
+	}
+	namedRepoDir = r.Dir
+	return nil
+}
+
+// optionalRepository returns repository for the working directory or nil outside a repository
+func optionalRepository() (*repository.Repository, error) {
+	repo, err := workingDirRepository()
+	var e repositoryError
+	if errors.As(err, &e) && e.IsNotRepository() {
+		return nil, nil
+	}
+	return repo, err
+}
+
+func configCmd() *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Get and set settings",
+		Long: `Get and set settings. Each setting is taken from the first place where it is given: command line flag,
+NOTEO_* environment variable (e.g. NOTEO_LS_OUTPUT for ls.output), repository config (.noteo.yml), user config
+($XDG_CONFIG_HOME/noteo/config.yml) or default value.
+
+Settings:
+` + settingsUsage(),
+	}
+	configCmd.AddCommand(configGet())
+	configCmd.AddCommand(configSet())
+	configCmd.AddCommand(configList())
+	return configCmd
+}
+
+func settingsUsage() string {
+	var usage strings.Builder
+	for _, s := range config.Settings {
+		line := fmt.Sprintf("  %-12s %s", s.Key, s.Usage)
+		if len(s.Values) > 0 {
+			line += ": " + strings.Join(s.Values, ", ")
+		}
+		if s.Default != "" {
+			line += fmt.Sprintf(" (default %q)", s.Default)
+		}
+		usage.WriteString(line + "\n")
+	}
+	return usage.String()
+}
+
+func configGet() *cobra.Command {
+	var showOrigin bool
+	get := &cobra.Command{
+		Use:   "get KEY",
+		Short: "Print value of the setting",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadWorkingDirConfig(cmd)
+			if err != nil {
+				return err
+			}
+			value, err := cfg.Get(args[0])
+			if err != nil {
+				return err
+			}
+			printValue(value.Value, value, showOrigin)
+			return nil
+		},
+	}
+	get.Flags().BoolVar(&showOrigin, "show-origin", false, "print where the value comes from")
+	return get
+}
+
+func configList() *cobra.Command {
+	var showOrigin bool
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "Print all settings",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadWorkingDirConfig(cmd)
+			if err != nil {
+				return err
+			}
+			for _, value := range cfg.List() {
+				printValue(value.Key+"="+value.Value, value, showOrigin)
+			}
+			return nil
+		},
+	}
+	list.Flags().BoolVar(&showOrigin, "show-origin", false, "print where each value comes from")
+	return list
+}
+
+func configSet() *cobra.Command {
+	var user bool
+	set := &cobra.Command{
+		Use:   "set KEY VALUE",
+		Short: "Save the setting in repository config or user config",
+		Example: `
+  # Use JSON as a default output of ls in the current repository
+  noteo config set ls.output json
+
+  # Use nano as an editor in all repositories
+  noteo config set --user editor nano`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, err := configFile(user)
+			if err != nil {
+				return err
+			}
+			return config.Set(file, args[0], args[1])
+		},
+	}
+	set.Flags().BoolVar(&user, "user", false, "save in user config instead of repository config")
+	return set
+}
+
+// configFile returns user config or config of the repository in the working directory
+func configFile(user bool) (string, error) {
+	if user {
+		return config.UserFile()
+	}
+	repo, err := workingDirRepository()
+	if err != nil {
+		return "", err
+	}
+	return repo.ConfigFile(), nil
+}
+
+func loadWorkingDirConfig(cmd *cobra.Command) (*config.Config, error) {
+	repo, err := optionalRepository()
+	if err != nil {
+		return nil, err
+	}
+	return loadConfig(cmd, repo)
+}
+
+// printValue prints text optionally preceded by the origin of the value, such as repo:/notes/.noteo.yml
+func printValue(text string, value config.Value, showOrigin bool) {
+	if !showOrigin {
+		fmt.Println(text)
+		return
+	}
+	origin := string(value.Origin)
+	if value.Source != "" {
+		origin += ":" + value.Source
+	}
+	fmt.Printf("%s\t%s\n", origin, text)
+}
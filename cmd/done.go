@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func doneCmd() *cobra.Command {
+	var strict bool
+	done := &cobra.Command{
+		Use:   "done FILE...",
+		Short: "Mark notes as done",
+		Long: `Mark notes as done.
+
+Dates of recurring notes (having a repeat tag such as repeat:weekly, repeat:monthly or repeat:2w) are moved to the
+next occurrence. Other notes are tagged with "done" and no longer listed by agenda.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, err := workingDirRepository()
+			if err != nil {
+				return err
+			}
+			errs := newNoteErrors(cmd.ErrOrStderr(), strict)
+			printer := NewPrinter()
+			for _, file := range args {
+				tags, err := repo.Done(file)
+				if err != nil {
+					if !errs.report(err, "skipping:") {
+						break
+					}
+					continue
+				}
+				printer.PrintFile(file)
+				printer.Print(" updated:")
+				for _, t := range tags {
+					printer.Print(" ", t.String())
+				}
+				printer.Println()
+			}
+			return errs.err()
+		},
+	}
+	done.Flags().BoolVar(&strict, "strict", false, "stop on first note which could not be updated")
+	return done
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/elgopher/noteo/date"
+	"github.com/elgopher/noteo/note"
+)
+
+// Exit codes returned by noteo
+const (
+	exitFatal     = 1 // command failed, e.g. because of invalid flag or missing repository
+	exitPartial   = 2 // command finished, but some notes could not be processed
+	exitNoMatches = 3 // command finished without errors, but no notes matched
+	exitCheck     = 4 // check found problems, e.g. notes which are not formatted
+)
+
+const exitCodesUsage = `Exit status:
+  0  success
+  1  fatal error, e.g. invalid flag or not a noteo repository
+  2  some notes could not be processed (partial errors)
+  3  no notes matched
+  4  check found problems
+`
+
+// exitError makes Execute exit with given code. Message is printed to stderr unless it is empty.
+type exitError struct {
+	code    int
+	message string
+}
+
+func (e exitError) Error() string {
+	return e.message
+}
+
+// noteErrors prints per-note errors to stderr as they occur and counts them by kind
+type noteErrors struct {
+	out    io.Writer
+	strict bool
+	counts map[string]int
+	total  int
+}
+
+func newNoteErrors(out io.Writer, strict bool) *noteErrors {
+	return &noteErrors{out: out, strict: strict, counts: map[string]int{}}
+}
+
+// report prints the error and returns false when command should stop, which is when --strict flag was used
+func (e *noteErrors) report(err error, prefix ...string) bool {
+	e.total++
+	e.counts[errorKind(err)]++
+	if len(prefix) > 0 {
+		_, _ = fmt.Fprintln(e.out, strings.Join(prefix, " "), err)
+	} else {
+		_, _ = fmt.Fprintln(e.out, err)
+	}
+	return !e.strict
+}
+
+// err returns exitError with a summary when any error was reported, otherwise nil
+func (e *noteErrors) err() error {
+	if e.total == 0 {
+		return nil
+	}
+	if e.strict {
+		return exitError{code: exitPartial, message: "stopped on first error because of --strict flag"}
+	}
+	kinds := make([]string, 0, len(e.counts))
+	for kind, count := range e.counts {
+		kinds = append(kinds, fmt.Sprintf("%d %s", count, kind))
+	}
+	sort.Strings(kinds)
+	errorsWord := "errors"
+	if e.total == 1 {
+		errorsWord = "error"
+	}
+	return exitError{
+		code:    exitPartial,
+		message: fmt.Sprintf("%d %s: %s", e.total, errorsWord, strings.Join(kinds, ", ")),
+	}
+}
+
+func errorKind(err error) string {
+	var (
+		parseError *time.ParseError
+		pathError  *fs.PathError
+	)
+	switch {
+	case errors.Is(err, note.ErrInvalidFrontMatter):
+		return "invalid front matter"
+	case errors.Is(err, date.ErrUnsupportedDate), errors.As(err, &parseError):
+		return "invalid date"
+	case errors.As(err, &pathError):
+		return "unreadable file"
+	default:
+		return "other"
+	}
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elgopher/noteo/note"
+)
+
+func fmtCmd() *cobra.Command {
+	var (
+		check  bool
+		strict bool
+	)
+	fmtCmd := &cobra.Command{
+		Use:   "fmt",
+		Short: "Rewrite front matter of all notes to canonical form defined in .noteo.yml",
+		Example: `
+  # Format all notes
+  noteo fmt
+
+  # List notes which are not formatted, without changing them (exits with 4 if any)
+  noteo fmt --check`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, err := workingDirRepository()
+			if err != nil {
+				return err
+			}
+			repoConfig, err := repo.Config()
+			if err != nil {
+				return err
+			}
+			format, err := repoConfig.FrontMatterFormat()
+			if err != nil {
+				return err
+			}
+			errs := newNoteErrors(cmd.ErrOrStderr(), strict)
+			printer := NewPrinter()
+			unformatted := 0
+			repo.AllNotesSeq(context.Background())(func(n *note.Note, err error) bool {
+				if err != nil {
+					return errs.report(err)
+				}
+				if err = n.Format(format); err != nil {
+					return errs.report(err)
+				}
+				if check {
+					changed, err := n.Changed()
+					if err != nil {
+						return errs.report(err)
+					}
+					if changed {
+						unformatted++
+						printer.PrintFile(n.Path())
+						printer.Println()
+					}
+					return true
+				}
+				saved, err := n.Save()
+				if err != nil {
+					return errs.report(err)
+				}
+				if saved {
+					printer.PrintFile(n.Path())
+					printer.Println(" formatted")
+				}
+				return true
+			})
+			if err = errs.err(); err != nil {
+				return err
+			}
+			if unformatted > 0 {
+				return exitError{code: exitCheck, message: fmt.Sprintf("%d notes are not formatted", unformatted)}
+			}
+			return nil
+		},
+	}
+	fmtCmd.Flags().BoolVar(&check, "check", false, "list notes which are not formatted and exit with 4 if any, without changing them")
+	fmtCmd.Flags().BoolVar(&strict, "strict", false, "stop on first note which could not be formatted")
+	return fmtCmd
+}
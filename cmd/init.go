@@ -11,7 +11,11 @@ var initialize = &cobra.Command{
 	Use:   "init",
 	Short: "Initialize a Noteo repository",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfgFile, err := repository.Init(".")
+		dir := "."
+		if namedRepoDir != "" {
+			dir = namedRepoDir
+		}
+		cfgFile, err := repository.Init(dir)
 		if err != nil {
 			return err
 		}
@@ -2,57 +2,42 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"math"
-	"regexp"
+	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
-	"github.com/elgopher/noteo/date"
+	"github.com/elgopher/noteo/config"
+	"github.com/elgopher/noteo/listing"
+	"github.com/elgopher/noteo/note"
 	"github.com/elgopher/noteo/notes"
-	"github.com/elgopher/noteo/output/jayson"
-	"github.com/elgopher/noteo/output/quiet"
-	"github.com/elgopher/noteo/output/table"
-	"github.com/elgopher/noteo/output/yml"
+	"github.com/elgopher/noteo/repository"
+	"github.com/elgopher/noteo/seq"
+	noteotag "github.com/elgopher/noteo/tag"
 	"github.com/spf13/cobra"
 )
 
-type formatter interface {
-	Header() string
-	Note(note notes.Note) string
-	Footer() string
-}
-
 type lsCommand struct {
 	// projection
 	quietMode    bool
 	outputFormat string
 	date         string
+	timezone     string
+	watch        bool
+	strict       bool
+	allRepos     bool
 	// filtering
-	tagFilter      []string
-	notagFilter    []string
-	tagGrep        []string
-	tagGreater     []string
-	tagLower       []string
-	tagAfter       []string
-	tagBefore      []string
-	noTags         bool
-	modifiedAfter  string
-	modifiedBefore string
-	createdAfter   string
-	createdBefore  string
-	grep           string
+	listing.Filter
 	// sorting and limiting
-	limit           int
-	sortByCreated   bool
-	sortByTagDate   string
-	sortByTagNumber string
-	reverse         bool
+	listing.Sorting
 }
 
 func ls() *cobra.Command {
 	c := &lsCommand{}
 	ls := &cobra.Command{
-		Use:   "ls",
+		Use:   "ls [DIR | @VIEW]",
 		Short: "List notes summary",
 		Args:  cobra.RangeArgs(0, 1),
 		RunE:  c.RunE,
@@ -72,35 +57,58 @@ func ls() *cobra.Command {
   # List notes with tag name "priority" and value greater than 1
   noteo ls --tag-greater priority:1
 
+  # List notes with tag name "estimate" and duration value greater than 2 hours
+  noteo ls --tag-greater estimate:2h
+
+  # List notes with tag name "status" and value "open" or "blocked"
+  noteo ls --tag-in status:open,blocked
+
+  # List notes with tag name "priority" and value from 1 to 3 (inclusive)
+  noteo ls --tag-range priority:1..3
+
+  # List notes with tag name "deadline" and value from today to tomorrow (inclusive)
+  noteo ls --tag-between deadline:today..tomorrow
+
   # List notes with tag name "deadline" and value (which is a date) after 2020-08-30, sorted by date taken from this tag
   noteo ls --tag-after deadline:2020-08-30 --sort-by-tag-date deadline
 
   # List specific columns
-  noteo ls -o table=file,tags`,
+  noteo ls -o table=file,tags
+
+  # List file, title limited to 60 characters and value of deadline tag, such as "in 3 days"
+  noteo ls -o table=file,title:60,tag:deadline
+
+  # List file names with priority using Go template
+  noteo ls -o template='{{.File}}\t{{.Tag "priority"}}'
+
+  # List file names with the first tag using JSONPath
+  noteo ls -o jsonpath='{.file}{"\t"}{.tags[0]}'
+
+  # Show dates using custom format in UTC
+  noteo ls --date format:%Y-%m-%d --tz UTC
+
+  # List notes tagged "todo" in all repositories registered in user config
+  noteo ls --all-repos -t todo
+
+  # List notes using inbox view defined in .noteo.yml, limited to notes tagged "work"
+  noteo ls @inbox -t work`,
 	}
+	c.addLsFlags(ls)
+	return ls
+}
+
+// addLsFlags adds flags and usage of ls to the command
+func (c *lsCommand) addLsFlags(ls *cobra.Command) {
 	ls.Flags().BoolVarP(&c.quietMode, "quiet", "q", false, "")
-	ls.Flags().StringVarP(&c.outputFormat, "output", "o", "table=file,beginning,modified,tags", "")
+	ls.Flags().StringVarP(&c.outputFormat, "output", "o", "", "")
 	ls.Flags().StringVar(&c.date, "date", "", "")
+	ls.Flags().StringVar(&c.timezone, "tz", "", "")
+	ls.Flags().BoolVarP(&c.watch, "watch", "w", false, "")
+	ls.Flags().BoolVar(&c.strict, "strict", false, "")
+	ls.Flags().BoolVar(&c.allRepos, "all-repos", false, "")
 	// filtering
-	ls.Flags().StringArrayVarP(&c.tagFilter, "tag", "t", nil, "")
-	ls.Flags().StringArrayVar(&c.notagFilter, "no-tag", nil, "")
-	ls.Flags().StringArrayVar(&c.tagGrep, "tag-grep", nil, "")
-	ls.Flags().StringArrayVar(&c.tagGreater, "tag-greater", nil, "")
-	ls.Flags().StringArrayVar(&c.tagLower, "tag-lower", nil, "")
-	ls.Flags().StringArrayVar(&c.tagAfter, "tag-after", nil, "")
-	ls.Flags().StringArrayVar(&c.tagBefore, "tag-before", nil, "")
-	ls.Flags().BoolVar(&c.noTags, "no-tags", false, "")
-	ls.Flags().StringVar(&c.modifiedAfter, "modified-after", "", "")
-	ls.Flags().StringVar(&c.modifiedBefore, "modified-before", "", "")
-	ls.Flags().StringVar(&c.createdAfter, "created-after", "", "")
-	ls.Flags().StringVar(&c.createdBefore, "created-before", "", "")
-	ls.Flags().StringVar(&c.grep, "grep", "", "")
-	// sorting and limiting
-	ls.Flags().IntVarP(&c.limit, "limit", "l", math.MaxInt32, "")
-	ls.Flags().BoolVar(&c.sortByCreated, "sort-by-created", false, "")
-	ls.Flags().StringVarP(&c.sortByTagDate, "sort-by-tag-date", "", "", "")
-	ls.Flags().StringVarP(&c.sortByTagNumber, "sort-by-tag-number", "", "", "")
-	ls.Flags().BoolVar(&c.reverse, "reverse", false, "")
+	c.Filter.AddFlags(ls.Flags())
+	c.Sorting.AddFlags(ls.Flags())
 	ls.SetUsageTemplate(`Usage:{{if .Runnable}}
   {{.UseLine}}{{end}}{{if .HasAvailableSubCommands}}
   {{.CommandPath}} [command]{{end}}{{if gt (len .Aliases) 0}}
@@ -115,33 +123,31 @@ Available Commands:{{range .Commands}}{{if (or .IsAvailableCommand (eq .Name "he
   {{rpad .Name .NamePadding }} {{.Short}}{{end}}{{end}}{{end}}
 
 Filtering flags:
-      --created-after <date>        filter notes created after given date
-      --created-before <date>       filter notes created before given date
-      --grep <regex>                grep text using regular expression
-      --modified-after <date>       filter notes modified after given date
-      --modified-before <date>      filter notes modified before given date
-      --no-tag <name>               filter notes not having tag. Flag can be specified multiple times.
-      --no-tags                     filter notes not having tags at all
-  -t, --tag <name>                  filter notes having tag. Flag can be specified multiple times.
-      --tag-after <name:date>       filter notes having tag with value date after specified date, e.g. "foo:2010-08-01". Flag can be specified multiple times.
-      --tag-before <name:date>      filter notes having tag with value date before specified date, e.g. "foo:2010-08-01". Flag can be specified multiple times.
-      --tag-greater <name:number>   filter notes having tag with value number greater than specified number e.g. "foo:2". Flag can be specified multiple times.
-      --tag-grep <regex>            filter notes having tag matching regular expression. Flag can be specified multiple times.
-      --tag-lower <name:number>     filter notes having tag with value number greater than specified number e.g. "foo:2". Flag can be specified multiple times.
-
+` + listing.FilteringFlagsUsage + `
 Sorting and limiting flags:
   -l, --limit int                   limits number of notes returned (default 2147483647)
       --reverse                     makes sorting ascending
       --sort-by-created             sorts by created date descending
       --sort-by-tag-date <name>     sorts by date given in a tag with name descending
-      --sort-by-tag-number <name>   sorts by number given in a tag with name descending
+      --sort-by-tag-number <name>   sorts by number, duration or date given in a tag with name descending
 
 Other flags:
-      --date string                 shows dates in given format: relative (default), iso8601 or rfc2822. For now used in table and wide output only.
+      --all-repos                   lists notes of all repositories registered in user config, with repo column
+      --date string                 shows dates in given format: relative (default), iso8601, rfc2822 or custom format:<layout>,
+                                    where layout is strftime pattern (format:%Y-%m-%d) or Go layout (format:2006-01-02).
+                                    Default can be set using date-format setting.
   -h, --help                        help for ls
-  -o, --output string               Specify output format: table using given columns, wide, json or yaml
-                                    (default "table=file,beginning,modified,tags")
-  -q, --quiet                       Show only file names{{if .HasAvailableInheritedFlags}}
+  -o, --output string               Specify output format: table, table using given columns (e.g. table=file,tags),
+                                    where columns are repo, file, dir, title, beginning, modified, created, tags, size,
+                                    words, links, backlinks, tag:<name> and field:<key>, optionally followed by width
+                                    (e.g. beginning:60),
+                                    wide, json, yaml, template=<go template>, template-file=<path>,
+                                    jsonpath=<template> or jsonpath-file=<path>. Default can be set using ls.output
+                                    and ls.columns settings (default "table=file,beginning,modified,tags")
+  -q, --quiet                       Show only file names
+      --strict                      stops on first note which could not be read or filtered
+      --tz string                   shows dates in given IANA time zone, e.g. Europe/Warsaw. Default can be set using timezone setting.
+  -w, --watch                       after listing notes, watch for changes and list notes again{{if .HasAvailableInheritedFlags}}
 Global Flags:
 {{.InheritedFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}{{if .HasHelpSubCommands}}
 
@@ -149,272 +155,275 @@ Additional help topics:{{range .Commands}}{{if .IsAdditionalHelpTopicCommand}}
   {{rpad .CommandPath .CommandPathPadding}} {{.Short}}{{end}}{{end}}{{end}}{{if .HasAvailableSubCommands}}
 
 Use "{{.CommandPath}} [command] --help" for more information about a command.{{end}}
-`)
+
+` + exitCodesUsage)
 	ls.Flags().Usage = func() {
 
 	}
-	return ls
 }
 
 func (c *lsCommand) RunE(cmd *cobra.Command, args []string) error {
-	repo, err := repo(args)
+	if len(args) == 1 && strings.HasPrefix(args[0], "@") {
+		return c.runView(cmd, strings.TrimPrefix(args[0], "@"))
+	}
+	if c.allRepos && (c.watch || len(args) > 0) {
+		return errors.New("--all-repos cannot be used with --watch or a directory")
+	}
+	repo, err := c.repository(args)
 	if err != nil {
 		return err
 	}
-
-	ctx := context.Background()
-	dirNotes, notesErrors := repo.Notes(ctx)
-
-	predicates, err := c.filterPredicates()
+	cfg, err := c.config(cmd, repo)
 	if err != nil {
 		return err
 	}
-	filtered, filterErrors := notes.Filter(ctx, toNotes(dirNotes), predicates...)
-	sortedNotes, topErrors := notes.Top(ctx, c.limit, filtered, c.sort())
-
-	printErrors(ctx, notesErrors, filterErrors, topErrors)
-
-	out, err := c.formatter()
+	errs := newNoteErrors(cmd.ErrOrStderr(), c.strict)
+	listed, err := c.list(repo, cfg, errs)
 	if err != nil {
 		return err
 	}
-	fmt.Print(out.Header())
-	for note := range sortedNotes {
-		fmt.Print(out.Note(note))
+	if c.watch {
+		return c.listOnChange(cmd, repo, cfg)
+	}
+	if err = errs.err(); err != nil {
+		return err
+	}
+	if listed == 0 {
+		return exitError{code: exitNoMatches}
 	}
-	fmt.Print(out.Footer())
 	return nil
 }
 
-func (c *lsCommand) filterPredicates() ([]notes.Predicate, error) {
-	var predicates []notes.Predicate
-	for _, createPredicates := range []func() ([]notes.Predicate, error){
-		c.tagFilterPredicates,
-		c.notagFilterPredicates,
-		c.tagGrepPredicates,
-		c.tagGreaterPredicates,
-		c.tagLowerPredicates,
-		c.tagAfterPredicates,
-		c.tagBeforePredicates,
-		c.notagsPredicates,
-		c.modifiedAfterPredicates,
-		c.modifiedBeforePredicates,
-		c.createdAfterPredicates,
-		c.createdBeforePredicates,
-		c.grepPredicates,
-	} {
-		p, err := createPredicates()
-		if err != nil {
-			return nil, err
-		}
-		predicates = append(predicates, p...)
+// repository returns repository of the directory given in args. With --all-repos it returns repository of the
+// working directory, which settings are used, or nil outside a repository.
+func (c *lsCommand) repository(args []string) (*repository.Repository, error) {
+	if c.allRepos {
+		return optionalRepository()
 	}
-	return predicates, nil
+	return repo(args)
 }
 
-func (c *lsCommand) tagFilterPredicates() ([]notes.Predicate, error) {
-	var predicates []notes.Predicate
-	for _, t := range c.tagFilter {
-		predicates = append(predicates, notes.Tag(t))
+// config returns settings overridden by --output, --date and --tz flags
+func (c *lsCommand) config(cmd *cobra.Command, repo *repository.Repository) (*config.Config, error) {
+	cfg, err := loadConfig(cmd, repo)
+	if err != nil {
+		return nil, err
 	}
-	return predicates, nil
+	c.overrideSettings(cfg)
+	return cfg, nil
 }
 
-func (c *lsCommand) notagFilterPredicates() ([]notes.Predicate, error) {
-	var predicates []notes.Predicate
-	for _, t := range c.notagFilter {
-		predicates = append(predicates, notes.NoTag(t))
+func (c *lsCommand) overrideSettings(cfg *config.Config) {
+	if c.outputFormat != "" {
+		cfg.SetFlag("ls.output", c.outputFormat)
+	}
+	if c.date != "" {
+		cfg.SetFlag("date-format", c.date)
+	}
+	if c.timezone != "" {
+		cfg.SetFlag("timezone", c.timezone)
 	}
-	return predicates, nil
 }
 
-func (c *lsCommand) tagGrepPredicates() ([]notes.Predicate, error) {
-	var predicates []notes.Predicate
-	for _, grep := range c.tagGrep {
-		regex, err := regexp.Compile(grep)
-		if err != nil {
-			return nil, err
-		}
-		predicates = append(predicates, notes.TagGrep(regex))
+// list prints notes and returns how many were printed. Per-note errors are reported to errs.
+func (c *lsCommand) list(repo *repository.Repository, cfg *config.Config, errs *noteErrors) (int, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	vocabulary, err := vocabularyOf(repo)
+	if err != nil {
+		return 0, err
 	}
-	return predicates, nil
-}
+	predicates, err := c.Predicates(vocabulary)
+	if err != nil {
+		return 0, err
+	}
+	source, err := c.notesSeq(ctx, repo, cfg)
+	if err != nil {
+		return 0, err
+	}
+	sortedNotes := notes.TopSeq(c.Limit, notes.FilterSeq(source, predicates...), c.Less())
 
-func (c *lsCommand) tagGreaterPredicates() ([]notes.Predicate, error) {
-	var predicates []notes.Predicate
-	for _, greater := range c.tagGreater {
-		p, err := notes.TagGreater(greater)
+	out, err := c.formatter(cfg)
+	if err != nil {
+		return 0, err
+	}
+	listed := 0
+	fmt.Print(out.Header())
+	sortedNotes(func(note notes.Note, err error) bool {
 		if err != nil {
-			return nil, err
+			return errs.report(err)
 		}
-		predicates = append(predicates, p)
-	}
-	return predicates, nil
+		listed++
+		fmt.Print(out.Note(note))
+		if n, ok := note.(listedNote); ok {
+			n.ReleaseBody()
+		}
+		return true
+	})
+	fmt.Print(out.Footer())
+	return listed, nil
 }
 
-func (c *lsCommand) tagLowerPredicates() ([]notes.Predicate, error) {
-	var predicates []notes.Predicate
-	for _, lower := range c.tagLower {
-		p, err := notes.TagLower(lower)
-		if err != nil {
-			return nil, err
-		}
-		predicates = append(predicates, p)
+// notesSeq returns notes of the repository or, with --all-repos, notes of all repositories registered in user config
+func (c *lsCommand) notesSeq(ctx context.Context, repo *repository.Repository,
+	cfg *config.Config) (seq.Seq[notes.Note], error) {
+	if !c.allRepos {
+		return listedNotesSeq(repo.NotesSeq(ctx), "", &backlinks{repo: repo}), nil
 	}
-	return predicates, nil
+	repos, err := cfg.Repos()
+	if err != nil {
+		return nil, err
+	}
+	if len(repos) == 0 {
+		userFile, _ := config.UserFile()
+		return nil, fmt.Errorf("no repositories registered in %s, e.g. repos: {work: ~/notes/work}", userFile)
+	}
+	seqs := make([]seq.Seq[notes.Note], len(repos))
+	listed := map[string]bool{} // absolute paths of files, which can be in many repositories, e.g. registered twice
+	for i, r := range repos {
+		seqs[i] = namedRepoNotesSeq(ctx, r, listed)
+	}
+	return seq.Concat(seqs...), nil
 }
 
-func (c *lsCommand) tagAfterPredicates() ([]notes.Predicate, error) {
-	var predicates []notes.Predicate
-	for _, after := range c.tagAfter {
-		p, err := notes.TagAfter(after)
-		if err != nil {
-			return nil, err
+// namedRepoNotesSeq yields notes of the repository which are not in listed yet
+func namedRepoNotesSeq(ctx context.Context, r config.Repo, listed map[string]bool) seq.Seq[notes.Note] {
+	named, err := repository.ForWorkDir(r.Dir)
+	if err != nil {
+		return func(yield func(notes.Note, error) bool) {
+			yield(nil, fmt.Errorf("repository %s: %w", r.Name, err))
 		}
-		predicates = append(predicates, p)
 	}
-	return predicates, nil
+	all := named.NotesSeq(ctx)
+	unique := func(yield func(*note.Note, error) bool) {
+		all(func(n *note.Note, err error) bool {
+			if err != nil {
+				return yield(nil, err)
+			}
+			file, err := filepath.Abs(filepath.Join(named.WorkDir(), n.Path()))
+			if err != nil {
+				return yield(nil, err)
+			}
+			if listed[file] {
+				return true
+			}
+			listed[file] = true
+			return yield(n, nil)
+		})
+	}
+	return listedNotesSeq(unique, r.Name, &backlinks{repo: named})
 }
 
-func (c *lsCommand) tagBeforePredicates() ([]notes.Predicate, error) {
-	var predicates []notes.Predicate
-	for _, before := range c.tagBefore {
-		p, err := notes.TagBefore(before)
-		if err != nil {
-			return nil, err
-		}
-		predicates = append(predicates, p)
-	}
-	return predicates, nil
+func listedNotesSeq(all seq.Seq[*note.Note], repo string, b *backlinks) seq.Seq[notes.Note] {
+	return seq.Map(all, func(n *note.Note) notes.Note {
+		return listedNote{Note: n, repo: repo, backlinks: b}
+	})
 }
 
-func (c *lsCommand) notagsPredicates() ([]notes.Predicate, error) {
-	var predicates []notes.Predicate
-	if c.noTags {
-		predicates = append(predicates, notes.NoTags())
-	}
-	return predicates, nil
+// listedNote is a note with data shown by ls which is not stored in the note itself
+type listedNote struct {
+	*note.Note
+	repo      string // empty unless notes of all repositories are listed
+	backlinks *backlinks
 }
 
-func (c *lsCommand) modifiedAfterPredicates() ([]notes.Predicate, error) {
-	var predicates []notes.Predicate
-	if c.modifiedAfter != "" {
-		p, err := notes.ModifiedAfter(c.modifiedAfter)
-		if err != nil {
-			return nil, err
-		}
-		predicates = append(predicates, p)
-	}
-	return predicates, nil
+func (n listedNote) Repo() string {
+	return n.repo
 }
 
-func (c *lsCommand) modifiedBeforePredicates() ([]notes.Predicate, error) {
-	var predicates []notes.Predicate
-	if c.modifiedBefore != "" {
-		p, err := notes.ModifiedBefore(c.modifiedBefore)
-		if err != nil {
-			return nil, err
-		}
-		predicates = append(predicates, p)
-	}
-	return predicates, nil
+func (n listedNote) Backlinks() int {
+	return n.backlinks.count(n.Path())
 }
 
-func (c *lsCommand) createdAfterPredicates() ([]notes.Predicate, error) {
-	var predicates []notes.Predicate
-	if c.createdAfter != "" {
-		p, err := notes.CreatedAfter(c.createdAfter)
-		if err != nil {
-			return nil, err
-		}
-		predicates = append(predicates, p)
-	}
-	return predicates, nil
+// backlinks counts notes of the repository linking to each file. Notes are read when the first count is needed,
+// i.e. only when backlinks column is listed.
+type backlinks struct {
+	repo   *repository.Repository
+	once   sync.Once
+	counts map[string]int
 }
 
-func (c *lsCommand) createdBeforePredicates() ([]notes.Predicate, error) {
-	var predicates []notes.Predicate
-	if c.createdBefore != "" {
-		p, err := notes.CreatedBefore(c.createdBefore)
-		if err != nil {
-			return nil, err
-		}
-		predicates = append(predicates, p)
-	}
-	return predicates, nil
+func (b *backlinks) count(path string) int {
+	b.once.Do(func() {
+		b.counts = map[string]int{}
+		b.repo.AllNotesSeq(context.Background())(func(n *note.Note, err error) bool {
+			if err != nil {
+				return true // errors are reported when notes are listed
+			}
+			links, _ := n.Links()
+			counted := map[string]bool{}
+			for _, link := range links {
+				link = b.absolute(link)
+				if !counted[link] && link != b.absolute(n.Path()) {
+					counted[link] = true
+					b.counts[link]++
+				}
+			}
+			n.ReleaseBody()
+			return true
+		})
+	})
+	return b.counts[b.absolute(path)]
 }
 
-func (c *lsCommand) grepPredicates() ([]notes.Predicate, error) {
-	var predicates []notes.Predicate
-	if c.grep != "" {
-		p, err := notes.Grep(c.grep)
-		if err != nil {
-			return nil, err
-		}
-		predicates = append(predicates, p)
+// absolute returns path, which is relative to the working directory, as an absolute one, so paths such as
+// ../dir/a.md and a.md can be compared
+func (b *backlinks) absolute(path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
 	}
-	return predicates, nil
+	return filepath.Join(b.repo.WorkDir(), path)
 }
 
-func (c *lsCommand) sort() notes.Less {
-	sort := notes.ModifiedDesc
-	if c.reverse {
-		sort = notes.ModifiedAsc
-	}
-	switch {
-	case c.sortByCreated && !c.reverse:
-		sort = notes.CreatedDesc
-	case c.sortByCreated && c.reverse:
-		sort = notes.CreatedAsc
-	case c.sortByTagDate != "" && !c.reverse:
-		sort = notes.TagDateDesc(c.sortByTagDate)
-	case c.sortByTagDate != "" && c.reverse:
-		sort = notes.TagDateAsc(c.sortByTagDate)
-	case c.sortByTagNumber != "" && !c.reverse:
-		sort = notes.TagNumberDesc(c.sortByTagNumber)
-	case c.sortByTagNumber != "" && c.reverse:
-		sort = notes.TagNumberAsc(c.sortByTagNumber)
+// listOnChange clears the screen and lists notes again each time notes are changed
+func (c *lsCommand) listOnChange(cmd *cobra.Command, repo *repository.Repository, cfg *config.Config) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, errs := repo.Watch(ctx)
+	printErrors(ctx, errs)
+	// editors usually write files a few times in a row, so listing is done once after changes settle down
+	const settleDown = 200 * time.Millisecond
+	timer := time.NewTimer(settleDown)
+	timer.Stop()
+	for {
+		select {
+		case _, ok := <-events:
+			if !ok {
+				return nil
+			}
+			timer.Reset(settleDown)
+		case <-timer.C:
+			fmt.Print(clearScreen)
+			if _, err := c.list(repo, cfg, newNoteErrors(cmd.ErrOrStderr(), c.strict)); err != nil {
+				return err
+			}
+		}
 	}
-	return sort
 }
 
-func (c *lsCommand) dateFormat(defaultFormat date.Format) (date.Format, error) {
-	switch strings.ToLower(c.date) {
-	case "rfc", "rfc2822":
-		return date.RFC2822, nil
-	case "iso", "iso8601":
-		return date.ISO8601, nil
-	case "relative":
-		return date.Relative, nil
-	case "":
-		return defaultFormat, nil
-	default:
-		return "", fmt.Errorf("unsupported date format: %s. Supported formats are: rfc2822 (or rfc), iso8601 (or iso), relative", c.date)
-	}
+const clearScreen = "\033[H\033[2J"
+
+// formatter returns formatter of the output given in settings
+func (c *lsCommand) formatter(cfg *config.Config) (listing.Formatter, error) {
+	return listing.NewFormatter(listing.Output{
+		Format:     cfg.LsOutput(),
+		Columns:    cfg.LsColumns(),
+		DateFormat: cfg.DateFormat(),
+		Timezone:   cfg.Timezone(),
+		Quiet:      c.quietMode,
+		RepoColumn: c.allRepos,
+		Colors:     colorEnabled(),
+	})
 }
 
-func (c *lsCommand) formatter() (formatter, error) {
-	outputFormat := strings.ToLower(c.outputFormat)
-	var err error
-	var out formatter
-	dateFormat, err := c.dateFormat(date.Relative)
+// vocabularyOf returns vocabulary of the repository used to compare tag values. Repository can be nil.
+func vocabularyOf(repo *repository.Repository) (*noteotag.Vocabulary, error) {
+	if repo == nil {
+		return nil, nil
+	}
+	config, err := repo.Config()
 	if err != nil {
 		return nil, err
 	}
-	switch {
-	case c.quietMode:
-		out = quiet.Formatter{}
-	case outputFormat == "wide":
-		out, err = table.NewFormatter([]string{"file", "beginning", "modified", "created", "tags"}, dateFormat)
-	case strings.HasPrefix(outputFormat, "table="):
-		columns := strings.Split(strings.TrimPrefix(outputFormat, "table="), ",")
-		out, err = table.NewFormatter(columns, dateFormat)
-	case outputFormat == "json":
-		out = jayson.Formatter{}
-	case outputFormat == "yaml":
-		out = yml.Formatter{}
-	default:
-		err = fmt.Errorf("unsupported output format in --output flag: %s", outputFormat)
-	}
-	return out, err
+	return config.Vocabulary()
 }
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elgopher/noteo/lsp"
+)
+
+var lspCmd = &cobra.Command{
+	Use:   "lsp",
+	Short: "Start Language Server Protocol server on stdio",
+	Long: `Start Language Server Protocol server for notes in a current working directory. The server communicates using stdin and stdout.
+
+Server provides:
+  * completion of tags in the front matter Tags and of note paths in Markdown links
+  * go to definition of a link
+  * find references - links to the note from other notes
+  * rename - moves the note and updates links in other notes
+  * diagnostics for invalid tags and broken links`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repo, err := workingDirRepository()
+		if err != nil {
+			return err
+		}
+		return lsp.New(repo).Serve(context.Background(), os.Stdin, os.Stdout)
+	},
+}
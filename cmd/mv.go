@@ -5,39 +5,37 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
+
+	"github.com/elgopher/noteo/note"
 )
 
-// mv represents the mv command
-var mv = &cobra.Command{
-	Use:   "mv",
-	Args:  cobra.ExactArgs(2),
-	Short: "Move note - EXPERIMENTAL (update links if necessary)",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		repo, err := workingDirRepository()
-		if err != nil {
-			return err
-		}
-		ctx := context.Background()
-		updated, success, errors := repo.Move(ctx, args[0], args[1])
-		printErrors(ctx, errors)
-		printer := NewPrinter()
-		for {
-			select {
-			case note, ok := <-updated:
-				if ok {
-					printer.PrintFile(note.Path())
-					printer.Println(" updated")
-				}
-			case succ, ok := <-success:
-				if !ok {
-					return nil
-				}
-				if succ {
-					printer.Println("File moved")
-				} else {
-					return fmt.Errorf("move failed")
-				}
+func mvCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "mv",
+		Args:  cobra.ExactArgs(2),
+		Short: "Move note - EXPERIMENTAL (update links if necessary)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, err := workingDirRepository()
+			if err != nil {
+				return err
+			}
+			updated, err := repo.MoveSeq(context.Background(), args[0], args[1])
+			if err != nil {
+				return fmt.Errorf("move failed: %v", err)
 			}
-		}
-	},
+			printer := NewPrinter()
+			printer.Println("File moved")
+			// links are already updated, so there is nothing to stop on error
+			errs := newNoteErrors(cmd.ErrOrStderr(), false)
+			updated(func(note *note.Note, err error) bool {
+				if err != nil {
+					return errs.report(err)
+				}
+				printer.PrintFile(note.Path())
+				printer.Println(" updated")
+				return true
+			})
+			return errs.err()
+		},
+	}
 }
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -11,21 +12,49 @@ func Root() *cobra.Command {
 	root := cobra.Command{
 		Use:           "noteo",
 		Short:         "Command line note-taking assistant",
+		Long:          "Command line note-taking assistant\n\n" + exitCodesUsage,
 		Version:       "0.6.1",
 		SilenceErrors: true,
 		SilenceUsage:  true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := selectRepo(cmd); err != nil {
+				return err
+			}
+			colorMode = resolveColorMode(cmd)
+			return nil
+		},
 	}
+	root.PersistentFlags().String("color", "", "colors in output: auto, always or never")
+	root.PersistentFlags().String("repo", "", "name of the repository registered in user config, used instead of "+
+		"the working directory")
 	root.AddCommand(initialize)
-	root.AddCommand(add)
+	root.AddCommand(addCmd())
 	root.AddCommand(ls())
+	root.AddCommand(viewCmd())
 	root.AddCommand(tag())
-	root.AddCommand(mv)
+	root.AddCommand(mvCmd())
+	root.AddCommand(check())
+	root.AddCommand(fmtCmd())
+	root.AddCommand(agendaCmd())
+	root.AddCommand(doneCmd())
+	root.AddCommand(tasks())
+	root.AddCommand(watch())
+	root.AddCommand(serve())
+	root.AddCommand(lspCmd)
+	root.AddCommand(configCmd())
 	return &root
 }
 
 func Execute() {
 	root := Root()
 	if err := root.Execute(); err != nil {
+		var exit exitError
+		if errors.As(err, &exit) {
+			if exit.message != "" {
+				_, _ = fmt.Fprintln(os.Stderr, exit.message)
+			}
+			os.Exit(exit.code)
+		}
 		if e, ok := err.(repositoryError); ok && e.IsNotRepository() {
 			_, _ = fmt.Fprintln(os.Stderr, "not a noteo repository (or any of the parent directories)")
 			printer := NewPrinter()
@@ -38,7 +67,7 @@ func Execute() {
 				_, _ = fmt.Fprintln(os.Stderr, err)
 			}
 		}
-		os.Exit(1)
+		os.Exit(exitFatal)
 	}
 }
 
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/elgopher/noteo/listing"
+	"github.com/elgopher/noteo/repository"
+	"github.com/elgopher/noteo/server"
+)
+
+func serve() *cobra.Command {
+	var (
+		addr  string
+		token string
+	)
+	serve := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve HTTP/JSON API for notes in a current working directory",
+		Long: `Serve HTTP/JSON API for notes in a current working directory. Server accepts connections only on a loopback address.
+
+Endpoints:
+  GET    /notes                       list notes. Query parameters are the same as ls filtering and sorting flags,
+                                      e.g. /notes?tag=idea&tag-greater=priority:1&sort-by-created&limit=10
+  POST   /notes                       create note. Request body is a note text
+  GET    /notes/{file}                get note
+  PUT    /tags?file={file}&tag={tag}  set tag
+  DELETE /tags?file={file}&tag={tag}  remove tag
+  POST   /move?source={file}&target={file}
+                                      move note and update links
+
+Each request must have "Authorization: Bearer {token}" header. Token can be given in --token flag or NOTEO_TOKEN
+environment variable, otherwise a random token is generated and printed. Requests with Host other than a loopback
+address or with Origin of other site are rejected.`,
+		Args: cobra.NoArgs,
+		Example: `
+  # Serve on port 8375
+  noteo serve --addr 127.0.0.1:8375
+
+  # List notes tagged with "idea"
+  curl -H "Authorization: Bearer $NOTEO_TOKEN" "http://127.0.0.1:8375/notes?tag=idea"`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, err := workingDirRepository()
+			if err != nil {
+				return err
+			}
+			if token == "" {
+				token = os.Getenv("NOTEO_TOKEN")
+			}
+			printer := NewPrinter()
+			if token == "" {
+				if token, err = generateToken(); err != nil {
+					return err
+				}
+				printer.Println("Token: " + token)
+			}
+			printer.Println("Serving notes on http://" + addr)
+			return server.New(repo, lsQuery(repo), token).ListenAndServe(addr)
+		},
+	}
+	serve.Flags().StringVar(&addr, "addr", "127.0.0.1:8375", "loopback address to listen on")
+	serve.Flags().StringVar(&token, "token", "", "token required in Authorization header")
+	return serve
+}
+
+func generateToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// lsQuery returns a function parsing query parameters the same way as ls flags
+func lsQuery(repo *repository.Repository) func(params map[string][]string) (server.Query, error) {
+	return func(params map[string][]string) (server.Query, error) {
+		var filter listing.Filter
+		var sorting listing.Sorting
+		flags := pflag.NewFlagSet("query", pflag.ContinueOnError)
+		filter.AddFlags(flags)
+		sorting.AddFlags(flags)
+		for name, values := range params {
+			flag := flags.Lookup(name)
+			if flag == nil {
+				return server.Query{}, fmt.Errorf("unsupported query parameter: %s", name)
+			}
+			for _, value := range values {
+				if value == "" && flag.Value.Type() == "bool" {
+					value = "true"
+				}
+				if err := flags.Set(name, value); err != nil {
+					return server.Query{}, fmt.Errorf("invalid query parameter %s: %v", name, err)
+				}
+			}
+		}
+		vocabulary, err := vocabularyOf(repo)
+		if err != nil {
+			return server.Query{}, err
+		}
+		predicates, err := filter.Predicates(vocabulary)
+		if err != nil {
+			return server.Query{}, err
+		}
+		return server.Query{Predicates: predicates, Less: sorting.Less(), Limit: sorting.Limit}, nil
+	}
+}
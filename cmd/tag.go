@@ -11,6 +11,6 @@ func tag() *cobra.Command {
 	}
 	tag.AddCommand(tagSet())
 	tag.AddCommand(tagRm())
-	tag.AddCommand(tagLs)
+	tag.AddCommand(tagLs())
 	return tag
 }
@@ -5,23 +5,40 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
+
+	noteotag "github.com/elgopher/noteo/tag"
 )
 
-var tagLs = &cobra.Command{
-	Use:   "ls",
-	Short: "List all tags",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		repo, err := repo(args)
-		if err != nil {
-			return err
-		}
+func tagLs() *cobra.Command {
+	var strict bool
+	tagLs := &cobra.Command{
+		Use:   "ls",
+		Short: "List all tags",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, err := repo(args)
+			if err != nil {
+				return err
+			}
 
-		ctx := context.Background()
-		tags, errs := repo.Tags(ctx)
-		printErrors(ctx, errs)
-		for tag := range tags {
-			fmt.Println(tag)
-		}
-		return nil
-	},
+			errs := newNoteErrors(cmd.ErrOrStderr(), strict)
+			listed := 0
+			repo.TagsSeq(context.Background())(func(t noteotag.Tag, err error) bool {
+				if err != nil {
+					return errs.report(err)
+				}
+				listed++
+				fmt.Println(t)
+				return true
+			})
+			if err = errs.err(); err != nil {
+				return err
+			}
+			if listed == 0 {
+				return exitError{code: exitNoMatches}
+			}
+			return nil
+		},
+	}
+	tagLs.Flags().BoolVar(&strict, "strict", false, "stop on first note which could not be read")
+	return tagLs
 }
@@ -10,9 +10,10 @@ import (
 
 func tagRm() *cobra.Command {
 	var (
-		name  string
-		stdin bool
-		grep  string
+		name   string
+		stdin  bool
+		grep   string
+		strict bool
 	)
 	tagRm := &cobra.Command{
 		Use:   "rm",
@@ -26,32 +27,40 @@ func tagRm() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			removeFileTags := func(file string) {
+			errs := newNoteErrors(cmd.ErrOrStderr(), strict)
+			removeFileTags := func(file string) bool {
 				updated, err := untagFile(file)
 				if err != nil {
-					_, _ = fmt.Fprintln(cmd.ErrOrStderr(), "skipping:", err)
-				} else if updated {
+					return errs.report(err, "skipping:")
+				}
+				if updated {
 					printer := NewPrinter()
 					printer.PrintFile(file)
 					printer.Println(" updated")
 				}
+				return true
 			}
 			if stdin {
 				scanner := bufio.NewScanner(cmd.InOrStdin())
 				scanner.Split(bufio.ScanLines)
 				for scanner.Scan() {
 					file := scanner.Text()
-					removeFileTags(file)
+					if !removeFileTags(file) {
+						break
+					}
 				}
-				return nil
+				return errs.err()
 			}
 			for _, file := range args {
-				removeFileTags(file)
+				if !removeFileTags(file) {
+					break
+				}
 			}
-			return nil
+			return errs.err()
 		},
 	}
 	tagRm.Flags().BoolVarP(&stdin, "stdin", "", false, "read file names from standard input")
+	tagRm.Flags().BoolVar(&strict, "strict", false, "stop on first note which could not be updated")
 	tagRm.Flags().StringVarP(&name, "name", "n", "", "short name without space. Can have form of name:number-or-date")
 	tagRm.Flags().StringVar(&grep, "grep", "", "name regular expression")
 	return tagRm
@@ -9,8 +9,9 @@ import (
 
 func tagSet() *cobra.Command {
 	var (
-		stdin bool
-		name  string
+		stdin  bool
+		name   string
+		strict bool
 	)
 	tagSet := &cobra.Command{
 		Use:     "set",
@@ -24,32 +25,40 @@ func tagSet() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			tagFileWith := func(file, name string) {
+			errs := newNoteErrors(cmd.ErrOrStderr(), strict)
+			tagFileWith := func(file, name string) bool {
 				ok, err := repo.TagFileWith(file, name)
 				if err != nil {
-					_, _ = fmt.Fprintln(cmd.ErrOrStderr(), "skipping:", err)
-				} else if ok {
+					return errs.report(err, "skipping:")
+				}
+				if ok {
 					printer := NewPrinter()
 					printer.PrintFile(file)
 					printer.Println(" updated")
 				}
+				return true
 			}
 			if stdin {
 				scanner := bufio.NewScanner(cmd.InOrStdin())
 				scanner.Split(bufio.ScanLines)
 				for scanner.Scan() {
 					file := scanner.Text()
-					tagFileWith(file, name)
+					if !tagFileWith(file, name) {
+						break
+					}
 				}
-				return nil
+				return errs.err()
 			}
 			for _, file := range args {
-				tagFileWith(file, name)
+				if !tagFileWith(file, name) {
+					break
+				}
 			}
-			return nil
+			return errs.err()
 		},
 	}
 	tagSet.Flags().BoolVarP(&stdin, "stdin", "", false, "read file names from standard input")
+	tagSet.Flags().BoolVar(&strict, "strict", false, "stop on first note which could not be updated")
 	tagSet.Flags().StringVarP(&name, "name", "n", "", "short name without space. Can have form of name:number-or-date")
 	return tagSet
 }
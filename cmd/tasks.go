@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elgopher/noteo/date"
+	"github.com/elgopher/noteo/listing"
+	"github.com/elgopher/noteo/notes"
+	"github.com/elgopher/noteo/task"
+)
+
+type tasksCommand struct {
+	listing.Filter
+	status     string
+	inlineTags []string
+	dueBefore  string
+	dueAfter   string
+	strict     bool
+}
+
+func tasks() *cobra.Command {
+	c := &tasksCommand{}
+	tasks := &cobra.Command{
+		Use:   "tasks",
+		Short: "List Markdown task list items found in notes",
+		Args:  cobra.RangeArgs(0, 1),
+		RunE:  c.RunE,
+		Example: `
+  # List open tasks
+  noteo tasks
+
+  # List done tasks in notes tagged with "project"
+  noteo tasks --status done -t project
+
+  # List open tasks due this week, given as "- [ ] task @due(2020-10-01)"
+  noteo tasks --due-before "end of week"
+
+  # Tick off the task in line 12
+  noteo tasks done note.md:12`,
+	}
+	tasks.Flags().StringVar(&c.status, "status", "open", "")
+	tasks.Flags().StringArrayVar(&c.inlineTags, "task-tag", nil, "")
+	tasks.Flags().StringVar(&c.dueBefore, "due-before", "", "")
+	tasks.Flags().StringVar(&c.dueAfter, "due-after", "", "")
+	tasks.Flags().BoolVar(&c.strict, "strict", false, "")
+	c.Filter.AddFlags(tasks.Flags())
+	tasks.SetUsageTemplate(`Usage:
+  {{.UseLine}} [DIR]
+  {{.CommandPath}} done FILE:LINE...{{if .HasExample}}
+
+Examples:
+{{.Example}}{{end}}
+
+Task flags:
+      --due-after <date>            filter tasks with @due(date) after given date
+      --due-before <date>           filter tasks with @due(date) before given date
+      --status string               filter tasks by status: open (default), done or all
+      --task-tag <name>             filter tasks having inline tag @name or @name(value). Flag can be specified multiple times.
+
+Filtering flags:
+` + listing.FilteringFlagsUsage + `
+Other flags:
+  -h, --help                        help for tasks
+      --strict                      stop on first note which could not be read
+`)
+	tasks.AddCommand(tasksDoneCmd())
+	return tasks
+}
+
+func (c *tasksCommand) RunE(cmd *cobra.Command, args []string) error {
+	repo, err := repo(args)
+	if err != nil {
+		return err
+	}
+	taskMatches, err := c.taskPredicate()
+	if err != nil {
+		return err
+	}
+	vocabulary, err := vocabularyOf(repo)
+	if err != nil {
+		return err
+	}
+	predicates, err := c.Predicates(vocabulary)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	printer := NewPrinter()
+	errs := newNoteErrors(cmd.ErrOrStderr(), c.strict)
+	notes.FilterSeq(toNotesSeq(repo.NotesSeq(ctx)), predicates...)(func(n notes.Note, err error) bool {
+		if err != nil {
+			return errs.report(err)
+		}
+		noteTasks, err := tasksOf(n)
+		if err != nil {
+			return errs.report(err)
+		}
+		for _, t := range noteTasks {
+			matches, err := taskMatches(t)
+			if err != nil {
+				if !errs.report(err, n.Path()+":") {
+					return false
+				}
+				continue
+			}
+			if !matches {
+				continue
+			}
+			checkbox := "[ ]"
+			if t.Done {
+				checkbox = "[x]"
+			}
+			printer.PrintFile(fmt.Sprintf("%s:%d", n.Path(), t.Line))
+			printer.Println(" " + checkbox + " " + t.Text)
+		}
+		return true
+	})
+	return errs.err()
+}
+
+type lineNote interface {
+	notes.Note
+	BodyLine() (int, error)
+}
+
+func tasksOf(n notes.Note) ([]task.Task, error) {
+	body, err := n.Body()
+	if err != nil {
+		return nil, err
+	}
+	firstLine := 1
+	if ln, ok := n.(lineNote); ok {
+		if firstLine, err = ln.BodyLine(); err != nil {
+			return nil, err
+		}
+	}
+	return task.Parse(body, firstLine), nil
+}
+
+func (c *tasksCommand) taskPredicate() (func(task.Task) (bool, error), error) {
+	var predicates []func(task.Task) (bool, error)
+	switch strings.ToLower(c.status) {
+	case "open":
+		predicates = append(predicates, func(t task.Task) (bool, error) { return !t.Done, nil })
+	case "done":
+		predicates = append(predicates, func(t task.Task) (bool, error) { return t.Done, nil })
+	case "all":
+	default:
+		return nil, fmt.Errorf("unsupported status: %s. Supported are: open, done, all", c.status)
+	}
+	for _, name := range c.inlineTags {
+		name := strings.TrimPrefix(name, "@")
+		predicates = append(predicates, func(t task.Task) (bool, error) {
+			_, ok := t.Tag(name)
+			return ok, nil
+		})
+	}
+	for _, d := range []struct {
+		value   string
+		matches func(due, d time.Time) bool
+	}{
+		{value: c.dueBefore, matches: func(due, d time.Time) bool { return due.Before(d) }},
+		{value: c.dueAfter, matches: func(due, d time.Time) bool { return due.After(d) }},
+	} {
+		if d.value == "" {
+			continue
+		}
+		parsed, err := date.Parse(d.value)
+		if err != nil {
+			return nil, err
+		}
+		matches := d.matches
+		predicates = append(predicates, func(t task.Task) (bool, error) {
+			due, found, err := t.Due()
+			if err != nil || !found {
+				return false, err
+			}
+			return matches(due, parsed), nil
+		})
+	}
+	return func(t task.Task) (bool, error) {
+		for _, p := range predicates {
+			matches, err := p(t)
+			if err != nil || !matches {
+				return false, err
+			}
+		}
+		return true, nil
+	}, nil
+}
+
+func tasksDoneCmd() *cobra.Command {
+	var strict bool
+	tasksDone := &cobra.Command{
+		Use:   "done FILE:LINE...",
+		Short: "Tick off tasks",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, err := workingDirRepository()
+			if err != nil {
+				return err
+			}
+			files := make([]string, len(args))
+			lines := make([]int, len(args))
+			for i, arg := range args {
+				separator := strings.LastIndex(arg, ":")
+				if separator < 0 {
+					return fmt.Errorf("%s is not in FILE:LINE format", arg)
+				}
+				files[i] = arg[:separator]
+				if lines[i], err = strconv.Atoi(arg[separator+1:]); err != nil {
+					return fmt.Errorf("%s is not in FILE:LINE format", arg)
+				}
+			}
+			errs := newNoteErrors(cmd.ErrOrStderr(), strict)
+			printer := NewPrinter()
+			for i, arg := range args {
+				if err := repo.CompleteTask(files[i], lines[i]); err != nil {
+					if !errs.report(err, "skipping:", arg+":") {
+						break
+					}
+					continue
+				}
+				printer.PrintFile(arg)
+				printer.Println(" done")
+			}
+			return errs.err()
+		},
+	}
+	tasksDone.Flags().BoolVar(&strict, "strict", false, "stop on first task which could not be ticked off")
+	return tasksDone
+}
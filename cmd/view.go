@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/elgopher/noteo/repository"
+)
+
+func viewCmd() *cobra.Command {
+	c := &lsCommand{}
+	view := &cobra.Command{
+		Use:   "view NAME",
+		Short: "List notes using a view defined in .noteo.yml",
+		Long: `List notes using a view defined in .noteo.yml. View is a named set of ls flags, such as filters, sorting,
+limit and output. Flags given in command line refine the view: filters which can be given many times, such as --tag,
+are added to filters of the view, and other flags replace values of the view.`,
+		Example: `
+  # Define inbox view in .noteo.yml
+  views:
+    inbox:
+      tag: [inbox]
+      no-tag: [done]
+      sort-by-created: true
+      limit: 20
+      output: table=file,beginning,created
+
+  # List notes using inbox view (same as noteo ls @inbox)
+  noteo view inbox
+
+  # List notes using inbox view, limited to notes tagged "work"
+  noteo view inbox -t work
+
+  # List defined views
+  noteo view ls`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return c.runView(cmd, args[0])
+		},
+	}
+	c.addLsFlags(view)
+	view.AddCommand(viewLs())
+	return view
+}
+
+func viewLs() *cobra.Command {
+	return &cobra.Command{
+		Use:   "ls",
+		Short: "List views defined in .noteo.yml",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			views, err := viewList()
+			if err != nil {
+				return err
+			}
+			if len(views) == 0 {
+				return exitError{code: exitNoMatches}
+			}
+			flags := ls().Flags()
+			for _, v := range views {
+				fmt.Printf("%s\t%s\n", v.Name, viewArgs(v, flags))
+			}
+			return nil
+		},
+	}
+}
+
+func viewList() ([]repository.View, error) {
+	cfg, err := workingDirConfig()
+	if err != nil {
+		return nil, err
+	}
+	return cfg.ViewList()
+}
+
+// workingDirConfig returns config of the repository in the working directory, where views are defined
+func workingDirConfig() (*repository.Config, error) {
+	repo, err := workingDirRepository()
+	if err != nil {
+		return nil, err
+	}
+	return repo.Config()
+}
+
+// runView lists notes using flags of the view. Flags given in command line take precedence.
+func (c *lsCommand) runView(cmd *cobra.Command, name string) error {
+	cfg, err := workingDirConfig()
+	if err != nil {
+		return err
+	}
+	v, err := cfg.View(name)
+	if err != nil {
+		return err
+	}
+	if err = applyView(cmd.Flags(), v); err != nil {
+		return err
+	}
+	return c.RunE(cmd, nil)
+}
+
+// applyView sets ls flags of the view. Values of flags which can be given many times, such as --tag, are added to
+// values given in command line. Other flags given in command line are left untouched.
+func applyView(flags *pflag.FlagSet, v repository.View) error {
+	for _, f := range v.Flags {
+		flag := flags.Lookup(f.Name)
+		if flag == nil {
+			return fmt.Errorf("view %s: unknown ls flag: %s", v.Name, f.Name)
+		}
+		if flag.Changed && !repeatable(flag) {
+			continue
+		}
+		for _, value := range f.Values {
+			if err := flags.Set(f.Name, value); err != nil {
+				return fmt.Errorf("view %s: %v", v.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func repeatable(flag *pflag.Flag) bool {
+	return flag.Value.Type() == "stringArray"
+}
+
+// viewArgs returns flags of the view as given in command line, e.g. --tag inbox --sort-by-created
+func viewArgs(v repository.View, flags *pflag.FlagSet) string {
+	var args []string
+	for _, f := range v.Flags {
+		flag := flags.Lookup(f.Name)
+		for _, value := range f.Values {
+			if flag != nil && flag.Value.Type() == "bool" && value == "true" {
+				args = append(args, "--"+f.Name)
+				continue
+			}
+			if value == "" || strings.ContainsAny(value, " \t\"'") {
+				value = strconv.Quote(value)
+			}
+			args = append(args, "--"+f.Name, value)
+		}
+	}
+	return strings.Join(args, " ")
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elgopher/noteo/repository"
+)
+
+func watch() *cobra.Command {
+	var (
+		command string
+		poll    time.Duration
+	)
+	watch := &cobra.Command{
+		Use:   "watch",
+		Short: "Watch notes for changes",
+		Long: `Watch notes in a current working directory for changes and print events or execute a command for each event.
+
+The command is executed by sh (cmd on Windows) with NOTEO_EVENT (created, modified or removed) and NOTEO_FILE
+environment variables set.`,
+		Args: cobra.RangeArgs(0, 1),
+		Example: `
+  # Print events
+  noteo watch
+
+  # Execute command for each event
+  noteo watch --exec 'git add "$NOTEO_FILE"'`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, err := repo(args)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			var events <-chan repository.Event
+			var errs <-chan error
+			if poll > 0 {
+				events, errs = repo.WatchPolling(ctx, poll)
+			} else {
+				events, errs = repo.Watch(ctx)
+			}
+			printErrors(ctx, errs)
+			printer := NewPrinter()
+			for event := range events {
+				if command == "" {
+					printer.Print(string(event.Type) + " ")
+					printer.PrintFile(event.Note.Path())
+					printer.Println()
+					continue
+				}
+				if err := execHook(command, event); err != nil {
+					_, _ = fmt.Fprintln(cmd.ErrOrStderr(), err)
+				}
+			}
+			return nil
+		},
+	}
+	watch.Flags().StringVar(&command, "exec", "", "command executed for each event")
+	watch.Flags().DurationVar(&poll, "poll", 0, "scan files every given interval (e.g. 2s) instead of using file system notifications")
+	return watch
+}
+
+// execHook runs command in a shell, so it can use quotes, pipes and environment variables describing the event
+func execHook(command string, event repository.Event) error {
+	file := event.Note.Path()
+	cmd := exec.Command("sh", "-c", command)
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	}
+	cmd.Env = append(os.Environ(), "NOTEO_EVENT="+string(event.Type), "NOTEO_FILE="+file)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s failed for %s: %v", command, file, err)
+	}
+	return nil
+}
@@ -10,7 +10,9 @@ import (
 type Printer ansiterm.Writer
 
 func NewPrinter() *Printer {
-	return (*Printer)(ansiterm.NewWriter(os.Stdout))
+	writer := ansiterm.NewWriter(os.Stdout)
+	writer.SetColorCapable(colorEnabled())
+	return (*Printer)(writer)
 }
 
 func (w Printer) PrintFile(file string) {
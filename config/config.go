@@ -1,35 +1,341 @@
-// Package config provides configuration from merging environment and repository config
+// Package config provides settings merged from flags, environment, repository config and user config
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
 	"runtime"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
 )
 
-type RepoConfig interface {
-	EditorCommand() string
+// Origin tells where the value of the setting comes from
+type Origin string
+
+const (
+	FromFlag    Origin = "flag"
+	FromEnv     Origin = "env"
+	FromRepo    Origin = "repo"
+	FromUser    Origin = "user"
+	FromDefault Origin = "default"
+)
+
+// Setting is a key which can be given in a flag, NOTEO_* environment variable, repository config (.noteo.yml)
+// or user config, in that order of precedence. Repository config in a subdirectory, having root: false, overrides
+// the one in the root directory. Nested keys are separated with dots, e.g. ls.output.
+type Setting struct {
+	Key     string
+	Default string
+	// Values lists allowed values. Empty list means any value.
+	Values []string
+	Usage  string
+}
+
+// Settings lists all keys which can be read and written by noteo config command
+var Settings = []Setting{
+	{Key: "editor", Usage: "command used to edit notes, by default $VISUAL, $EDITOR, then vim + (notepad on Windows)"},
+	{Key: "ls.output", Default: "table", Usage: "default output of ls: table, wide, json, yaml, template=<go template>, " +
+		"template-file=<path>, jsonpath=<template> or jsonpath-file=<path>"},
+	{Key: "ls.columns", Default: "file,beginning,modified,tags", Usage: "columns of ls table output, e.g. file,title:60,tag:deadline"},
+	{Key: "date-format", Usage: "dates shown by ls: relative, iso8601, rfc2822 or format:<layout>"},
+	{Key: "timezone", Usage: "IANA time zone of dates shown by ls, e.g. Europe/Warsaw"},
+	{Key: "naming", Default: "title", Values: []string{"title", "date-title", "timestamp"},
+		Usage: "file names of new notes"},
+	{Key: "add.tags", Usage: "tags added to new notes, e.g. journal,daily"},
+	{Key: "add.template", Usage: "file with a template of new notes, relative to the config file. " +
+		"{{.Created}} and {{.Tags}} are replaced with the date and tags"},
+	{Key: "color", Default: "auto", Values: []string{"auto", "always", "never"}, Usage: "colors in terminal output"},
+}
+
+// EnvName returns environment variable overriding the setting, e.g. NOTEO_LS_OUTPUT for ls.output
+func (s Setting) EnvName() string {
+	return "NOTEO_" + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(s.Key))
+}
+
+func lookup(key string) (Setting, error) {
+	for _, s := range Settings {
+		if s.Key == key {
+			return s, nil
+		}
+	}
+	return Setting{}, fmt.Errorf("unknown setting: %s", key)
+}
+
+// Value of the setting together with its origin
+type Value struct {
+	Key    string
+	Value  string
+	Origin Origin
+	// Source is a config file or environment variable. It is empty for flags and defaults.
+	Source string
 }
 
 type Config struct {
-	repoConfig RepoConfig
+	files []file // repository config files first, nearest first
+	flags map[string]string
 }
 
-func New(config RepoConfig) *Config {
-	return &Config{repoConfig: config}
+type file struct {
+	origin Origin
+	path   string
+	values map[string]interface{}
 }
 
-func (c *Config) EditorCommand() string {
-	if c.repoConfig.EditorCommand() != "" {
-		return c.repoConfig.EditorCommand()
+// Load reads repository config files, nearest first, and user config. No repoFiles means there is no repository.
+// Missing files are treated as empty.
+func Load(repoFiles ...string) (*Config, error) {
+	c := &Config{flags: map[string]string{}}
+	for _, repoFile := range repoFiles {
+		if repoFile == "" {
+			continue
+		}
+		f, err := readFile(FromRepo, repoFile)
+		if err != nil {
+			return nil, err
+		}
+		c.files = append(c.files, f)
+	}
+	userFile, err := UserFile()
+	if err != nil {
+		return c, nil // user config is optional, e.g. when home directory is unknown
+	}
+	f, err := readFile(FromUser, userFile)
+	if err != nil {
+		return nil, err
+	}
+	c.files = append(c.files, f)
+	return c, nil
+}
+
+// UserFile returns path of the user config: $XDG_CONFIG_HOME/noteo/config.yml. When XDG_CONFIG_HOME is not set,
+// default user config directory of the operating system is used, e.g. ~/.config on Linux.
+func UserFile() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		var err error
+		if dir, err = os.UserConfigDir(); err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(dir, "noteo", "config.yml"), nil
+}
+
+func readFile(origin Origin, path string) (file, error) {
+	f := file{origin: origin, path: path, values: map[string]interface{}{}}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return f, nil
+	}
+	if err != nil {
+		return f, err
+	}
+	if err = yaml.Unmarshal(content, &f.values); err != nil {
+		return f, fmt.Errorf("%s: %v", path, err)
+	}
+	return f, nil
+}
+
+// lookup returns value of nested key, such as ls.output. Lists are joined with commas.
+func (f file) lookup(key string) (string, bool) {
+	var value interface{} = f.values
+	for _, name := range strings.Split(key, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+		if value, ok = m[name]; !ok || value == nil {
+			return "", false
+		}
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return "", false
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = fmt.Sprint(item)
+		}
+		return strings.Join(items, ","), true
+	default:
+		return fmt.Sprint(v), true
+	}
+}
+
+// Repo is a named repository registered in the user config
+type Repo struct {
+	Name string
+	// Dir is an absolute path of the repository directory
+	Dir string
+}
+
+// Repos returns repositories registered in repos section of the user config, sorted by name. Directories starting
+// with ~ or relative directories are resolved against the home directory.
+func (c *Config) Repos() ([]Repo, error) {
+	var repos []Repo
+	for _, f := range c.files {
+		if f.origin != FromUser {
+			continue
+		}
+		dirs, ok := f.values["repos"].(map[string]interface{})
+		if !ok && f.values["repos"] != nil {
+			return nil, fmt.Errorf("%s: repos must be a mapping of names to directories", f.path)
+		}
+		for name, dir := range dirs {
+			path, err := expandHome(fmt.Sprint(dir))
+			if err != nil {
+				return nil, err
+			}
+			repos = append(repos, Repo{Name: name, Dir: path})
+		}
+	}
+	sort.Slice(repos, func(i, j int) bool {
+		return repos[i].Name < repos[j].Name
+	})
+	return repos, nil
+}
+
+// Repo returns the repository registered under given name
+func (c *Config) Repo(name string) (Repo, error) {
+	repos, err := c.Repos()
+	if err != nil {
+		return Repo{}, err
 	}
-	if visual := os.Getenv("VISUAL"); visual != "" {
-		return visual
+	for _, r := range repos {
+		if r.Name == name {
+			return r, nil
+		}
 	}
-	if editor := os.Getenv("EDITOR"); editor != "" {
-		return editor
+	userFile, _ := UserFile()
+	return Repo{}, fmt.Errorf("unknown repository: %s. Repositories can be registered in %s, e.g. "+
+		"repos: {work: ~/notes/work}", name, userFile)
+}
+
+func expandHome(dir string) (string, error) {
+	if filepath.IsAbs(dir) {
+		return dir, nil
 	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, strings.TrimLeft(strings.TrimPrefix(dir, "~"), `/\`)), nil
+}
+
+// SetFlag overrides the setting with a value given in a command line flag
+func (c *Config) SetFlag(key, value string) {
+	c.flags[key] = value
+}
+
+// Get returns the value of the setting with the highest precedence
+func (c *Config) Get(key string) (Value, error) {
+	s, err := lookup(key)
+	if err != nil {
+		return Value{}, err
+	}
+	return c.get(s), nil
+}
+
+// List returns values of all settings
+func (c *Config) List() []Value {
+	values := make([]Value, len(Settings))
+	for i, s := range Settings {
+		values[i] = c.get(s)
+	}
+	return values
+}
+
+func (c *Config) get(s Setting) Value {
+	if value, ok := c.flags[s.Key]; ok {
+		return Value{Key: s.Key, Value: value, Origin: FromFlag}
+	}
+	if value := os.Getenv(s.EnvName()); value != "" {
+		return Value{Key: s.Key, Value: value, Origin: FromEnv, Source: s.EnvName()}
+	}
+	for _, f := range c.files {
+		if value, ok := f.lookup(s.Key); ok {
+			return Value{Key: s.Key, Value: value, Origin: f.origin, Source: f.path}
+		}
+	}
+	if s.Key == "editor" {
+		return defaultEditor()
+	}
+	return Value{Key: s.Key, Value: s.Default, Origin: FromDefault}
+}
+
+// defaultEditor returns editor from $VISUAL or $EDITOR, which are more general than repository and user config
+func defaultEditor() Value {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if editor := os.Getenv(name); editor != "" {
+			return Value{Key: "editor", Value: editor, Origin: FromEnv, Source: name}
+		}
+	}
+	editor := "vim +"
 	if runtime.GOOS == "windows" {
-		return "notepad"
+		editor = "notepad"
 	}
-	return "vim +"
+	return Value{Key: "editor", Value: editor, Origin: FromDefault}
+}
+
+func (c *Config) value(key string) string {
+	s, _ := lookup(key)
+	return c.get(s).Value
+}
+
+func (c *Config) EditorCommand() string {
+	return c.value("editor")
+}
+
+// LsOutput returns output of ls: table, wide, json, yaml, table with columns (e.g. table=file,tags), template or
+// jsonpath
+func (c *Config) LsOutput() string {
+	return c.value("ls.output")
+}
+
+// LsColumns returns columns of ls table output separated with commas
+func (c *Config) LsColumns() string {
+	return c.value("ls.columns")
+}
+
+func (c *Config) DateFormat() string {
+	return c.value("date-format")
+}
+
+func (c *Config) Timezone() string {
+	return c.value("timezone")
+}
+
+// Naming returns strategy of generating file names of new notes: title, date-title or timestamp
+func (c *Config) Naming() string {
+	return c.value("naming")
+}
+
+// AddTags returns tags added to new notes
+func (c *Config) AddTags() []string {
+	var tags []string
+	for _, t := range strings.Split(c.value("add.tags"), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
+// AddTemplate returns path of the template file of new notes or empty string when none was given. Relative path
+// is resolved against the directory of the config file where it was given.
+func (c *Config) AddTemplate() string {
+	s, _ := lookup("add.template")
+	v := c.get(s)
+	if v.Value == "" || filepath.IsAbs(v.Value) || (v.Origin != FromRepo && v.Origin != FromUser) {
+		return v.Value
+	}
+	return filepath.Join(filepath.Dir(v.Source), v.Value)
+}
+
+// Color returns auto, always or never
+func (c *Config) Color() string {
+	return c.value("color")
 }
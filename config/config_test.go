@@ -0,0 +1,265 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/noteo/config"
+)
+
+func TestConfig_Get(t *testing.T) {
+	t.Run("should take value with the highest precedence", func(t *testing.T) {
+		repoFile, userFile := files(t, "date-format: iso8601\nls:\n  output: json\n",
+			"date-format: rfc2822\nnaming: date-title\nls:\n  output: yaml\n  columns: [file, tags]\n")
+		t.Setenv("NOTEO_LS_OUTPUT", "wide")
+		cfg, err := config.Load(repoFile)
+		require.NoError(t, err)
+		cfg.SetFlag("color", "never")
+		// expect
+		assert.Equal(t, []config.Value{
+			{Key: "editor", Value: "vim +", Origin: config.FromDefault},
+			{Key: "ls.output", Value: "wide", Origin: config.FromEnv, Source: "NOTEO_LS_OUTPUT"},
+			{Key: "ls.columns", Value: "file,tags", Origin: config.FromUser, Source: userFile},
+			{Key: "date-format", Value: "iso8601", Origin: config.FromRepo, Source: repoFile},
+			{Key: "timezone", Value: "", Origin: config.FromDefault},
+			{Key: "naming", Value: "date-title", Origin: config.FromUser, Source: userFile},
+			{Key: "add.tags", Value: "", Origin: config.FromDefault},
+			{Key: "add.template", Value: "", Origin: config.FromDefault},
+			{Key: "color", Value: "never", Origin: config.FromFlag},
+		}, cfg.List())
+	})
+
+	t.Run("should take value from the nearest repository config", func(t *testing.T) {
+		rootFile, _ := files(t, "naming: timestamp\nls:\n  columns: file,tags\n", "")
+		subdirFile := filepath.Join(filepath.Dir(rootFile), "journal", ".noteo.yml")
+		require.NoError(t, os.MkdirAll(filepath.Dir(subdirFile), 0775))
+		require.NoError(t, os.WriteFile(subdirFile, []byte("naming: date-title\n"), 0664))
+		cfg, err := config.Load(subdirFile, rootFile)
+		require.NoError(t, err)
+		// when
+		naming, err := cfg.Get("naming")
+		require.NoError(t, err)
+		columns, err := cfg.Get("ls.columns")
+		require.NoError(t, err)
+		// then
+		assert.Equal(t, config.Value{Key: "naming", Value: "date-title", Origin: config.FromRepo, Source: subdirFile}, naming)
+		assert.Equal(t, config.Value{Key: "ls.columns", Value: "file,tags", Origin: config.FromRepo, Source: rootFile}, columns)
+	})
+
+	t.Run("should take editor from repository config before VISUAL", func(t *testing.T) {
+		repoFile, _ := files(t, "editor: nano\n", "")
+		t.Setenv("VISUAL", "code -w")
+		cfg, err := config.Load(repoFile)
+		require.NoError(t, err)
+		// when
+		value, err := cfg.Get("editor")
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, config.Value{Key: "editor", Value: "nano", Origin: config.FromRepo, Source: repoFile}, value)
+	})
+
+	t.Run("should take editor from VISUAL", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+		t.Setenv("VISUAL", "code -w")
+		cfg, err := config.Load("")
+		require.NoError(t, err)
+		// when
+		value, err := cfg.Get("editor")
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, config.Value{Key: "editor", Value: "code -w", Origin: config.FromEnv, Source: "VISUAL"}, value)
+	})
+
+	t.Run("should return error for unknown key", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+		cfg, err := config.Load("")
+		require.NoError(t, err)
+		// when
+		_, err = cfg.Get("unknown")
+		// then
+		assert.EqualError(t, err, "unknown setting: unknown")
+	})
+}
+
+func TestConfig_AddTags(t *testing.T) {
+	repoFile, _ := files(t, "add:\n  tags: [journal, daily]\n", "")
+	cfg, err := config.Load(repoFile)
+	require.NoError(t, err)
+	// when
+	tags := cfg.AddTags()
+	// then
+	assert.Equal(t, []string{"journal", "daily"}, tags)
+}
+
+func TestConfig_AddTemplate(t *testing.T) {
+	t.Run("should resolve path relative to the config file", func(t *testing.T) {
+		repoFile, _ := files(t, "add:\n  template: templates/journal.md\n", "")
+		cfg, err := config.Load(repoFile)
+		require.NoError(t, err)
+		// when
+		template := cfg.AddTemplate()
+		// then
+		assert.Equal(t, filepath.Join(filepath.Dir(repoFile), "templates", "journal.md"), template)
+	})
+
+	t.Run("should return empty string when template is not given", func(t *testing.T) {
+		repoFile, _ := files(t, "", "")
+		cfg, err := config.Load(repoFile)
+		require.NoError(t, err)
+		// when
+		template := cfg.AddTemplate()
+		// then
+		assert.Empty(t, template)
+	})
+}
+
+func TestConfig_Repos(t *testing.T) {
+	t.Run("should return repositories sorted by name", func(t *testing.T) {
+		home, me := t.TempDir(), t.TempDir()
+		t.Setenv("HOME", home)
+		t.Setenv("USERPROFILE", home) // home directory on Windows
+		repoFile, _ := files(t, "repos:\n  ignored: "+me+"\n", "repos:\n  work: ~/notes/work\n  me: "+me+"\n")
+		cfg, err := config.Load(repoFile)
+		require.NoError(t, err)
+		// when
+		repos, err := cfg.Repos()
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []config.Repo{
+			{Name: "me", Dir: me},
+			{Name: "work", Dir: filepath.Join(home, "notes", "work")},
+		}, repos)
+	})
+
+	t.Run("should return error for unknown repository", func(t *testing.T) {
+		_, _ = files(t, "", "repos:\n  work: /notes/work\n")
+		cfg, err := config.Load("")
+		require.NoError(t, err)
+		// when
+		_, err = cfg.Repo("unknown")
+		// then
+		assert.ErrorContains(t, err, "unknown repository: unknown")
+	})
+}
+
+func TestSet(t *testing.T) {
+	tests := map[string]struct {
+		content  string
+		key      string
+		value    string
+		expected string
+	}{
+		"empty file": {
+			key: "ls.output", value: "json",
+			expected: "ls:\n  output: json\n",
+		},
+		"commented file": {
+			content: "# editor: vim +\n# naming: title",
+			key:     "naming", value: "timestamp",
+			expected: "# editor: vim +\n# naming: title\nnaming: timestamp\n",
+		},
+		"existing value": {
+			content: "# comment\neditor: vim # my editor\ncolor: auto\n",
+			key:     "editor", value: "nano",
+			expected: "# comment\neditor: nano # my editor\ncolor: auto\n",
+		},
+		"value which needs quotes": {
+			content: "date-format: iso8601\n",
+			key:     "date-format", value: "format: %Y",
+			expected: "date-format: 'format: %Y'\n",
+		},
+		"existing list": {
+			content: "ls:\n    columns:\n        - file\n        - tags\n    output: table\n",
+			key:     "ls.columns", value: "file,created",
+			expected: "ls:\n    columns: file,created\n    output: table\n",
+		},
+		"existing block scalar": {
+			content: "editor: |\n  vim\n  +\n\n# naming\nnaming: title\n",
+			key:     "editor", value: "nano",
+			expected: "editor: nano\n\n# naming\nnaming: title\n",
+		},
+		"existing block scalar of the last nested key": {
+			content: "ls:\n  output: >-\n    template={{.File}}\n    {{.Tags}}\n  # comment\ncolor: never\n",
+			key:     "ls.output", value: "json",
+			expected: "ls:\n  output: json\n  # comment\ncolor: never\n",
+		},
+		"existing multi-line flow sequence": {
+			content: "ls:\n  columns: [file,\n    tags]\n  output: table\n",
+			key:     "ls.columns", value: "file,created",
+			expected: "ls:\n  columns: file,created\n  output: table\n",
+		},
+		"existing multi-line flow mapping": {
+			content: "editor: {name: vim,\n  args: +}\ncolor: never\n",
+			key:     "editor", value: "nano",
+			expected: "editor: nano\ncolor: never\n",
+		},
+		"missing key in existing mapping": {
+			content: "ls:\n    output: table\n\n# agenda:\n",
+			key:     "ls.columns", value: "file",
+			expected: "ls:\n    output: table\n    columns: file\n\n# agenda:\n",
+		},
+		"mapping without keys": {
+			content: "ls:\ncolor: never\n",
+			key:     "ls.output", value: "wide",
+			expected: "ls:\n  output: wide\ncolor: never\n",
+		},
+		"crlf": {
+			content: "editor: vim\r\n",
+			key:     "editor", value: "nano",
+			expected: "editor: nano\r\n",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "config.yml")
+			if test.content != "" {
+				require.NoError(t, os.WriteFile(file, []byte(test.content), 0664))
+			}
+			// when
+			err := config.Set(file, test.key, test.value)
+			// then
+			require.NoError(t, err)
+			content, err := os.ReadFile(file)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, string(content))
+		})
+	}
+
+	t.Run("should create directory of user config", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "noteo", "config.yml")
+		// when
+		err := config.Set(file, "color", "never")
+		// then
+		require.NoError(t, err)
+		content, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.Equal(t, "color: never\n", string(content))
+	})
+
+	t.Run("should reject unsupported value", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "config.yml")
+		// when
+		err := config.Set(file, "color", "sometimes")
+		// then
+		assert.EqualError(t, err, "unsupported color: sometimes. Supported values are auto, always and never")
+	})
+}
+
+// files writes repository config and user config, which is found using XDG_CONFIG_HOME
+func files(t *testing.T, repoConfig, userConfig string) (repoFile, userFile string) {
+	for _, name := range []string{"VISUAL", "EDITOR", "NOTEO_LS_OUTPUT", "NOTEO_ADD_TAGS", "NOTEO_ADD_TEMPLATE"} {
+		t.Setenv(name, "")
+	}
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "config"))
+	repoFile = filepath.Join(dir, ".noteo.yml")
+	userFile = filepath.Join(dir, "config", "noteo", "config.yml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(userFile), 0775))
+	require.NoError(t, os.WriteFile(repoFile, []byte(repoConfig), 0664))
+	require.NoError(t, os.WriteFile(userFile, []byte(userConfig), 0664))
+	return repoFile, userFile
+}
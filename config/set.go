@@ -0,0 +1,176 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Set writes value of the setting to the config file, which is created when missing. Only the line with the value
+// is changed, so comments and formatting of other lines are preserved.
+func Set(path, key, value string) error {
+	s, err := lookup(key)
+	if err != nil {
+		return err
+	}
+	if len(s.Values) > 0 && !contains(s.Values, value) {
+		last := len(s.Values) - 1
+		return fmt.Errorf("unsupported %s: %s. Supported values are %s and %s", key, value,
+			strings.Join(s.Values[:last], ", "), s.Values[last])
+	}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		if err = os.MkdirAll(filepath.Dir(path), 0775); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+	edited, err := setValue(string(content), strings.Split(key, "."), value)
+	if err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	return os.WriteFile(path, []byte(edited), 0664)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// setValue replaces the value of nested key in YAML content or adds the key after the last line of the closest
+// existing mapping
+func setValue(content string, path []string, value string) (string, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &doc); err != nil {
+		return "", err
+	}
+	rendered, err := scalar(value)
+	if err != nil {
+		return "", err
+	}
+	lines := strings.SplitAfter(content, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	var mapping *yaml.Node
+	if len(doc.Content) > 0 {
+		mapping = doc.Content[0]
+	}
+	insertAt, indent := len(lines), ""
+	// end is the index of the line following the mapping entry with the key
+	end := len(lines)
+	for i, name := range path {
+		if mapping == nil {
+			break
+		}
+		if mapping.Kind != yaml.MappingNode || mapping.Style&yaml.FlowStyle != 0 {
+			return "", fmt.Errorf("%s is not a block mapping", strings.Join(path[:i], "."))
+		}
+		keyNode, valueNode, nextKey := find(mapping, name)
+		if nextKey != nil {
+			end = nextKey.Line - 1
+		}
+		if keyNode == nil {
+			insertAt, indent = lastLine(mapping), strings.Repeat(" ", mapping.Content[0].Column-1)
+			path = path[i:]
+			break
+		}
+		if i < len(path)-1 && valueNode.Kind != yaml.MappingNode {
+			// key without nested keys, such as "ls:", gets them in following lines
+			if valueNode.Kind != yaml.ScalarNode || valueNode.Value != "" {
+				return "", fmt.Errorf("%s is not a mapping", strings.Join(path[:i+1], "."))
+			}
+			insertAt, indent = keyNode.Line, strings.Repeat(" ", keyNode.Column+1)
+			path = path[i+1:]
+			break
+		}
+		if i == len(path)-1 {
+			return replaceValue(lines, keyNode, valueNode, rendered, end), nil
+		}
+		mapping = valueNode
+	}
+	var added strings.Builder
+	for i, name := range path {
+		added.WriteString(indent + strings.Repeat("  ", i) + name + ":")
+		if i == len(path)-1 {
+			added.WriteString(" " + rendered)
+		}
+		added.WriteString("\n")
+	}
+	if insertAt > 0 && !strings.HasSuffix(lines[insertAt-1], "\n") {
+		lines[insertAt-1] += "\n"
+	}
+	return strings.Join(lines[:insertAt], "") + added.String() + strings.Join(lines[insertAt:], ""), nil
+}
+
+// find returns key and value with the name, and the key following them, which is nil for the last key
+func find(mapping *yaml.Node, name string) (key, value, nextKey *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == name {
+			if i+2 < len(mapping.Content) {
+				nextKey = mapping.Content[i+2]
+			}
+			return mapping.Content[i], mapping.Content[i+1], nextKey
+		}
+	}
+	return nil, nil, nil
+}
+
+// lastLine returns number of the last line of the node, counting from 1
+func lastLine(node *yaml.Node) int {
+	last := node.Line
+	for _, child := range node.Content {
+		if line := lastLine(child); line > last {
+			last = line
+		}
+	}
+	return last
+}
+
+// replaceValue replaces the value, which can span many lines up to the end index of the mapping entry, keeping the
+// line comment
+func replaceValue(lines []string, key, value *yaml.Node, rendered string, end int) string {
+	keyLine := lines[key.Line-1]
+	ending := keyLine[len(strings.TrimRight(keyLine, "\r\n")):]
+	start := strings.Index(keyLine[key.Column-1:], ":") + key.Column
+	comment := value.LineComment
+	if comment == "" {
+		comment = key.LineComment
+	}
+	if comment != "" {
+		rendered += " " + comment
+	}
+	lines[key.Line-1] = keyLine[:start] + " " + rendered + ending
+	// blank lines and comments not indented more than the key, which precede the next key, are not part of the value
+	for end > key.Line && isOuterLine(lines[end-1], key.Column) {
+		end--
+	}
+	// block sequence, block mapping, block scalar or flow collection spanning following lines
+	return strings.Join(append(lines[:key.Line], lines[end:]...), "")
+}
+
+// isOuterLine returns true for blank line or comment starting before column, counting from 1
+func isOuterLine(line string, column int) bool {
+	trimmed := strings.TrimLeft(line, " \t")
+	if strings.TrimSpace(trimmed) == "" {
+		return true
+	}
+	return strings.HasPrefix(trimmed, "#") && len(line)-len(trimmed) < column
+}
+
+func scalar(value string) (string, error) {
+	out, err := yaml.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
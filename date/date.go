@@ -3,6 +3,8 @@ package date
 import (
 	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -17,6 +19,9 @@ const (
 
 var now = time.Now
 
+// ErrUnsupportedDate is wrapped by errors returned by Parse when value is neither absolute date nor supported expression
+var ErrUnsupportedDate = errors.New("not supported date format")
+
 func SetNow(f func() time.Time) {
 	if f == nil {
 		panic("nil function")
@@ -24,7 +29,110 @@ func SetNow(f func() time.Time) {
 	now = f
 }
 
-const iso8601Layout = "2006-01-02 15:04:05 Z0700"
+// ISO8601Layout is the Go layout of ISO8601 format, which can be parsed back by Parse
+const ISO8601Layout = "2006-01-02 15:04:05 Z0700"
+
+const layoutPrefix = "layout:"
+
+// Layout returns a custom format using Go layout, for example "2006-01-02 15:04"
+func Layout(layout string) Format {
+	return Format(layoutPrefix + layout)
+}
+
+// ParseFormat parses format name (rfc2822, iso8601, relative or their short names) or custom format given as
+// "format:<layout>", where layout is a strftime pattern such as "%Y-%m-%d" or Go layout such as "2006-01-02".
+func ParseFormat(s string) (Format, error) {
+	if strings.HasPrefix(strings.ToLower(s), "format:") {
+		layout := s[len("format:"):]
+		if layout == "" {
+			return "", errors.New("empty date format")
+		}
+		if strings.Contains(layout, "%") {
+			goLayout, err := StrftimeToLayout(layout)
+			if err != nil {
+				return "", err
+			}
+			return Layout(goLayout), nil
+		}
+		return Layout(layout), nil
+	}
+	switch strings.ToLower(s) {
+	case "rfc", "rfc2822":
+		return RFC2822, nil
+	case "iso", "iso8601":
+		return ISO8601, nil
+	case "relative":
+		return Relative, nil
+	default:
+		return "", fmt.Errorf("unsupported date format: %s. Supported formats are: rfc2822 (or rfc), iso8601 (or iso), relative "+
+			"and format:<layout>, e.g. format:%%Y-%%m-%%d or format:2006-01-02", s)
+	}
+}
+
+var strftimeDirectives = map[byte]string{
+	'Y': "2006", 'y': "06", 'm': "01", 'b': "Jan", 'h': "Jan", 'B': "January", 'd': "02", 'e': "_2", 'j': "002",
+	'a': "Mon", 'A': "Monday", 'H': "15", 'I': "03", 'M': "04", 'S': "05", 'p': "PM", 'Z': "MST", 'z': "-0700",
+	'F': "2006-01-02", 'T': "15:04:05", 'R': "15:04", 'D': "01/02/06", '%': "%",
+}
+
+// StrftimeToLayout converts strftime pattern, such as "%Y-%m-%d %H:%M", to Go layout. Go layouts cannot escape
+// literal text, so error is returned when literal text would be read as layout element, e.g. "1" in "%Y week 1".
+func StrftimeToLayout(pattern string) (string, error) {
+	var layout strings.Builder
+	var elements []layoutElement
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] != '%' {
+			layout.WriteByte(pattern[i])
+			elements = append(elements, layoutElement{text: pattern[i : i+1], literal: true})
+			continue
+		}
+		if i+1 == len(pattern) {
+			return "", fmt.Errorf("incomplete directive at the end of date format %s", pattern)
+		}
+		i++
+		directive, ok := strftimeDirectives[pattern[i]]
+		if !ok {
+			return "", fmt.Errorf("unsupported directive %%%c in date format %s", pattern[i], pattern)
+		}
+		layout.WriteString(directive)
+		elements = append(elements, layoutElement{text: directive})
+	}
+	if !formatsLikeElements(layout.String(), elements) {
+		return "", fmt.Errorf("date format %s has literal text which is a Go layout element, such as 1, 2, Jan or Mon", pattern)
+	}
+	return layout.String(), nil
+}
+
+// layoutSamples have all fields different from Go reference time, so literal text formats differently when it
+// contains layout elements
+var layoutSamples = []time.Time{
+	time.Date(2021, 11, 23, 19, 47, 38, 0, time.UTC),
+	time.Date(2022, 8, 16, 8, 9, 7, 0, time.FixedZone("", 3*60*60)),
+}
+
+type layoutElement struct {
+	text    string
+	literal bool
+}
+
+// formatsLikeElements returns true when layout is formatted the same as elements, where only directives are
+// formatted and literal text is kept as is
+func formatsLikeElements(layout string, elements []layoutElement) bool {
+	for _, sample := range layoutSamples {
+		var expected strings.Builder
+		for _, element := range elements {
+			if element.literal {
+				expected.WriteString(element.text)
+			} else {
+				expected.WriteString(sample.Format(element.text))
+			}
+		}
+		if sample.Format(layout) != expected.String() {
+			return false
+		}
+	}
+	return true
+}
 
 func FormatWithType(t time.Time, f Format) string {
 	switch f {
@@ -35,13 +143,16 @@ func FormatWithType(t time.Time, f Format) string {
 	case Relative:
 		return FormatRelative(t)
 	default:
+		if strings.HasPrefix(string(f), layoutPrefix) {
+			return t.Format(strings.TrimPrefix(string(f), layoutPrefix))
+		}
 		return "format " + string(f) + " not supported"
 	}
 }
 
 // Format timestamps in a ISO 8601-like format (same as in Git command)
 func FormatISO8601(t time.Time) string {
-	return t.Format(iso8601Layout)
+	return t.Format(ISO8601Layout)
 }
 
 // Format timestamps in RFC 2822 format, often found in email messages (same as in Git command)
@@ -49,36 +160,49 @@ func FormatRFC2822(t time.Time) string {
 	return t.Format(time.RFC1123Z)
 }
 
+// FormatRelative returns time relative to now, such as "3 days ago" or "in 3 days" for future time
 func FormatRelative(t time.Time) string {
+	timePassed := now().Sub(t)
+	if timePassed <= -time.Second {
+		return "in " + relativeAmount(-timePassed, true)
+	}
+	amount := relativeAmount(timePassed, false)
+	return strings.ToUpper(amount[:1]) + amount[1:] + " ago"
+}
+
+// relativeAmount returns d in words. Days of future time are rounded to the nearest day, so time 2 days and 16 hours
+// ahead is "in 3 days". Days of past time are truncated.
+func relativeAmount(d time.Duration, future bool) string {
 	var (
-		timePassed = time.Since(t)
-		seconds    = int(timePassed.Seconds())
-		minutes    = int(timePassed.Minutes())
-		hours      = int(timePassed.Hours() + 0.5)
+		seconds = int(d.Seconds())
+		minutes = int(d.Minutes())
+		hours   = int(d.Hours() + 0.5)
 	)
 	switch {
 	case seconds < 1:
-		return "Less than a second ago"
+		return "less than a second"
 	case seconds == 1:
-		return "1 second ago"
+		return "1 second"
 	case seconds < 60:
-		return fmt.Sprintf("%d seconds ago", seconds)
+		return fmt.Sprintf("%d seconds", seconds)
 	case minutes == 1:
-		return "About a minute ago"
+		return "about a minute"
 	case minutes < 60:
-		return fmt.Sprintf("%d minutes ago", minutes)
+		return fmt.Sprintf("%d minutes", minutes)
 	case hours == 1:
-		return "About an hour ago"
+		return "about an hour"
 	case hours < 48:
-		return fmt.Sprintf("%d hours ago", hours)
+		return fmt.Sprintf("%d hours", hours)
+	case hours < 24*7*2 && future:
+		return fmt.Sprintf("%d days", (hours+12)/24)
 	case hours < 24*7*2:
-		return fmt.Sprintf("%d days ago", hours/24)
+		return fmt.Sprintf("%d days", hours/24)
 	case hours < 24*30*2:
-		return fmt.Sprintf("%d weeks ago", hours/24/7)
+		return fmt.Sprintf("%d weeks", hours/24/7)
 	case hours < 24*365*2:
-		return fmt.Sprintf("%d months ago", hours/24/30)
+		return fmt.Sprintf("%d months", hours/24/30)
 	default:
-		return fmt.Sprintf("%d years ago", int(timePassed.Hours())/24/365)
+		return fmt.Sprintf("%d years", int(d.Hours())/24/365)
 	}
 }
 
@@ -91,7 +215,7 @@ func ParseAbsolute(value string) (time.Time, error) {
 	if err == nil {
 		return t, nil
 	}
-	t, err = time.Parse(iso8601Layout, value)
+	t, err = time.Parse(ISO8601Layout, value)
 	if err == nil {
 		return t, nil
 	}
@@ -102,48 +226,219 @@ func ParseAbsolute(value string) (time.Time, error) {
 	return time.Parse(time.UnixDate, value)
 }
 
+// Parse parses absolute date or relative expression such as "3 days ago", "in 2 weeks", "+2w", "-3d", "next friday",
+// "monday", "end of month", "start of week" or ISO week date "2020-W41-3". Words in expression can be separated by
+// dashes instead of spaces, e.g. "next-friday". Offsets given in days, weeks, months or years are calendar days, so
+// "in 3 days" and "+3d" return midnight.
 func Parse(value string) (time.Time, error) {
 	t, err := ParseAbsolute(value)
 	if err == nil {
 		return t, nil
 	}
-	var amount time.Duration
-	var unit string
-	_, err = fmt.Sscanf(value, "%d %s ago", &amount, &unit)
-	if err == nil {
-		unit = strings.ToLower(unit)
-		switch unit {
-		case "seconds", "second":
-			return now().Add(-time.Second * amount), nil
-		case "minutes", "minute":
-			return now().Add(-time.Minute * amount), nil
-		case "hours", "hour":
-			return now().Add(-time.Hour * amount), nil
-		case "days", "day":
-			return now().Add(-time.Hour * 24 * amount), nil
-		case "weeks", "week":
-			return now().Add(-time.Hour * 24 * 7 * amount), nil
-		case "months", "month":
-			return now().Add(-time.Hour * 30 * 24 * 7 * amount), nil
-		case "years", "year":
-			return now().Add(-time.Hour * 365 * 30 * 24 * 7 * amount), nil
+	if t, ok := parseISOWeek(value); ok {
+		return t, nil
+	}
+	if t, ok := parseShorthand(value); ok {
+		return t, nil
+	}
+	if t, ok := parseExpression(strings.Fields(normalizeExpression(value))); ok {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%w: %s", ErrUnsupportedDate, value)
+}
+
+func normalizeExpression(value string) string {
+	value = strings.ToLower(value)
+	value = strings.ReplaceAll(value, "-", " ")
+	return strings.ReplaceAll(value, "_", " ")
+}
+
+func parseExpression(words []string) (time.Time, bool) {
+	switch len(words) {
+	case 1:
+		return parseWord(words[0])
+	case 2:
+		weekday, ok := weekdays[words[1]]
+		if !ok {
+			return time.Time{}, false
+		}
+		switch words[0] {
+		case "next":
+			return nextWeekday(weekday, false), true
+		case "last":
+			return lastWeekday(weekday), true
+		case "this":
+			return nextWeekday(weekday, true), true
+		}
+	case 3:
+		if words[2] == "ago" {
+			amount, err := strconv.Atoi(words[0])
+			if err != nil {
+				return time.Time{}, false
+			}
+			return addUnits(now(), -amount, words[1])
+		}
+		if words[0] == "in" {
+			amount, err := strconv.Atoi(words[1])
+			if err != nil {
+				return time.Time{}, false
+			}
+			return addCalendarUnits(amount, words[2])
 		}
+		if words[1] == "of" {
+			return boundaryOf(words[0], words[2])
+		}
+	}
+	return time.Time{}, false
+}
+
+func parseWord(word string) (time.Time, bool) {
+	switch word {
+	case "now":
+		return now(), true
+	case "today":
+		return midnight(now()), true
+	case "yesterday":
+		return midnight(now().AddDate(0, 0, -1)), true
+	case "tomorrow":
+		return midnight(now().AddDate(0, 0, 1)), true
+	}
+	if weekday, ok := weekdays[word]; ok {
+		return nextWeekday(weekday, true), true
 	}
-	if value == "now" {
-		return now(), nil
+	return time.Time{}, false
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+	"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// nextWeekday returns midnight of the nearest given weekday after today (or today if includeToday is true)
+func nextWeekday(weekday time.Weekday, includeToday bool) time.Time {
+	today := midnight(now())
+	days := (int(weekday) - int(today.Weekday()) + 7) % 7
+	if days == 0 && !includeToday {
+		days = 7
+	}
+	return today.AddDate(0, 0, days)
+}
+
+// lastWeekday returns midnight of the nearest given weekday before today
+func lastWeekday(weekday time.Weekday) time.Time {
+	today := midnight(now())
+	days := (int(today.Weekday()) - int(weekday) + 7) % 7
+	if days == 0 {
+		days = 7
+	}
+	return today.AddDate(0, 0, -days)
+}
+
+func addUnits(t time.Time, amount int, unit string) (time.Time, bool) {
+	switch unit {
+	case "seconds", "second":
+		return t.Add(time.Second * time.Duration(amount)), true
+	case "minutes", "minute":
+		return t.Add(time.Minute * time.Duration(amount)), true
+	case "hours", "hour":
+		return t.Add(time.Hour * time.Duration(amount)), true
+	case "days", "day":
+		return t.AddDate(0, 0, amount), true
+	case "weeks", "week":
+		return t.AddDate(0, 0, 7*amount), true
+	case "months", "month":
+		return t.AddDate(0, amount, 0), true
+	case "years", "year":
+		return t.AddDate(amount, 0, 0), true
+	}
+	return time.Time{}, false
+}
+
+// addCalendarUnits adds amount of units to now. Days, weeks, months and years are added to midnight.
+func addCalendarUnits(amount int, unit string) (time.Time, bool) {
+	switch unit {
+	case "seconds", "second", "minutes", "minute", "hours", "hour":
+		return addUnits(now(), amount, unit)
+	}
+	return addUnits(midnight(now()), amount, unit)
+}
+
+var shorthandUnits = map[string]string{
+	"s": "seconds", "min": "minutes", "h": "hours", "d": "days", "w": "weeks", "m": "months", "y": "years",
+}
+
+var shorthandRegex = regexp.MustCompile(`^([+-])(\d+)(s|min|h|d|w|m|y)$`)
+
+// parseShorthand parses expressions such as +2w, -3d, +1m (month) or +6h
+func parseShorthand(value string) (time.Time, bool) {
+	match := shorthandRegex.FindStringSubmatch(strings.ToLower(value))
+	if match == nil {
+		return time.Time{}, false
+	}
+	amount, err := strconv.Atoi(match[2])
+	if err != nil {
+		return time.Time{}, false
+	}
+	if match[1] == "-" {
+		amount = -amount
+	}
+	return addCalendarUnits(amount, shorthandUnits[match[3]])
+}
+
+// boundaryOf parses expressions such as "start of week" or "end of month". Weeks start on Monday.
+func boundaryOf(boundary, period string) (time.Time, bool) {
+	today := midnight(now())
+	var start time.Time
+	var next func(time.Time) time.Time
+	switch period {
+	case "week":
+		start = today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+		next = func(t time.Time) time.Time { return t.AddDate(0, 0, 7) }
+	case "month":
+		start = time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, today.Location())
+		next = func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }
+	case "year":
+		start = time.Date(today.Year(), time.January, 1, 0, 0, 0, 0, today.Location())
+		next = func(t time.Time) time.Time { return t.AddDate(1, 0, 0) }
+	default:
+		return time.Time{}, false
+	}
+	switch boundary {
+	case "start", "beginning":
+		return start, true
+	case "end":
+		return next(start).AddDate(0, 0, -1), true
+	}
+	return time.Time{}, false
+}
+
+var isoWeekRegex = regexp.MustCompile(`^(\d{4})-?W(\d{2})(?:-?([1-7]))?$`)
+
+// parseISOWeek parses ISO week date such as 2020-W41 (Monday of the week) or 2020-W41-3 (Wednesday)
+func parseISOWeek(value string) (time.Time, bool) {
+	match := isoWeekRegex.FindStringSubmatch(strings.ToUpper(value))
+	if match == nil {
+		return time.Time{}, false
 	}
-	if value == "today" {
-		return midnight(now()), nil
+	year, _ := strconv.Atoi(match[1])
+	week, _ := strconv.Atoi(match[2])
+	day := 1
+	if match[3] != "" {
+		day, _ = strconv.Atoi(match[3])
 	}
-	if value == "yesterday" {
-		yesterday := now().AddDate(0, 0, -1)
-		return midnight(yesterday), nil
+	if week < 1 || week > 53 {
+		return time.Time{}, false
 	}
-	if value == "tomorrow" {
-		tomorrow := now().AddDate(0, 0, 1)
-		return midnight(tomorrow), nil
+	// 4th of January is always in the first week
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.Local)
+	mondayOfFirstWeek := jan4.AddDate(0, 0, -((int(jan4.Weekday()) + 6) % 7))
+	t := mondayOfFirstWeek.AddDate(0, 0, (week-1)*7+day-1)
+	if _, w := t.ISOWeek(); w != week {
+		return time.Time{}, false
 	}
-	return time.Time{}, errors.New("not supported date format: " + value)
+	return t, true
 }
 
 func midnight(t time.Time) time.Time {
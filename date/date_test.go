@@ -75,3 +75,142 @@ func TestFormatISO8601(t *testing.T) {
 	// then
 	assert.Equal(t, "2020-10-15 16:30:10 +0200", f)
 }
+
+func TestFormatRelative(t *testing.T) {
+	current := time.Date(2020, 9, 5, 12, 30, 0, 0, time.UTC)
+	date.SetNow(func() time.Time {
+		return current
+	})
+	defer date.SetNow(time.Now)
+	tests := map[string]time.Time{
+		"Less than a second ago": current,
+		"About a minute ago":     current.Add(-time.Minute),
+		"5 hours ago":            current.Add(-5 * time.Hour),
+		"3 days ago":             current.AddDate(0, 0, -3),
+		"2 days ago":             current.AddDate(0, 0, -3).Add(4 * time.Hour),
+		"in 1 second":            current.Add(time.Second),
+		"in about an hour":       current.Add(time.Hour),
+		"in 3 days":              current.AddDate(0, 0, 3).Add(-8 * time.Hour),
+		"in 2 weeks":             current.AddDate(0, 0, 14),
+	}
+	for expected, tm := range tests {
+		t.Run(expected, func(t *testing.T) {
+			// when
+			formatted := date.FormatRelative(tm)
+			// then
+			assert.Equal(t, expected, formatted)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	// Wednesday
+	givenNow := time.Date(2020, 10, 14, 16, 30, 10, 0, time.UTC)
+	date.SetNow(func() time.Time {
+		return givenNow
+	})
+	defer date.SetNow(time.Now)
+
+	tests := map[string]time.Time{
+		"now":                  givenNow,
+		"today":                time.Date(2020, 10, 14, 0, 0, 0, 0, time.UTC),
+		"tomorrow":             time.Date(2020, 10, 15, 0, 0, 0, 0, time.UTC),
+		"3 days ago":           time.Date(2020, 10, 11, 16, 30, 10, 0, time.UTC),
+		"2 months ago":         time.Date(2020, 8, 14, 16, 30, 10, 0, time.UTC),
+		"1 year ago":           time.Date(2019, 10, 14, 16, 30, 10, 0, time.UTC),
+		"3-days-ago":           time.Date(2020, 10, 11, 16, 30, 10, 0, time.UTC),
+		"in 3 days":            time.Date(2020, 10, 17, 0, 0, 0, 0, time.UTC),
+		"in 2 hours":           time.Date(2020, 10, 14, 18, 30, 10, 0, time.UTC),
+		"in-1-month":           time.Date(2020, 11, 14, 0, 0, 0, 0, time.UTC),
+		"+2w":                  time.Date(2020, 10, 28, 0, 0, 0, 0, time.UTC),
+		"-3d":                  time.Date(2020, 10, 11, 0, 0, 0, 0, time.UTC),
+		"+1y":                  time.Date(2021, 10, 14, 0, 0, 0, 0, time.UTC),
+		"+6h":                  time.Date(2020, 10, 14, 22, 30, 10, 0, time.UTC),
+		"friday":               time.Date(2020, 10, 16, 0, 0, 0, 0, time.UTC),
+		"wednesday":            time.Date(2020, 10, 14, 0, 0, 0, 0, time.UTC),
+		"next wednesday":       time.Date(2020, 10, 21, 0, 0, 0, 0, time.UTC),
+		"next-friday":          time.Date(2020, 10, 16, 0, 0, 0, 0, time.UTC),
+		"Next Monday":          time.Date(2020, 10, 19, 0, 0, 0, 0, time.UTC),
+		"last friday":          time.Date(2020, 10, 9, 0, 0, 0, 0, time.UTC),
+		"end of month":         time.Date(2020, 10, 31, 0, 0, 0, 0, time.UTC),
+		"start-of-month":       time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC),
+		"start of week":        time.Date(2020, 10, 12, 0, 0, 0, 0, time.UTC),
+		"end of week":          time.Date(2020, 10, 18, 0, 0, 0, 0, time.UTC),
+		"end of year":          time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC),
+		"beginning of year":    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		"2020-W42":             time.Date(2020, 10, 12, 0, 0, 0, 0, time.Local),
+		"2020-W42-3":           time.Date(2020, 10, 14, 0, 0, 0, 0, time.Local),
+		"2021W01":              time.Date(2021, 1, 4, 0, 0, 0, 0, time.Local),
+		"2020-W53-7":           time.Date(2021, 1, 3, 0, 0, 0, 0, time.Local),
+		"2020-10-15":           time.Date(2020, 10, 15, 0, 0, 0, 0, time.Local),
+		"2020-10-15T16:30:00Z": time.Date(2020, 10, 15, 16, 30, 0, 0, time.UTC),
+	}
+	for given, expected := range tests {
+		t.Run(given, func(t *testing.T) {
+			actual, err := date.Parse(given)
+			require.NoError(t, err)
+			assert.True(t, expected.Equal(actual), "expected %s, got %s", expected, actual)
+		})
+	}
+
+	t.Run("should return error", func(t *testing.T) {
+		for _, given := range []string{"", "someday", "in x days", "next month", "+2x", "2019-W53", "2020-W00"} {
+			t.Run(given, func(t *testing.T) {
+				_, err := date.Parse(given)
+				assert.ErrorIs(t, err, date.ErrUnsupportedDate)
+			})
+		}
+	})
+}
+
+func TestStrftimeToLayout(t *testing.T) {
+	t.Run("should convert literal text", func(t *testing.T) {
+		// when
+		layout, err := date.StrftimeToLayout("%Y-%m-%d at %H:%M, day %j")
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "2006-01-02 at 15:04, day 002", layout)
+	})
+
+	t.Run("should return error for literal text which is Go layout element", func(t *testing.T) {
+		for _, pattern := range []string{"%Y week 1", "%d Mon", "%H:%M PM", "%Y January", "%d 2006", "%Y MST"} {
+			t.Run(pattern, func(t *testing.T) {
+				// when
+				_, err := date.StrftimeToLayout(pattern)
+				// then
+				assert.Error(t, err)
+			})
+		}
+	})
+}
+
+func TestParseFormat(t *testing.T) {
+	given := time.Date(2020, 10, 15, 16, 30, 10, 0, time.FixedZone("CEST", 60*60*2))
+
+	tests := map[string]string{
+		"iso":                          "2020-10-15 16:30:10 +0200",
+		"RFC2822":                      "Thu, 15 Oct 2020 16:30:10 +0200",
+		"format:%Y-%m-%d":              "2020-10-15",
+		"format:%d %B %Y, %H:%M %Z":    "15 October 2020, 16:30 CEST",
+		"format:%F %T %z":              "2020-10-15 16:30:10 +0200",
+		"format:%d%%":                  "15%",
+		"format:2006-01-02 15:04":      "2020-10-15 16:30",
+		"format:Monday, Jan _2 3:04PM": "Thursday, Oct 15 4:30PM",
+	}
+	for name, expected := range tests {
+		t.Run(name, func(t *testing.T) {
+			f, err := date.ParseFormat(name)
+			require.NoError(t, err)
+			assert.Equal(t, expected, date.FormatWithType(given, f))
+		})
+	}
+
+	t.Run("should return error", func(t *testing.T) {
+		for _, name := range []string{"unknown", "format:", "format:%Q", "format:%Y%"} {
+			t.Run(name, func(t *testing.T) {
+				_, err := date.ParseFormat(name)
+				assert.Error(t, err)
+			})
+		}
+	})
+}
@@ -0,0 +1,140 @@
+// Package ignore matches paths against gitignore-style patterns
+package ignore
+
+import (
+	"bufio"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Matcher holds patterns from many ignore files. Paths are slash separated and relative to the same root
+// directory, e.g. repository root.
+type Matcher struct {
+	rules []rule
+}
+
+type rule struct {
+	dir     string // directory of the ignore file, "" for the root
+	regex   *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// Add adds patterns read from the ignore file located in dir. Patterns apply only to paths inside dir. Files
+// should be added from the root down, because patterns added later take precedence.
+func (m *Matcher) Add(dir string, patterns io.Reader) error {
+	dir = path.Clean(dir)
+	if dir == "." {
+		dir = ""
+	}
+	scanner := bufio.NewScanner(patterns)
+	for scanner.Scan() {
+		if r, ok := parseRule(scanner.Text()); ok {
+			r.dir = dir
+			m.rules = append(m.rules, r)
+		}
+	}
+	return scanner.Err()
+}
+
+// AddPatterns adds patterns given as strings, for example defaults, which apply to the whole root directory
+func (m *Matcher) AddPatterns(patterns ...string) {
+	for _, pattern := range patterns {
+		if r, ok := parseRule(pattern); ok {
+			m.rules = append(m.rules, r)
+		}
+	}
+}
+
+// Match returns true when path is ignored. The last matching pattern wins, so negated pattern, such as !keep.md,
+// includes back a path excluded by earlier patterns.
+func (m *Matcher) Match(p string, isDir bool) bool {
+	p = path.Clean(p)
+	ignored := false
+	for _, r := range m.rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		rel := p
+		if r.dir != "" {
+			if !strings.HasPrefix(p, r.dir+"/") {
+				continue
+			}
+			rel = p[len(r.dir)+1:]
+		}
+		if r.regex.MatchString(rel) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+func parseRule(line string) (rule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return rule{}, false
+	}
+	var r rule
+	if strings.HasPrefix(line, "!") {
+		r.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\#`) || strings.HasPrefix(line, `\!`) {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		r.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return rule{}, false
+	}
+	// pattern with a slash at the beginning or in the middle is relative to the directory of the ignore file,
+	// otherwise it matches a name at any level
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+	prefix := "^"
+	if !anchored {
+		prefix = "^(?:.*/)?"
+	}
+	r.regex = regexp.MustCompile(prefix + toRegex(line) + "$")
+	return r, true
+}
+
+func toRegex(pattern string) string {
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,81 @@
+package ignore_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/noteo/ignore"
+)
+
+func TestMatcher_Match(t *testing.T) {
+	tests := map[string]struct {
+		patterns string
+		path     string
+		isDir    bool
+		ignored  bool
+	}{
+		"name at any level":                 {patterns: "README.md", path: "vendor/lib/README.md", ignored: true},
+		"different name":                    {patterns: "README.md", path: "notes/todo.md"},
+		"comment":                           {patterns: "# README.md", path: "README.md"},
+		"escaped hash":                      {patterns: `\#draft.md`, path: "#draft.md", ignored: true},
+		"star":                              {patterns: "draft-*.md", path: "sub/draft-1.md", ignored: true},
+		"star does not match slash":         {patterns: "a/*.md", path: "a/b/c.md"},
+		"question mark":                     {patterns: "note?.md", path: "note1.md", ignored: true},
+		"character class":                   {patterns: "note[0-9].md", path: "note5.md", ignored: true},
+		"negated character class":           {patterns: "note[!0-9].md", path: "note5.md"},
+		"anchored with leading slash":       {patterns: "/todo.md", path: "sub/todo.md"},
+		"anchored with slash in the middle": {patterns: "sub/todo.md", path: "sub/todo.md", ignored: true},
+		"double star prefix":                {patterns: "**/build", path: "a/b/build", isDir: true, ignored: true},
+		"double star suffix":                {patterns: "archive/**", path: "archive/2020/a.md", ignored: true},
+		"double star in the middle":         {patterns: "a/**/z.md", path: "a/b/c/z.md", ignored: true},
+		"double star matching zero dirs":    {patterns: "a/**/z.md", path: "a/z.md", ignored: true},
+		"dir only pattern and dir":          {patterns: "node_modules/", path: "node_modules", isDir: true, ignored: true},
+		"dir only pattern and file":         {patterns: "node_modules/", path: "node_modules"},
+		"negation":                          {patterns: "*.md\n!keep.md", path: "keep.md"},
+		"last match wins":                   {patterns: "!keep.md\n*.md", path: "keep.md", ignored: true},
+		"trailing spaces":                   {patterns: "todo.md  ", path: "todo.md", ignored: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var m ignore.Matcher
+			require.NoError(t, m.Add("", strings.NewReader(test.patterns)))
+			// when
+			ignored := m.Match(test.path, test.isDir)
+			// then
+			assert.Equal(t, test.ignored, ignored)
+		})
+	}
+
+	t.Run("patterns apply only to paths inside directory of the ignore file", func(t *testing.T) {
+		var m ignore.Matcher
+		require.NoError(t, m.Add("sub", strings.NewReader("/todo.md")))
+		// expect
+		assert.True(t, m.Match("sub/todo.md", false))
+		assert.False(t, m.Match("todo.md", false))
+		assert.False(t, m.Match("other/todo.md", false))
+		assert.False(t, m.Match("subdir/todo.md", false))
+	})
+
+	t.Run("patterns from nested ignore file take precedence", func(t *testing.T) {
+		var m ignore.Matcher
+		require.NoError(t, m.Add("", strings.NewReader("*.md")))
+		require.NoError(t, m.Add("keep", strings.NewReader("!*.md")))
+		// expect
+		assert.True(t, m.Match("a.md", false))
+		assert.False(t, m.Match("keep/a.md", false))
+	})
+
+	t.Run("default patterns", func(t *testing.T) {
+		var m ignore.Matcher
+		m.AddPatterns(".*/")
+		require.NoError(t, m.Add("", strings.NewReader("!.github/")))
+		// expect
+		assert.True(t, m.Match(".git", true))
+		assert.True(t, m.Match("sub/.cache", true))
+		assert.False(t, m.Match(".github", true))
+		assert.False(t, m.Match(".hidden.md", false))
+	})
+}
@@ -0,0 +1,325 @@
+// Package listing builds filters, sorting and output of notes listed by ls and other commands listing notes. It is
+// shared by the command line tool and the Go library.
+package listing
+
+import (
+	"regexp"
+
+	"github.com/spf13/pflag"
+
+	"github.com/elgopher/noteo/notes"
+	noteotag "github.com/elgopher/noteo/tag"
+)
+
+// Filter builds predicates from filtering flags shared by commands listing notes
+type Filter struct {
+	tagFilter      []string
+	notagFilter    []string
+	tagGrep        []string
+	tagGreater     []string
+	tagLower       []string
+	tagIn          []string
+	tagEq          []string
+	tagExists      []string
+	tagRange       []string
+	tagBetween     []string
+	tagAfter       []string
+	tagBefore      []string
+	noTags         bool
+	modifiedAfter  string
+	modifiedBefore string
+	createdAfter   string
+	createdBefore  string
+	grep           string
+	hasOpenTasks   bool
+	// vocabulary is used to compare values of declared tags, can be nil
+	vocabulary *noteotag.Vocabulary
+}
+
+// AddFlags adds filtering flags, which usage is FilteringFlagsUsage
+func (c *Filter) AddFlags(flags *pflag.FlagSet) {
+	flags.StringArrayVarP(&c.tagFilter, "tag", "t", nil, "")
+	flags.StringArrayVar(&c.notagFilter, "no-tag", nil, "")
+	flags.StringArrayVar(&c.tagGrep, "tag-grep", nil, "")
+	flags.StringArrayVar(&c.tagGreater, "tag-greater", nil, "")
+	flags.StringArrayVar(&c.tagLower, "tag-lower", nil, "")
+	flags.StringArrayVar(&c.tagIn, "tag-in", nil, "")
+	flags.StringArrayVar(&c.tagEq, "tag-eq", nil, "")
+	flags.StringArrayVar(&c.tagExists, "tag-exists", nil, "")
+	flags.StringArrayVar(&c.tagRange, "tag-range", nil, "")
+	flags.StringArrayVar(&c.tagBetween, "tag-between", nil, "")
+	flags.StringArrayVar(&c.tagAfter, "tag-after", nil, "")
+	flags.StringArrayVar(&c.tagBefore, "tag-before", nil, "")
+	flags.BoolVar(&c.noTags, "no-tags", false, "")
+	flags.StringVar(&c.modifiedAfter, "modified-after", "", "")
+	flags.StringVar(&c.modifiedBefore, "modified-before", "", "")
+	flags.StringVar(&c.createdAfter, "created-after", "", "")
+	flags.StringVar(&c.createdBefore, "created-before", "", "")
+	flags.StringVar(&c.grep, "grep", "", "")
+	flags.BoolVar(&c.hasOpenTasks, "has-open-tasks", false, "")
+}
+
+// FilteringFlagsUsage describes flags added by AddFlags
+const FilteringFlagsUsage = `      --created-after <date>        filter notes created after given date
+      --created-before <date>       filter notes created before given date
+      --grep <regex>                grep text using regular expression
+      --has-open-tasks              filter notes having task list items which are not done, e.g. "- [ ] task"
+      --modified-after <date>       filter notes modified after given date
+      --modified-before <date>      filter notes modified before given date
+      --no-tag <name>               filter notes not having tag. Flag can be specified multiple times.
+      --no-tags                     filter notes not having tags at all
+  -t, --tag <name>                  filter notes having tag. Flag can be specified multiple times.
+      --tag-after <name:date>       filter notes having tag with value date after specified date, e.g. "foo:2010-08-01". Flag can be specified multiple times.
+      --tag-before <name:date>      filter notes having tag with value date before specified date, e.g. "foo:2010-08-01". Flag can be specified multiple times.
+      --tag-between <name:from..to> filter notes having tag with value date between specified dates (inclusive), e.g. "foo:today..tomorrow". Flag can be specified multiple times.
+      --tag-eq <name:value>         filter notes having tag with value equal to specified value, e.g. "foo:1" matches "foo:01". Flag can be specified multiple times.
+      --tag-exists <name>           filter notes having tag with given name and any value. Flag can be specified multiple times.
+      --tag-greater <name:value>    filter notes having tag with value (number, duration or date) greater than specified value e.g. "foo:2.5", "foo:2h" or "deadline:today". Flag can be specified multiple times.
+      --tag-grep <regex>            filter notes having tag matching regular expression. Flag can be specified multiple times.
+      --tag-in <name:values>        filter notes having tag with one of comma separated values e.g. "status:open,blocked". Flag can be specified multiple times.
+      --tag-lower <name:value>      filter notes having tag with value (number, duration or date) lower than specified value e.g. "foo:2.5" or "foo:2h". Flag can be specified multiple times.
+      --tag-range <name:from..to>   filter notes having tag with value (number, duration or date) in range (inclusive), e.g. "foo:1..3" or "foo:2..". Flag can be specified multiple times.
+`
+
+// Predicates returns predicates of given flags. Values of tags declared in vocabulary are compared as declared types.
+// Vocabulary can be nil.
+func (c *Filter) Predicates(vocabulary *noteotag.Vocabulary) ([]notes.Predicate, error) {
+	c.vocabulary = vocabulary
+	var predicates []notes.Predicate
+	for _, createPredicates := range []func() ([]notes.Predicate, error){
+		c.tagFilterPredicates,
+		c.notagFilterPredicates,
+		c.tagGrepPredicates,
+		c.tagGreaterPredicates,
+		c.tagLowerPredicates,
+		c.tagInPredicates,
+		c.tagEqPredicates,
+		c.tagExistsPredicates,
+		c.tagRangePredicates,
+		c.tagBetweenPredicates,
+		c.tagAfterPredicates,
+		c.tagBeforePredicates,
+		c.notagsPredicates,
+		c.modifiedAfterPredicates,
+		c.modifiedBeforePredicates,
+		c.createdAfterPredicates,
+		c.createdBeforePredicates,
+		c.grepPredicates,
+		c.hasOpenTasksPredicates,
+	} {
+		p, err := createPredicates()
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, p...)
+	}
+	return predicates, nil
+}
+
+func (c *Filter) tagFilterPredicates() ([]notes.Predicate, error) {
+	var predicates []notes.Predicate
+	for _, t := range c.tagFilter {
+		predicates = append(predicates, notes.Tag(t))
+	}
+	return predicates, nil
+}
+
+func (c *Filter) notagFilterPredicates() ([]notes.Predicate, error) {
+	var predicates []notes.Predicate
+	for _, t := range c.notagFilter {
+		predicates = append(predicates, notes.NoTag(t))
+	}
+	return predicates, nil
+}
+
+func (c *Filter) tagGrepPredicates() ([]notes.Predicate, error) {
+	var predicates []notes.Predicate
+	for _, grep := range c.tagGrep {
+		regex, err := regexp.Compile(grep)
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, notes.TagGrep(regex))
+	}
+	return predicates, nil
+}
+
+func (c *Filter) tagGreaterPredicates() ([]notes.Predicate, error) {
+	var predicates []notes.Predicate
+	for _, greater := range c.tagGreater {
+		p, err := notes.TagGreater(greater)
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, p)
+	}
+	return predicates, nil
+}
+
+func (c *Filter) tagLowerPredicates() ([]notes.Predicate, error) {
+	var predicates []notes.Predicate
+	for _, lower := range c.tagLower {
+		p, err := notes.TagLower(lower)
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, p)
+	}
+	return predicates, nil
+}
+
+func (c *Filter) tagInPredicates() ([]notes.Predicate, error) {
+	var predicates []notes.Predicate
+	for _, in := range c.tagIn {
+		p, err := notes.TagIn(in, c.vocabulary)
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, p)
+	}
+	return predicates, nil
+}
+
+func (c *Filter) tagEqPredicates() ([]notes.Predicate, error) {
+	var predicates []notes.Predicate
+	for _, eq := range c.tagEq {
+		p, err := notes.TagEq(eq, c.vocabulary)
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, p)
+	}
+	return predicates, nil
+}
+
+func (c *Filter) tagExistsPredicates() ([]notes.Predicate, error) {
+	var predicates []notes.Predicate
+	for _, name := range c.tagExists {
+		predicates = append(predicates, notes.TagExists(name))
+	}
+	return predicates, nil
+}
+
+func (c *Filter) tagRangePredicates() ([]notes.Predicate, error) {
+	var predicates []notes.Predicate
+	for _, r := range c.tagRange {
+		p, err := notes.TagRange(r)
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, p)
+	}
+	return predicates, nil
+}
+
+func (c *Filter) tagBetweenPredicates() ([]notes.Predicate, error) {
+	var predicates []notes.Predicate
+	for _, between := range c.tagBetween {
+		p, err := notes.TagBetween(between)
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, p)
+	}
+	return predicates, nil
+}
+
+func (c *Filter) tagAfterPredicates() ([]notes.Predicate, error) {
+	var predicates []notes.Predicate
+	for _, after := range c.tagAfter {
+		p, err := notes.TagAfter(after)
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, p)
+	}
+	return predicates, nil
+}
+
+func (c *Filter) tagBeforePredicates() ([]notes.Predicate, error) {
+	var predicates []notes.Predicate
+	for _, before := range c.tagBefore {
+		p, err := notes.TagBefore(before)
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, p)
+	}
+	return predicates, nil
+}
+
+func (c *Filter) notagsPredicates() ([]notes.Predicate, error) {
+	var predicates []notes.Predicate
+	if c.noTags {
+		predicates = append(predicates, notes.NoTags())
+	}
+	return predicates, nil
+}
+
+func (c *Filter) modifiedAfterPredicates() ([]notes.Predicate, error) {
+	var predicates []notes.Predicate
+	if c.modifiedAfter != "" {
+		p, err := notes.ModifiedAfter(c.modifiedAfter)
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, p)
+	}
+	return predicates, nil
+}
+
+func (c *Filter) modifiedBeforePredicates() ([]notes.Predicate, error) {
+	var predicates []notes.Predicate
+	if c.modifiedBefore != "" {
+		p, err := notes.ModifiedBefore(c.modifiedBefore)
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, p)
+	}
+	return predicates, nil
+}
+
+func (c *Filter) createdAfterPredicates() ([]notes.Predicate, error) {
+	var predicates []notes.Predicate
+	if c.createdAfter != "" {
+		p, err := notes.CreatedAfter(c.createdAfter)
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, p)
+	}
+	return predicates, nil
+}
+
+func (c *Filter) createdBeforePredicates() ([]notes.Predicate, error) {
+	var predicates []notes.Predicate
+	if c.createdBefore != "" {
+		p, err := notes.CreatedBefore(c.createdBefore)
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, p)
+	}
+	return predicates, nil
+}
+
+func (c *Filter) grepPredicates() ([]notes.Predicate, error) {
+	var predicates []notes.Predicate
+	if c.grep != "" {
+		p, err := notes.Grep(c.grep)
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, p)
+	}
+	return predicates, nil
+}
+
+func (c *Filter) hasOpenTasksPredicates() ([]notes.Predicate, error) {
+	var predicates []notes.Predicate
+	if c.hasOpenTasks {
+		predicates = append(predicates, notes.HasOpenTasks())
+	}
+	return predicates, nil
+}
@@ -0,0 +1,170 @@
+package listing
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/elgopher/noteo/date"
+	"github.com/elgopher/noteo/notes"
+	"github.com/elgopher/noteo/output/jayson"
+	"github.com/elgopher/noteo/output/jsonpath"
+	"github.com/elgopher/noteo/output/quiet"
+	"github.com/elgopher/noteo/output/table"
+	"github.com/elgopher/noteo/output/tmpl"
+	"github.com/elgopher/noteo/output/yml"
+)
+
+// Formatter formats listed notes
+type Formatter interface {
+	Header() string
+	Note(note notes.Note) string
+	Footer() string
+}
+
+// Output configures formatter of listed notes. Zero Output is the default table.
+type Output struct {
+	// Format is table, wide, json, yaml, table with columns (e.g. table=file,tags), template=<go template>,
+	// template-file=<path>, jsonpath=<template> or jsonpath-file=<path>. Table is used when empty.
+	Format string
+	// Columns of table format without columns, separated with commas. Default columns are used when empty.
+	Columns string
+	// DateFormat is relative, iso8601, rfc2822 or format:<layout>. Default of the output format is used when empty.
+	DateFormat string
+	// Timezone is IANA time zone of dates, e.g. Europe/Warsaw. Local time is used when empty.
+	Timezone string
+	// Quiet shows only file names
+	Quiet bool
+	// RepoColumn adds repo column in front of other columns, e.g. when notes of all repositories are listed
+	RepoColumn bool
+	// Colors enables colors in table output
+	Colors bool
+}
+
+const (
+	defaultFormat  = "table"
+	defaultColumns = "file,beginning,modified,tags"
+)
+
+// NewFormatter returns formatter of the output
+func NewFormatter(o Output) (Formatter, error) {
+	format := o.Format
+	if format == "" {
+		format = defaultFormat
+	}
+	// value of template and jsonpath formats is case-sensitive
+	name, value, hasValue := strings.Cut(format, "=")
+	name = strings.ToLower(name)
+	tableDateFormat, err := o.dateFormat(date.Relative)
+	if err != nil {
+		return nil, err
+	}
+	// marshalled formats use native date representation unless date format was given explicitly
+	marshalledDateFormat, err := o.dateFormat("")
+	if err != nil {
+		return nil, err
+	}
+	templateDateFormat, err := o.dateFormat(date.ISO8601)
+	if err != nil {
+		return nil, err
+	}
+	location, err := o.location()
+	if err != nil {
+		return nil, err
+	}
+	if name == "table" && !hasValue {
+		value, hasValue = o.Columns, true
+		if value == "" {
+			value = defaultColumns
+		}
+	}
+	var out Formatter
+	switch {
+	case o.Quiet:
+		out = quiet.Formatter{}
+	case name == "wide" && !hasValue:
+		columns := []string{"file", "beginning", "modified", "created", "tags"}
+		out, err = o.tableFormatter(columns, tableDateFormat, location)
+	case name == "table":
+		out, err = o.tableFormatter(strings.Split(value, ","), tableDateFormat, location)
+	case name == "json" && !hasValue:
+		out = jayson.Formatter{DateFormat: marshalledDateFormat, Location: location}
+	case name == "yaml" && !hasValue:
+		out = yml.Formatter{DateFormat: marshalledDateFormat, Location: location}
+	case name == "template" || name == "template-file":
+		out, err = templateFormatter(name, value, func(text string) (Formatter, error) {
+			return tmpl.New(text, templateDateFormat, location)
+		})
+	case name == "jsonpath" || name == "jsonpath-file":
+		out, err = templateFormatter(name, value, func(text string) (Formatter, error) {
+			return jsonpath.New(text, marshalledDateFormat, location)
+		})
+	default:
+		err = fmt.Errorf("unsupported output format: %s", format)
+	}
+	return out, err
+}
+
+// dateFormat returns DateFormat, otherwise defaultFormat
+func (o Output) dateFormat(defaultFormat date.Format) (date.Format, error) {
+	if o.DateFormat != "" {
+		return date.ParseFormat(o.DateFormat)
+	}
+	return defaultFormat, nil
+}
+
+// location returns location of Timezone. Returns nil if none was given.
+func (o Output) location() (*time.Location, error) {
+	if o.Timezone == "" {
+		return nil, nil
+	}
+	location, err := time.LoadLocation(o.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported time zone: %v", err)
+	}
+	return location, nil
+}
+
+// templateFormatter returns formatter using template given in value, or in a file when name ends with -file.
+// Escape sequences \t and \n given in value are replaced with a tab and a new line, which are hard to type in shell.
+// Template files are used as they are.
+func templateFormatter(name, value string, newFormatter func(text string) (Formatter, error)) (Formatter, error) {
+	var text string
+	if strings.HasSuffix(name, "-file") {
+		content, err := os.ReadFile(value)
+		if err != nil {
+			return nil, err
+		}
+		text = string(content)
+	} else {
+		text = strings.NewReplacer(`\t`, "\t", `\n`, "\n").Replace(value)
+	}
+	out, err := newFormatter(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s output: %v", strings.TrimSuffix(name, "-file"), err)
+	}
+	return out, nil
+}
+
+func (o Output) tableFormatter(columns []string, dateFormat date.Format, location *time.Location) (*table.Formatter, error) {
+	if o.RepoColumn {
+		columns = withRepoColumn(columns)
+	}
+	out, err := table.NewFormatter(columns, dateFormat, location)
+	if err != nil {
+		return nil, err
+	}
+	out.SetColorCapable(o.Colors)
+	return out, nil
+}
+
+// withRepoColumn adds repo column in front of other columns unless it is already given
+func withRepoColumn(columns []string) []string {
+	for _, column := range columns {
+		if strings.EqualFold(column, "repo") {
+			return columns
+		}
+	}
+	return append([]string{"repo"}, columns...)
+}
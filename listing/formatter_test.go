@@ -0,0 +1,46 @@
+package listing_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/noteo/listing"
+	"github.com/elgopher/noteo/note"
+)
+
+func TestNewFormatter(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "note.md"), []byte("---\nTags: priority:2\n---\ntext\n"), 0664))
+	n := note.NewInDir(dir, "note.md")
+
+	t.Run("should replace escape sequences in template given in command line", func(t *testing.T) {
+		formatter, err := listing.NewFormatter(listing.Output{Format: `template={{.File}}\t{{.Tag "priority"}}`})
+		require.NoError(t, err)
+		// when
+		out := formatter.Note(n)
+		// then
+		assert.Equal(t, "note.md\t2\n", out)
+	})
+
+	t.Run("should use template file as it is", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "report.tmpl")
+		require.NoError(t, os.WriteFile(file, []byte(`{{.File}}\t{{.Tag "priority"}}`), 0664))
+		formatter, err := listing.NewFormatter(listing.Output{Format: "template-file=" + file})
+		require.NoError(t, err)
+		// when
+		out := formatter.Note(n)
+		// then
+		assert.Equal(t, "note.md\\t2\n", out)
+	})
+
+	t.Run("should return error for unsupported output format", func(t *testing.T) {
+		// when
+		_, err := listing.NewFormatter(listing.Output{Format: "xml"})
+		// then
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,48 @@
+package listing
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"github.com/elgopher/noteo/notes"
+	noteotag "github.com/elgopher/noteo/tag"
+)
+
+// Query is built from ls filtering, sorting and limiting flags
+type Query struct {
+	Predicates []notes.Predicate
+	Less       notes.Less
+	// ReversedLess is the order opposite to Less
+	ReversedLess notes.Less
+	Limit        int
+}
+
+// ParseQuery parses ls filtering, sorting and limiting flags, e.g. []string{"--tag-greater", "priority:1"}.
+// Values of tags declared in vocabulary are compared as declared types. Vocabulary can be nil.
+func ParseQuery(args []string, vocabulary *noteotag.Vocabulary) (Query, error) {
+	var filter Filter
+	var sorting Sorting
+	flags := pflag.NewFlagSet("ls", pflag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	filter.AddFlags(flags)
+	sorting.AddFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return Query{}, err
+	}
+	if flags.NArg() > 0 {
+		return Query{}, fmt.Errorf("unexpected arguments: %s", strings.Join(flags.Args(), " "))
+	}
+	predicates, err := filter.Predicates(vocabulary)
+	if err != nil {
+		return Query{}, err
+	}
+	return Query{
+		Predicates:   predicates,
+		Less:         sorting.Less(),
+		ReversedLess: sorting.ReversedLess(),
+		Limit:        sorting.Limit,
+	}, nil
+}
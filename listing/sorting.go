@@ -0,0 +1,60 @@
+package listing
+
+import (
+	"math"
+
+	"github.com/spf13/pflag"
+
+	"github.com/elgopher/noteo/notes"
+)
+
+// Sorting is built from sorting and limiting flags
+type Sorting struct {
+	// Limit is the maximum number of listed notes
+	Limit           int
+	sortByCreated   bool
+	sortByTagDate   string
+	sortByTagNumber string
+	reverse         bool
+}
+
+// AddFlags adds sorting and limiting flags
+func (s *Sorting) AddFlags(flags *pflag.FlagSet) {
+	flags.IntVarP(&s.Limit, "limit", "l", math.MaxInt32, "")
+	flags.BoolVar(&s.sortByCreated, "sort-by-created", false, "")
+	flags.StringVarP(&s.sortByTagDate, "sort-by-tag-date", "", "", "")
+	flags.StringVarP(&s.sortByTagNumber, "sort-by-tag-number", "", "", "")
+	flags.BoolVar(&s.reverse, "reverse", false, "")
+}
+
+// Less returns order given in flags, descending unless --reverse was given
+func (s *Sorting) Less() notes.Less {
+	return s.less(s.reverse)
+}
+
+// ReversedLess returns order opposite to Less
+func (s *Sorting) ReversedLess() notes.Less {
+	return s.less(!s.reverse)
+}
+
+func (s *Sorting) less(ascending bool) notes.Less {
+	sort := notes.ModifiedDesc
+	if ascending {
+		sort = notes.ModifiedAsc
+	}
+	switch {
+	case s.sortByCreated && !ascending:
+		sort = notes.CreatedDesc
+	case s.sortByCreated && ascending:
+		sort = notes.CreatedAsc
+	case s.sortByTagDate != "" && !ascending:
+		sort = notes.TagDateDesc(s.sortByTagDate)
+	case s.sortByTagDate != "" && ascending:
+		sort = notes.TagDateAsc(s.sortByTagDate)
+	case s.sortByTagNumber != "" && !ascending:
+		sort = notes.TagNumberDesc(s.sortByTagNumber)
+	case s.sortByTagNumber != "" && ascending:
+		sort = notes.TagNumberAsc(s.sortByTagNumber)
+	}
+	return sort
+}
@@ -0,0 +1,184 @@
+package lsp
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/elgopher/noteo/note"
+	"github.com/elgopher/noteo/parser"
+)
+
+// document is a note text split into lines. Path is relative to the working directory.
+type document struct {
+	path  string
+	text  string
+	lines []string
+	// frontMatter is the front matter including delimiters, as parsed by noteo
+	frontMatter string
+}
+
+func newDocument(path, text string) document {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	frontMatter, _, _ := parser.Parse(strings.NewReader(text))
+	return document{path: path, text: text, lines: lines, frontMatter: frontMatter}
+}
+
+// note returns the note with the text of the document, which may be not saved yet
+func (d document) note() *note.Note {
+	return note.NewFromText(d.path, d.text)
+}
+
+func (d document) line(i int) string {
+	if i < 0 || i >= len(d.lines) {
+		return ""
+	}
+	return d.lines[i]
+}
+
+// frontMatterEnd returns index of the line closing front matter or -1 if there is no front matter
+func (d document) frontMatterEnd() int {
+	if d.frontMatter == "" {
+		return -1
+	}
+	return strings.Count(strings.TrimSuffix(d.frontMatter, "\n"), "\n")
+}
+
+// keyPatterns match lines with top level keys in YAML, TOML and JSON front matter. The first group is the key.
+var keyPatterns = map[parser.Format]*regexp.Regexp{
+	parser.YAML: regexp.MustCompile(`^["']?([^\s#"'-][^:"']*)["']?\s*:`),
+	parser.TOML: regexp.MustCompile(`^\s*["']?([\w-]+)["']?\s*=`),
+	parser.JSON: regexp.MustCompile(`^\s*"([^"]*)"\s*:`),
+}
+
+// isTagsValue returns true when the line is a part of Tags front matter value
+func (d document) isTagsValue(line int) bool {
+	end := d.frontMatterEnd()
+	if line <= 0 || line >= end {
+		return false
+	}
+	keyPattern := keyPatterns[parser.FormatOf(d.frontMatter)]
+	for i := line; i > 0; i-- {
+		if match := keyPattern.FindStringSubmatch(d.lines[i]); match != nil {
+			return strings.EqualFold(match[1], "tags")
+		}
+	}
+	return false
+}
+
+var linkRegexp = regexp.MustCompile(`\[[^][]+]\(([^()]+)\)`)
+
+// link is a Markdown link found in the document body
+type link struct {
+	line       int
+	start, end int // byte offsets of the whole link in the line
+	target     string
+	// byte offsets of the path in the line, which is the target without title and anchor
+	pathStart, pathEnd int
+}
+
+func (d document) links() []link {
+	var links []link
+	for i := d.frontMatterEnd() + 1; i < len(d.lines); i++ {
+		for _, match := range linkRegexp.FindAllStringSubmatchIndex(d.lines[i], -1) {
+			target := d.lines[i][match[2]:match[3]]
+			pathStart := match[2] + len(target) - len(strings.TrimLeft(target, " "))
+			pathEnd := pathStart
+			for pathEnd < match[3] && !strings.ContainsRune(" \t#", rune(d.lines[i][pathEnd])) {
+				pathEnd++
+			}
+			links = append(links, link{
+				line:      i,
+				start:     match[0],
+				end:       match[1],
+				target:    target,
+				pathStart: pathStart,
+				pathEnd:   pathEnd,
+			})
+		}
+	}
+	return links
+}
+
+// linkAt returns the link at given line and byte offset
+func (d document) linkAt(line, offset int) (link, bool) {
+	for _, l := range d.links() {
+		if l.line == line && offset >= l.start && offset <= l.end {
+			return l, true
+		}
+	}
+	return link{}, false
+}
+
+// notePath returns path of the note, relative to the working directory, which the link points to.
+// Returns false for external links and anchors.
+func (d document) notePath(l link) (string, bool) {
+	return note.LinkedFile(d.path, d.lines[l.line][l.pathStart:l.pathEnd])
+}
+
+func (d document) rangeOf(line, start, end int) rangeType {
+	l := d.line(line)
+	return rangeType{
+		Start: position{Line: line, Character: character(l, start)},
+		End:   position{Line: line, Character: character(l, end)},
+	}
+}
+
+// byteOffset converts LSP character offset, counted in UTF-16 code units, to byte offset in the line
+func byteOffset(line string, character int) int {
+	units := 0
+	for i, r := range line {
+		if units >= character {
+			return i
+		}
+		units += utf16Len(r)
+	}
+	return len(line)
+}
+
+// character converts byte offset in the line to LSP character offset, counted in UTF-16 code units
+func character(line string, offset int) int {
+	if offset > len(line) {
+		offset = len(line)
+	}
+	units := 0
+	for _, r := range line[:offset] {
+		units += utf16Len(r)
+	}
+	return units
+}
+
+func utf16Len(r rune) int {
+	if r >= 0x10000 {
+		return 2
+	}
+	return 1
+}
+
+func pathFromURI(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "file" {
+		return "", fmt.Errorf("unsupported URI %s", uri)
+	}
+	p := u.Path
+	if len(p) > 2 && p[0] == '/' && p[2] == ':' { // Windows drive letter, e.g. /C:/notes
+		p = p[1:]
+	}
+	return filepath.FromSlash(p), nil
+}
+
+func uriFromPath(absPath string) string {
+	p := filepath.ToSlash(absPath)
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	return (&url.URL{Scheme: "file", Path: p}).String()
+}
@@ -0,0 +1,493 @@
+// Package lsp implements Language Server Protocol server for Markdown notes in a noteo repository
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/elgopher/noteo/note"
+	"github.com/elgopher/noteo/repository"
+	"github.com/elgopher/noteo/seq"
+)
+
+// Server serves a single client. Paths of notes are relative to the working directory of the repository.
+type Server struct {
+	repo *repository.Repository
+	out  io.Writer
+	// documents opened by the client, by URI
+	documents map[string]string
+}
+
+func New(repo *repository.Repository) *Server {
+	return &Server{
+		repo:      repo,
+		documents: map[string]string{},
+	}
+}
+
+// Serve reads messages from in and writes messages to out until exit notification or the end of input
+func (s *Server) Serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	s.out = out
+	reader := bufio.NewReader(in)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		body, err := readMessage(reader)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var req request
+		if err = json.Unmarshal(body, &req); err != nil {
+			if err = writeMessage(out, errorResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{Code: parseError, Message: err.Error()}}); err != nil {
+				return err
+			}
+			continue
+		}
+		if req.Method == "exit" {
+			return nil
+		}
+		result, err := s.handle(ctx, req)
+		if req.isNotification() {
+			if err != nil {
+				s.logMessage(err)
+			}
+			continue
+		}
+		if err != nil {
+			var e *rpcError
+			if !errors.As(err, &e) {
+				e = &rpcError{Code: internalError, Message: err.Error()}
+			}
+			err = writeMessage(out, errorResponse{JSONRPC: "2.0", ID: req.ID, Error: e})
+		} else {
+			err = writeMessage(out, response{JSONRPC: "2.0", ID: req.ID, Result: result})
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) handle(ctx context.Context, req request) (interface{}, error) {
+	switch req.Method {
+	case "initialize":
+		return initializeResult{
+			Capabilities: serverCapabilities{
+				TextDocumentSync:   textDocumentSyncFull,
+				CompletionProvider: completionOptions{TriggerCharacters: []string{" ", "(", "/", ","}},
+				DefinitionProvider: true,
+				ReferencesProvider: true,
+				RenameProvider:     true,
+			},
+			ServerInfo: serverInfo{Name: "noteo"},
+		}, nil
+	case "initialized", "shutdown":
+		return nil, nil
+	case "textDocument/didOpen":
+		var params didOpenParams
+		if err := unmarshalParams(req, &params); err != nil {
+			return nil, err
+		}
+		s.documents[params.TextDocument.URI] = params.TextDocument.Text
+		return nil, s.publishDiagnostics(params.TextDocument.URI)
+	case "textDocument/didChange":
+		var params didChangeParams
+		if err := unmarshalParams(req, &params); err != nil {
+			return nil, err
+		}
+		if len(params.ContentChanges) > 0 {
+			s.documents[params.TextDocument.URI] = params.ContentChanges[len(params.ContentChanges)-1].Text
+		}
+		return nil, s.publishDiagnostics(params.TextDocument.URI)
+	case "textDocument/didSave":
+		var params didCloseParams
+		if err := unmarshalParams(req, &params); err != nil {
+			return nil, err
+		}
+		return nil, s.publishDiagnostics(params.TextDocument.URI)
+	case "textDocument/didClose":
+		var params didCloseParams
+		if err := unmarshalParams(req, &params); err != nil {
+			return nil, err
+		}
+		delete(s.documents, params.TextDocument.URI)
+		return nil, s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{URI: params.TextDocument.URI, Diagnostics: []diagnostic{}})
+	case "textDocument/completion":
+		var params textDocumentPositionParams
+		if err := unmarshalParams(req, &params); err != nil {
+			return nil, err
+		}
+		return s.completion(ctx, params)
+	case "textDocument/definition":
+		var params textDocumentPositionParams
+		if err := unmarshalParams(req, &params); err != nil {
+			return nil, err
+		}
+		return s.definition(params)
+	case "textDocument/references":
+		var params textDocumentPositionParams
+		if err := unmarshalParams(req, &params); err != nil {
+			return nil, err
+		}
+		return s.references(ctx, params)
+	case "textDocument/rename":
+		var params renameParams
+		if err := unmarshalParams(req, &params); err != nil {
+			return nil, err
+		}
+		return s.rename(ctx, params)
+	}
+	if req.isNotification() {
+		return nil, nil
+	}
+	return nil, &rpcError{Code: methodNotFound, Message: "method not found: " + req.Method}
+}
+
+func unmarshalParams(req request, params interface{}) error {
+	if err := json.Unmarshal(req.Params, params); err != nil {
+		return &rpcError{Code: invalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+func (s *Server) notify(method string, params interface{}) error {
+	return writeMessage(s.out, notification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+func (s *Server) logMessage(err error) {
+	const messageTypeError = 1
+	_ = s.notify("window/logMessage", map[string]interface{}{"type": messageTypeError, "message": err.Error()})
+}
+
+// document returns the document opened by the client or reads it from disk
+func (s *Server) document(uri string) (document, error) {
+	absPath, err := pathFromURI(uri)
+	if err != nil {
+		return document{}, err
+	}
+	path, err := filepath.Rel(s.repo.WorkDir(), absPath)
+	if err != nil {
+		return document{}, err
+	}
+	if text, ok := s.documents[uri]; ok {
+		return newDocument(path, text), nil
+	}
+	text, err := os.ReadFile(s.file(path))
+	if err != nil {
+		return document{}, err
+	}
+	return newDocument(path, string(text)), nil
+}
+
+// file returns path, which is relative to the working directory of the repository, as a path which can be accessed
+// regardless of the current working directory of the process
+func (s *Server) file(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(s.repo.WorkDir(), path)
+}
+
+func (s *Server) uri(path string) string {
+	return uriFromPath(s.file(path))
+}
+
+func (s *Server) publishDiagnostics(uri string) error {
+	d, err := s.document(uri)
+	if err != nil {
+		return err
+	}
+	diagnostics := s.tagDiagnostics(d)
+	diagnostics = append(diagnostics, s.linkDiagnostics(d)...)
+	return s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{URI: uri, Diagnostics: diagnostics})
+}
+
+// tagDiagnostics reports front matter problems found by noteo check and tags not matching the vocabulary
+func (s *Server) tagDiagnostics(d document) []diagnostic {
+	diagnostics := []diagnostic{}
+	n := d.note()
+	problems, err := n.Check()
+	if err != nil {
+		return diagnostics
+	}
+	for _, problem := range problems {
+		if problem.Warning {
+			continue
+		}
+		diagnostics = append(diagnostics, diagnostic{
+			Range:    problemRange(d, problem),
+			Severity: severityError,
+			Source:   "noteo",
+			Message:  problem.Message,
+		})
+	}
+	tags, err := n.Tags()
+	if err != nil {
+		return diagnostics
+	}
+	config, err := s.repo.Config()
+	if err != nil {
+		return diagnostics
+	}
+	vocabulary, err := config.Vocabulary()
+	if err != nil {
+		return diagnostics
+	}
+	for _, t := range tags {
+		if _, err = vocabulary.Normalize(t); err != nil {
+			diagnostics = append(diagnostics, diagnostic{
+				Range:    tagRange(d, d.frontMatterEnd(), t.String()),
+				Severity: severityError,
+				Source:   "noteo",
+				Message:  err.Error(),
+			})
+		}
+	}
+	return diagnostics
+}
+
+// problemRange returns range of the problem text or the whole line when there is no text
+func problemRange(d document, problem note.Problem) rangeType {
+	line := problem.Line - 1
+	if line < 0 {
+		line = 0
+	}
+	if offset := strings.Index(d.line(line), problem.Text); offset >= 0 && problem.Text != "" {
+		return d.rangeOf(line, offset, offset+len(problem.Text))
+	}
+	return d.rangeOf(line, 0, len(d.line(line)))
+}
+
+// tagRange returns range of the tag in the front matter or the range of the Tags line
+func tagRange(d document, frontMatterEnd int, t string) rangeType {
+	tagsLine := 1
+	for i := 1; i < frontMatterEnd; i++ {
+		if d.isTagsValue(i) {
+			if offset := strings.Index(d.lines[i], t); offset >= 0 && t != "" {
+				return d.rangeOf(i, offset, offset+len(t))
+			}
+			if tagsLine == 1 {
+				tagsLine = i
+			}
+		}
+	}
+	return d.rangeOf(tagsLine, 0, len(d.line(tagsLine)))
+}
+
+func (s *Server) linkDiagnostics(d document) []diagnostic {
+	var diagnostics []diagnostic
+	for _, l := range d.links() {
+		path, ok := d.notePath(l)
+		if !ok {
+			continue
+		}
+		if _, err := os.Stat(s.file(path)); os.IsNotExist(err) {
+			diagnostics = append(diagnostics, diagnostic{
+				Range:    d.rangeOf(l.line, l.start, l.end),
+				Severity: severityWarning,
+				Source:   "noteo",
+				Message:  "broken link: " + l.target,
+			})
+		}
+	}
+	return diagnostics
+}
+
+func (s *Server) completion(ctx context.Context, params textDocumentPositionParams) ([]completionItem, error) {
+	d, err := s.document(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	line := d.line(params.Position.Line)
+	offset := byteOffset(line, params.Position.Character)
+	if start, ok := linkTargetStart(line[:offset]); ok {
+		return s.noteCompletion(ctx, d, d.rangeOf(params.Position.Line, start, offset))
+	}
+	if d.isTagsValue(params.Position.Line) {
+		return s.tagCompletion(ctx)
+	}
+	return []completionItem{}, nil
+}
+
+// linkTargetStart returns byte offset of the link target when the text ends inside unfinished link target, e.g. "[a](dir/"
+func linkTargetStart(text string) (int, bool) {
+	i := strings.LastIndex(text, "](")
+	if i < 0 || strings.ContainsAny(text[i+2:], ") ") {
+		return 0, false
+	}
+	return i + 2, true
+}
+
+func (s *Server) noteCompletion(ctx context.Context, d document, replaced rangeType) ([]completionItem, error) {
+	items := []completionItem{}
+	allNotes, _ := seq.Collect(s.repo.AllNotesSeq(ctx))
+	for _, n := range allNotes {
+		if n.Path() == d.path {
+			continue
+		}
+		rel, err := filepath.Rel(filepath.Dir(d.path), n.Path())
+		if err != nil {
+			continue
+		}
+		label := filepath.ToSlash(rel)
+		items = append(items, completionItem{
+			Label:    label,
+			Kind:     completionKindFile,
+			TextEdit: &textEdit{Range: replaced, NewText: label},
+		})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Label < items[j].Label
+	})
+	return items, nil
+}
+
+func (s *Server) tagCompletion(ctx context.Context) ([]completionItem, error) {
+	unique := map[string]struct{}{}
+	if config, err := s.repo.Config(); err == nil {
+		if vocabulary, err := config.Vocabulary(); err == nil {
+			for name := range vocabulary.Names {
+				unique[name] = struct{}{}
+			}
+		}
+	}
+	tags, _ := seq.Collect(s.repo.TagsSeq(ctx))
+	for _, t := range tags {
+		unique[t.String()] = struct{}{}
+	}
+	items := []completionItem{}
+	for label := range unique {
+		items = append(items, completionItem{Label: label, Kind: completionKindValue})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Label < items[j].Label
+	})
+	return items, nil
+}
+
+func (s *Server) definition(params textDocumentPositionParams) (*location, error) {
+	d, err := s.document(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	line := d.line(params.Position.Line)
+	l, ok := d.linkAt(params.Position.Line, byteOffset(line, params.Position.Character))
+	if !ok {
+		return nil, nil
+	}
+	path, ok := d.notePath(l)
+	if !ok {
+		return nil, nil
+	}
+	if _, err = os.Stat(s.file(path)); err != nil {
+		return nil, nil
+	}
+	return &location{URI: s.uri(path)}, nil
+}
+
+// references returns links to the document found in all notes in the repository
+func (s *Server) references(ctx context.Context, params textDocumentPositionParams) ([]location, error) {
+	d, err := s.document(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	locations := []location{}
+	allNotes, _ := seq.Collect(s.repo.AllNotesSeq(ctx))
+	for _, n := range allNotes {
+		other, err := s.document(s.uri(n.Path()))
+		if err != nil {
+			continue
+		}
+		for _, l := range other.links() {
+			path, ok := other.notePath(l)
+			if ok && filepath.Clean(path) == filepath.Clean(d.path) {
+				locations = append(locations, location{
+					URI:   s.uri(other.path),
+					Range: other.rangeOf(l.line, l.start, l.end),
+				})
+			}
+		}
+	}
+	return locations, nil
+}
+
+// rename returns workspace edit moving the note to the new name, given relative to the note directory, and updating
+// links to the note in all notes, the same way as noteo mv does. Files are changed by the client.
+func (s *Server) rename(ctx context.Context, params renameParams) (*workspaceEdit, error) {
+	d, err := s.document(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	target := filepath.FromSlash(params.NewName)
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(d.path), target)
+	}
+	if filepath.Ext(target) == "" {
+		target += ".md"
+	}
+	if err = s.repo.CheckExtension(target); err != nil {
+		return nil, fmt.Errorf("rename failed: %v", err)
+	}
+	if _, err = os.Stat(s.file(target)); err == nil {
+		return nil, fmt.Errorf("rename failed: %s already exists", target)
+	}
+	var changes []interface{}
+	addEdits := func(uri string, edits []textEdit) {
+		if len(edits) > 0 {
+			changes = append(changes, textDocumentEdit{
+				TextDocument: optionalVersionedTextDocumentIdentifier{URI: uri},
+				Edits:        edits,
+			})
+		}
+	}
+	source := filepath.Clean(d.path)
+	// relative links of the renamed note must point to the same notes from the new directory
+	addEdits(params.TextDocument.URI, linkEdits(d, func(linkPath string) (string, bool) {
+		return note.RelocatedLink(source, target, linkPath)
+	}))
+	allNotes, _ := seq.Collect(s.repo.AllNotesSeq(ctx))
+	for _, n := range allNotes {
+		if filepath.Clean(n.Path()) == source {
+			continue
+		}
+		other, err := s.document(s.uri(n.Path()))
+		if err != nil {
+			continue
+		}
+		addEdits(s.uri(other.path), linkEdits(other, func(linkPath string) (string, bool) {
+			return note.UpdatedLink(other.path, linkPath, source, target)
+		}))
+	}
+	changes = append(changes, renameFile{Kind: "rename", OldURI: params.TextDocument.URI, NewURI: s.uri(target)})
+	return &workspaceEdit{DocumentChanges: changes}, nil
+}
+
+// linkEdits returns edits of links in d, which newPath returns a new link path for. Link paths are rewritten by
+// the note package, the same way as noteo mv does.
+func linkEdits(d document, newPath func(linkPath string) (string, bool)) []textEdit {
+	var edits []textEdit
+	for _, l := range d.links() {
+		linkPath := d.lines[l.line][l.pathStart:l.pathEnd]
+		newLinkPath, ok := newPath(linkPath)
+		if !ok || newLinkPath == linkPath {
+			continue
+		}
+		edits = append(edits, textEdit{
+			Range:   d.rangeOf(l.line, l.pathStart, l.pathEnd),
+			NewText: newLinkPath,
+		})
+	}
+	return edits
+}
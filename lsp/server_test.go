@@ -0,0 +1,351 @@
+package lsp_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/noteo/lsp"
+	"github.com/elgopher/noteo/repository"
+)
+
+func TestServer_Initialize(t *testing.T) {
+	dir := newRepo(t)
+	// when
+	messages := serve(t, dir, request(1, "initialize", map[string]interface{}{}))
+	// then
+	require.Len(t, messages, 1)
+	var result struct {
+		Capabilities struct {
+			DefinitionProvider bool
+			ReferencesProvider bool
+			RenameProvider     bool
+		}
+	}
+	require.NoError(t, json.Unmarshal(messages[0]["result"], &result))
+	assert.True(t, result.Capabilities.DefinitionProvider)
+	assert.True(t, result.Capabilities.ReferencesProvider)
+	assert.True(t, result.Capabilities.RenameProvider)
+}
+
+func TestServer_Diagnostics(t *testing.T) {
+	dir := newRepo(t)
+	writeFile(t, dir, "existing.md", "existing")
+	text := "---\nTags: [\"in valid\", ok]\n---\n[ok](existing.md) [broken](missing.md) [web](https://example.com)"
+	// when
+	messages := serve(t, dir, didOpen(dir, "note.md", text))
+	// then
+	require.Len(t, messages, 1)
+	var params struct {
+		Diagnostics []struct {
+			Range struct {
+				Start struct{ Line, Character int }
+			}
+			Message string
+		}
+	}
+	require.NoError(t, json.Unmarshal(messages[0]["params"], &params))
+	require.Len(t, params.Diagnostics, 2)
+	assert.Equal(t, "in valid is not a valid tag", params.Diagnostics[0].Message)
+	assert.Equal(t, 1, params.Diagnostics[0].Range.Start.Line)
+	assert.Equal(t, 8, params.Diagnostics[0].Range.Start.Character)
+	assert.Equal(t, "broken link: missing.md", params.Diagnostics[1].Message)
+	assert.Equal(t, 3, params.Diagnostics[1].Range.Start.Line)
+	assert.Equal(t, 18, params.Diagnostics[1].Range.Start.Character)
+}
+
+func TestServer_DiagnosticsTOML(t *testing.T) {
+	dir := newRepo(t)
+	text := "+++\nTags = [\"ok\", \"in valid\"]\n+++\ntext"
+	// when
+	messages := serve(t, dir, didOpen(dir, "note.md", text))
+	// then
+	require.Len(t, messages, 1)
+	var params struct {
+		Diagnostics []struct {
+			Range struct {
+				Start struct{ Line, Character int }
+			}
+			Message string
+		}
+	}
+	require.NoError(t, json.Unmarshal(messages[0]["params"], &params))
+	require.Len(t, params.Diagnostics, 1)
+	assert.Equal(t, "in valid is not a valid tag", params.Diagnostics[0].Message)
+	assert.Equal(t, 1, params.Diagnostics[0].Range.Start.Line)
+	assert.Equal(t, 15, params.Diagnostics[0].Range.Start.Character)
+}
+
+func TestServer_Completion(t *testing.T) {
+	t.Run("should complete tags", func(t *testing.T) {
+		dir := newRepo(t)
+		writeFile(t, dir, "other.md", "---\nTags: idea priority:1\n---\n")
+		text := "---\nTags: \n---\n"
+		// when
+		messages := serve(t, dir,
+			didOpen(dir, "note.md", text),
+			request(1, "textDocument/completion", positionParams(dir, "note.md", 1, 6)),
+		)
+		// then
+		assert.Equal(t, []string{"idea", "priority:1"}, completionLabels(t, messages[1]))
+	})
+
+	t.Run("should complete tags in TOML front matter", func(t *testing.T) {
+		dir := newRepo(t)
+		writeFile(t, dir, "other.md", "---\nTags: idea\n---\n")
+		text := "+++\nTitle = \"a\"\nTags = [\n  \"\n]\n+++\n"
+		// when
+		messages := serve(t, dir,
+			didOpen(dir, "note.md", text),
+			request(1, "textDocument/completion", positionParams(dir, "note.md", 3, 3)),
+		)
+		// then
+		assert.Equal(t, []string{"idea"}, completionLabels(t, messages[1]))
+	})
+
+	t.Run("should complete note paths in links", func(t *testing.T) {
+		dir := newRepo(t)
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "dir"), os.ModePerm))
+		writeFile(t, dir, filepath.Join("dir", "a.md"), "a")
+		writeFile(t, dir, "b.md", "b")
+		text := "see [a](d"
+		// when
+		messages := serve(t, dir,
+			didOpen(dir, "note.md", text),
+			request(1, "textDocument/completion", positionParams(dir, "note.md", 0, 9)),
+		)
+		// then
+		assert.Equal(t, []string{"b.md", "dir/a.md"}, completionLabels(t, messages[1]))
+	})
+
+	t.Run("should not complete outside of tags and links", func(t *testing.T) {
+		dir := newRepo(t)
+		writeFile(t, dir, "other.md", "---\nTags: idea\n---\n")
+		text := "---\nTags: \nCreated: \n---\ntext"
+		// when
+		messages := serve(t, dir,
+			didOpen(dir, "note.md", text),
+			request(1, "textDocument/completion", positionParams(dir, "note.md", 2, 9)),
+			request(2, "textDocument/completion", positionParams(dir, "note.md", 4, 2)),
+		)
+		// then
+		assert.Empty(t, completionLabels(t, messages[1]))
+		assert.Empty(t, completionLabels(t, messages[2]))
+	})
+}
+
+func TestServer_Definition(t *testing.T) {
+	dir := newRepo(t)
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "dir"), os.ModePerm))
+	writeFile(t, dir, filepath.Join("dir", "a.md"), "a")
+	text := "see [a](dir/a.md)"
+	// when
+	messages := serve(t, dir,
+		didOpen(dir, "note.md", text),
+		request(1, "textDocument/definition", positionParams(dir, "note.md", 0, 6)),
+		request(2, "textDocument/definition", positionParams(dir, "note.md", 0, 1)),
+	)
+	// then
+	var loc struct{ URI string }
+	require.NoError(t, json.Unmarshal(messages[1]["result"], &loc))
+	assert.Equal(t, uri(dir, filepath.Join("dir", "a.md")), loc.URI)
+	assert.Equal(t, "null", string(messages[2]["result"]))
+}
+
+func TestServer_References(t *testing.T) {
+	dir := newRepo(t)
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "dir"), os.ModePerm))
+	writeFile(t, dir, "target.md", "target")
+	writeFile(t, dir, filepath.Join("dir", "a.md"), "first\n[target](../target.md)")
+	writeFile(t, dir, "b.md", "[other](other.md)")
+	// when
+	messages := serve(t, dir,
+		request(1, "textDocument/references", positionParams(dir, "target.md", 0, 0)),
+	)
+	// then
+	var locations []struct {
+		URI   string
+		Range struct {
+			Start struct{ Line, Character int }
+		}
+	}
+	require.NoError(t, json.Unmarshal(messages[0]["result"], &locations))
+	require.Len(t, locations, 1)
+	assert.Equal(t, uri(dir, filepath.Join("dir", "a.md")), locations[0].URI)
+	assert.Equal(t, 1, locations[0].Range.Start.Line)
+}
+
+func TestServer_Rename(t *testing.T) {
+	t.Run("should return edit renaming file and updating links", func(t *testing.T) {
+		dir := newRepo(t)
+		writeFile(t, dir, "source.md", "source")
+		writeFile(t, dir, "link.md", "[link](source.md#section)")
+		// when
+		messages := serve(t, dir, request(1, "textDocument/rename", renameParams(dir, "source.md", "target")))
+		// then
+		require.Len(t, messages, 1)
+		assert.Nil(t, messages[0]["error"])
+		assert.JSONEq(t, `{"documentChanges": [
+			{"textDocument": {"uri": "`+uri(dir, "link.md")+`", "version": null}, "edits": [
+				{"range": {"start": {"line": 0, "character": 7}, "end": {"line": 0, "character": 16}}, "newText": "target.md"}
+			]},
+			{"kind": "rename", "oldUri": "`+uri(dir, "source.md")+`", "newUri": "`+uri(dir, "target.md")+`"}
+		]}`, string(messages[0]["result"]))
+		assert.FileExists(t, filepath.Join(dir, "source.md"))
+		assertFileEquals(t, dir, "link.md", "[link](source.md#section)")
+	})
+
+	t.Run("should update links in note moved to other directory", func(t *testing.T) {
+		dir := newRepo(t)
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "dir"), os.ModePerm))
+		writeFile(t, dir, "source.md", "[other](other.md)")
+		writeFile(t, dir, "other.md", "other")
+		// when
+		messages := serve(t, dir, request(1, "textDocument/rename", renameParams(dir, "source.md", "dir/target")))
+		// then
+		require.Len(t, messages, 1)
+		var result struct {
+			DocumentChanges []struct {
+				Edits []struct{ NewText string }
+			}
+		}
+		require.NoError(t, json.Unmarshal(messages[0]["result"], &result))
+		require.Len(t, result.DocumentChanges, 2)
+		require.Len(t, result.DocumentChanges[0].Edits, 1)
+		assert.Equal(t, "../other.md", result.DocumentChanges[0].Edits[0].NewText)
+	})
+
+	t.Run("should return error when target exists", func(t *testing.T) {
+		dir := newRepo(t)
+		writeFile(t, dir, "source.md", "source")
+		writeFile(t, dir, "target.md", "target")
+		// when
+		messages := serve(t, dir, request(1, "textDocument/rename", renameParams(dir, "source.md", "target")))
+		// then
+		require.Len(t, messages, 1)
+		assert.NotNil(t, messages[0]["error"])
+	})
+}
+
+func renameParams(dir, file, newName string) map[string]interface{} {
+	return map[string]interface{}{
+		"textDocument": map[string]string{"uri": uri(dir, file)},
+		"position":     map[string]int{"line": 0, "character": 0},
+		"newName":      newName,
+	}
+}
+
+func TestServer_MethodNotFound(t *testing.T) {
+	dir := newRepo(t)
+	// when
+	messages := serve(t, dir,
+		request(1, "unknown", nil),
+		notification("$/unknown", nil),
+	)
+	// then
+	require.Len(t, messages, 1)
+	assert.Contains(t, string(messages[0]["error"]), "method not found")
+}
+
+// newRepo creates a repository in a temporary directory. Working directory of the process is not changed, so
+// paths must be resolved against the repository.
+func newRepo(t *testing.T) string {
+	dir := t.TempDir()
+	_, err := repository.Init(dir)
+	require.NoError(t, err)
+	return dir
+}
+
+// serve sends messages to the server and returns all messages sent back
+func serve(t *testing.T, dir string, messages ...string) []map[string]json.RawMessage {
+	repo, err := repository.ForWorkDir(dir)
+	require.NoError(t, err)
+	in := strings.Join(messages, "")
+	out := &bytes.Buffer{}
+	require.NoError(t, lsp.New(repo).Serve(context.Background(), strings.NewReader(in), out))
+	return readMessages(t, out)
+}
+
+func readMessages(t *testing.T, out io.Reader) []map[string]json.RawMessage {
+	var messages []map[string]json.RawMessage
+	reader := bufio.NewReader(out)
+	for {
+		header, err := reader.ReadString('\n')
+		if err == io.EOF {
+			return messages
+		}
+		require.NoError(t, err)
+		length, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(header, "Content-Length:")))
+		require.NoError(t, err)
+		_, err = reader.ReadString('\n')
+		require.NoError(t, err)
+		body := make([]byte, length)
+		_, err = io.ReadFull(reader, body)
+		require.NoError(t, err)
+		var message map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(body, &message))
+		messages = append(messages, message)
+	}
+}
+
+func request(id int, method string, params interface{}) string {
+	return message(map[string]interface{}{"jsonrpc": "2.0", "id": id, "method": method, "params": params})
+}
+
+func notification(method string, params interface{}) string {
+	return message(map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": params})
+}
+
+func message(m map[string]interface{}) string {
+	body, _ := json.Marshal(m)
+	return fmt.Sprintf("Content-Length: %d\r\n\r\n%s", len(body), body)
+}
+
+func didOpen(dir, file, text string) string {
+	return notification("textDocument/didOpen", map[string]interface{}{
+		"textDocument": map[string]string{"uri": uri(dir, file), "text": text},
+	})
+}
+
+func positionParams(dir, file string, line, character int) map[string]interface{} {
+	return map[string]interface{}{
+		"textDocument": map[string]string{"uri": uri(dir, file)},
+		"position":     map[string]int{"line": line, "character": character},
+	}
+}
+
+func uri(dir, file string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(filepath.Join(dir, file))}).String()
+}
+
+func completionLabels(t *testing.T, message map[string]json.RawMessage) []string {
+	var items []struct{ Label string }
+	require.NoError(t, json.Unmarshal(message["result"], &items))
+	var labels []string
+	for _, item := range items {
+		labels = append(labels, item.Label)
+	}
+	return labels
+}
+
+func writeFile(t *testing.T, dir, filename, content string) {
+	require.NoError(t, os.WriteFile(filepath.Join(dir, filename), []byte(content), os.ModePerm))
+}
+
+func assertFileEquals(t *testing.T, dir, filename, expected string) {
+	bytes, err := os.ReadFile(filepath.Join(dir, filename))
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(bytes))
+}
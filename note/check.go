@@ -0,0 +1,221 @@
+package note
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/elgopher/noteo/tag"
+)
+
+// Problem is a front matter problem found by Check
+type Problem struct {
+	// Line in the file counting from 1, or 0 when problem is not related to a specific line
+	Line int
+	// Text is a part of the line the problem is about, e.g. a tag, or empty when the problem is about the whole line
+	Text       string
+	Message    string
+	Suggestion string
+	// Warning is true for problems which do not prevent noteo from reading the note
+	Warning bool
+	fix     func(n *Note) error
+}
+
+// Fixable returns true if problem can be fixed automatically using Fix
+func (p Problem) Fixable() bool {
+	return p.fix != nil
+}
+
+// Fix fixes the problem in note n, which must be the note the problem was found in. Note must be saved afterwards.
+func (p Problem) Fix(n *Note) error {
+	if p.fix == nil {
+		return fmt.Errorf("%s: problem can't be fixed automatically", p.Message)
+	}
+	return p.fix(n)
+}
+
+// Check reports all front matter problems. Contrary to Tags and Created, it does not stop on the first problem.
+// Returned error means the note could not be read at all.
+func (n *Note) Check() ([]Problem, error) {
+	frontMatter, err := n.originalContent.FrontMatter()
+	if err != nil {
+		return nil, err
+	}
+	c := codecOf(frontMatter)
+	items, err := c.unmarshal(frontMatter)
+	if err != nil {
+		return []Problem{syntaxProblem(c, err)}, nil
+	}
+	lines := strings.Split(frontMatter, "\n")
+	problems := createdProblems(items, lines, n.modified)
+	problems = append(problems, tagProblems(items, lines)...)
+	return problems, nil
+}
+
+func syntaxProblem(c codec, err error) Problem {
+	line := 0
+	var syntaxErr *syntaxError
+	if errors.As(err, &syntaxErr) {
+		line = syntaxErr.line
+	}
+	message := strings.TrimPrefix(err.Error(), strings.ToLower(c.name())+": ")
+	suggestion := "fix " + c.name() + " syntax"
+	if c.name() == "YAML" {
+		suggestion += ", e.g. quote values containing colons or brackets"
+	}
+	return Problem{
+		Line:       line,
+		Message:    fmt.Sprintf("invalid %s: %v", c.name(), message),
+		Suggestion: suggestion,
+	}
+}
+
+// lenientLayouts are layouts not supported by date.ParseAbsolute, which can be safely converted to RFC 3339
+var lenientLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006/01/02 15:04:05",
+	"2006/01/02",
+	time.RFC1123,
+	time.RFC822Z,
+	time.RFC822,
+	time.RFC850,
+	time.ANSIC,
+}
+
+func createdProblems(items mapSlice, lines []string, modified func() (time.Time, error)) []Problem {
+	created, ok := items.at("Created")
+	if !ok {
+		return []Problem{{
+			Line:       0,
+			Message:    "missing Created",
+			Suggestion: "add Created with file modification time",
+			Warning:    true,
+			fix: func(n *Note) error {
+				modifiedTime, err := modified()
+				if err != nil {
+					return err
+				}
+				return n.SetCreated(modifiedTime)
+			},
+		}}
+	}
+	if _, err := parseCreated(created); err == nil {
+		return nil
+	}
+	value := fmt.Sprintf("%v", created)
+	problem := Problem{
+		Line:       keyLine(lines, "Created"),
+		Message:    fmt.Sprintf("Created %q has unsupported layout", value),
+		Suggestion: "use RFC 3339, e.g. " + time.Date(2020, 9, 5, 12, 30, 5, 0, time.UTC).Format(time.RFC3339),
+	}
+	for _, layout := range lenientLayouts {
+		t, err := time.ParseInLocation(layout, value, time.Local)
+		if err == nil {
+			problem.Suggestion = "use " + t.Format(time.RFC3339)
+			problem.fix = func(n *Note) error {
+				return n.SetCreated(t)
+			}
+			break
+		}
+	}
+	return []Problem{problem}
+}
+
+func tagProblems(items mapSlice, lines []string) []Problem {
+	tags, ok := items.at("Tags")
+	if !ok {
+		return nil
+	}
+	var problems []Problem
+	tagsLine := keyLine(lines, "Tags")
+	names := map[string]tag.Tag{}
+	for _, s := range stringTags(tags) {
+		s = strings.Trim(s, " ")
+		line := valueLine(lines, tagsLine, s)
+		t, err := tag.New(s)
+		if err != nil {
+			problems = append(problems, Problem{
+				Line:       line,
+				Text:       s,
+				Message:    err.Error(),
+				Suggestion: "replace spaces with dashes",
+			})
+			continue
+		}
+		if strings.HasSuffix(s, ":") {
+			withEmptyValue := t
+			withoutValue, _ := tag.New(strings.TrimSuffix(s, ":"))
+			problems = append(problems, Problem{
+				Line:       line,
+				Text:       s,
+				Message:    fmt.Sprintf("tag %s has empty value", s),
+				Suggestion: "use " + withoutValue.String(),
+				fix: func(n *Note) error {
+					return n.MapTags(func(t2 tag.Tag) (tag.Tag, error) {
+						if t2 == withEmptyValue {
+							return withoutValue, nil
+						}
+						return t2, nil
+					})
+				},
+			})
+			t = withoutValue
+		}
+		previous, duplicate := names[t.Name()]
+		if !duplicate {
+			names[t.Name()] = t
+			continue
+		}
+		problem := Problem{
+			Line:       line,
+			Text:       s,
+			Message:    fmt.Sprintf("duplicate tag name %s", t.Name()),
+			Suggestion: fmt.Sprintf("keep only one of %s and %s", previous, t),
+		}
+		if previous == t {
+			problem.Suggestion = "remove duplicate " + t.String()
+			problem.fix = func(n *Note) error {
+				return n.RemoveTag(t)
+			}
+		}
+		problems = append(problems, problem)
+	}
+	return problems
+}
+
+// keyLine returns line of the key, counting from 1. Returns 0 if not found. Key can be quoted and followed by : or =,
+// so YAML, TOML and JSON front matter is supported.
+func keyLine(lines []string, key string) int {
+	for i, line := range lines {
+		line = strings.TrimLeft(line, " \t\"'")
+		if len(line) < len(key) || !strings.EqualFold(line[:len(key)], key) {
+			continue
+		}
+		rest := strings.TrimLeft(line[len(key):], " \t\"'")
+		if strings.HasPrefix(rest, ":") || strings.HasPrefix(rest, "=") {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// valueLine returns line containing value, starting at line from. Returns from if not found.
+func valueLine(lines []string, from int, value string) int {
+	if from == 0 {
+		return 0
+	}
+	for i := from - 1; i < len(lines); i++ {
+		line := lines[i]
+		if i == from-1 {
+			line = line[strings.IndexAny(line, ":=")+1:] // skip the key
+		}
+		if strings.Contains(line, value) {
+			return i + 1
+		}
+	}
+	return from
+}
@@ -0,0 +1,126 @@
+package note
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/elgopher/noteo/parser"
+)
+
+// codec reads and writes front matter in one format. Front matter includes delimiters.
+type codec interface {
+	// name returns format name used in messages, e.g. YAML
+	name() string
+	// unmarshal returns top level keys in order. Returned syntax errors are *syntaxError.
+	unmarshal(frontMatter string) (mapSlice, error)
+	// edit replaces value of the top level key, leaving the rest of front matter intact. Missing key is added.
+	edit(frontMatter string, kv keyValue) (string, error)
+	// marshal returns whole front matter
+	marshal(items mapSlice) (string, error)
+}
+
+// keyValue is a new value of the top level key. Value is a string or []string.
+type keyValue struct {
+	key   string
+	value interface{}
+}
+
+// syntaxError is an unmarshal error with a line in the file, counting from 1, or 0 when line is not known
+type syntaxError struct {
+	line int
+	err  error
+}
+
+func (e *syntaxError) Error() string {
+	return e.err.Error()
+}
+
+func (e *syntaxError) Unwrap() error {
+	return e.err
+}
+
+// codecOf returns codec for front matter returned by parser.Parse
+func codecOf(frontMatter string) codec {
+	switch parser.FormatOf(frontMatter) {
+	case parser.TOML:
+		return tomlCodec{}
+	case parser.JSON:
+		return jsonCodec{semicolons: strings.HasPrefix(frontMatter, ";;;")}
+	default:
+		return yamlCodec{}
+	}
+}
+
+func editAll(c codec, frontMatter string, values ...keyValue) (string, error) {
+	var err error
+	for _, kv := range values {
+		if frontMatter, err = c.edit(frontMatter, kv); err != nil {
+			return "", err
+		}
+	}
+	return frontMatter, nil
+}
+
+type yamlCodec struct{}
+
+func (yamlCodec) name() string {
+	return "YAML"
+}
+
+var yamlErrorLine = regexp.MustCompile(`line (\d+)`)
+
+func (yamlCodec) unmarshal(frontMatter string) (mapSlice, error) {
+	var items yaml.MapSlice
+	if err := yaml.Unmarshal([]byte(frontMatter), &items); err != nil {
+		// --- is a document start for YAML parser, so lines are the same as in the file
+		line := 0
+		if match := yamlErrorLine.FindStringSubmatch(err.Error()); match != nil {
+			line, _ = strconv.Atoi(match[1])
+		}
+		return nil, &syntaxError{line: line, err: err}
+	}
+	return mapSlice(items), nil
+}
+
+func (yamlCodec) edit(frontMatter string, kv keyValue) (string, error) {
+	return editYAML(frontMatter, kv)
+}
+
+func (yamlCodec) marshal(items mapSlice) (string, error) {
+	if items.isEmpty() {
+		return "", nil
+	}
+	marshaledBytes, err := yaml.Marshal(yaml.MapSlice(items))
+	if err != nil {
+		return "", err
+	}
+	return "---\n" + string(marshaledBytes) + "---\n", nil
+}
+
+// contentOf returns front matter without delimiter lines and the number of the first content line in the file
+func contentOf(frontMatter string) (string, int) {
+	lines := splitLines(frontMatter)
+	if len(lines) < 2 {
+		return "", 1
+	}
+	return strings.Join(lines[1:len(lines)-1], ""), 2
+}
+
+// newLineOf returns line ending used in front matter
+func newLineOf(frontMatter string) string {
+	if i := strings.Index(frontMatter, "\n"); i > 0 && frontMatter[i-1] == '\r' {
+		return "\r\n"
+	}
+	return "\n"
+}
+
+var errUnsupportedValue = errors.New("unsupported value")
+
+func unsupportedValue(v interface{}) error {
+	return fmt.Errorf("%w %v of type %T", errUnsupportedValue, v, v)
+}
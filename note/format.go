@@ -0,0 +1,130 @@
+package note
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/elgopher/noteo/date"
+)
+
+// Format is a canonical form of front matter
+type Format struct {
+	// DateLayout is a Go layout of Created. Empty layout leaves Created as it is.
+	DateLayout string
+	// TagsAsList marshals tags as YAML list instead of space separated string
+	TagsAsList bool
+	// KeyOrder lists keys which go first, in given order. Other keys keep their order. Keys are case-insensitive.
+	KeyOrder []string
+	// KeyCase changes case of all keys
+	KeyCase KeyCase
+}
+
+type KeyCase string
+
+const (
+	PreserveCase KeyCase = ""
+	LowerCase    KeyCase = "lower"
+	TitleCase    KeyCase = "title"
+)
+
+// DateLayouts maps names of layouts which can be read back by noteo to Go layouts
+var DateLayouts = map[string]string{
+	"rfc3339":  time.RFC3339,
+	"rfc2822":  time.RFC1123Z,
+	"iso8601":  date.ISO8601Layout,
+	"unixdate": time.UnixDate,
+}
+
+// Format rewrites front matter to canonical form f. Body is not changed. Note must be saved afterwards.
+func (n *Note) Format(f Format) error {
+	return n.frontMatter.format(f)
+}
+
+func (h *frontMatter) format(f Format) error {
+	if err := h.ensureParsed(); err != nil {
+		return err
+	}
+	if f.DateLayout != "" {
+		if _, ok := h.mapSlice.at("Created"); ok {
+			if h.createdErr != nil {
+				return h.createdErr
+			}
+			formatted := h.created.Format(f.DateLayout)
+			if created, _ := h.mapSlice.at("Created"); created != formatted {
+				h.mapSlice = h.mapSlice.set("Created", formatted)
+				h.createdChanged = true
+			}
+		}
+	}
+	h.tagsAsList = f.TagsAsList
+	h.rewriteTags = true
+	// keys are renamed or reordered by marshaling whole front matter, which drops comments
+	keys := fmt.Sprint(h.mapSlice.keys())
+	h.mapSlice = h.mapSlice.ordered(f.KeyOrder)
+	for i, item := range h.mapSlice {
+		h.mapSlice[i].Key = f.KeyCase.apply(fmt.Sprintf("%v", item.Key))
+	}
+	if fmt.Sprint(h.mapSlice.keys()) != keys {
+		h.rewrite = true
+	}
+	return nil
+}
+
+func (s mapSlice) keys() []string {
+	keys := make([]string, len(s))
+	for i, item := range s {
+		keys[i] = fmt.Sprintf("%v", item.Key)
+	}
+	return keys
+}
+
+// ordered returns items with given keys first
+func (s mapSlice) ordered(keys []string) mapSlice {
+	ordered := make(mapSlice, 0, len(s))
+	taken := make([]bool, len(s))
+	for _, key := range keys {
+		for i, item := range s {
+			if !taken[i] && strings.EqualFold(fmt.Sprintf("%v", item.Key), key) {
+				ordered = append(ordered, item)
+				taken[i] = true
+			}
+		}
+	}
+	for i, item := range s {
+		if !taken[i] {
+			ordered = append(ordered, item)
+		}
+	}
+	return ordered
+}
+
+func (c KeyCase) apply(key string) string {
+	switch c {
+	case LowerCase:
+		return strings.ToLower(key)
+	case TitleCase:
+		runes := []rune(strings.ToLower(key))
+		if len(runes) > 0 {
+			runes[0] = unicode.ToUpper(runes[0])
+		}
+		return string(runes)
+	default:
+		return key
+	}
+}
+
+// ParseDateLayout returns Go layout for a name from DateLayouts. Custom layouts are not supported, because Created
+// must be readable by noteo.
+func ParseDateLayout(name string) (string, error) {
+	if name == "" {
+		return time.RFC3339, nil
+	}
+	layout, ok := DateLayouts[strings.ToLower(name)]
+	if !ok {
+		return "", fmt.Errorf("unsupported date layout: %s. Supported layouts are rfc3339, rfc2822, iso8601 and unixdate",
+			name)
+	}
+	return layout, nil
+}
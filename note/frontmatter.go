@@ -1,6 +1,7 @@
 package note
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
@@ -13,6 +14,10 @@ import (
 	"github.com/elgopher/noteo/tag"
 )
 
+// ErrInvalidFrontMatter is wrapped by errors returned for notes with front matter which is not a valid YAML, TOML or
+// JSON
+var ErrInvalidFrontMatter = errors.New("front matter unmarshal failed")
+
 type frontMatter struct {
 	path     string
 	original func() (string, error)
@@ -20,6 +25,25 @@ type frontMatter struct {
 	mapSlice mapSlice
 	created  time.Time
 	tags     []tag.Tag
+	// err is returned by all methods when front matter could not be parsed
+	err error
+	// createdErr is returned by Created when Created could not be parsed
+	createdErr error
+	// tagsAsList is true when tags are marshaled as YAML list instead of space separated string
+	tagsAsList bool
+	// raw is the original front matter including delimiters
+	raw string
+	// codec reads and writes front matter in the format of raw
+	codec codec
+	// originalTags and originalTagsAsList are compared with current ones to find out whether Tags must be written
+	originalTags       []tag.Tag
+	originalTagsAsList bool
+	// rewriteTags forces writing Tags even when they were not changed, e.g. to normalize separators
+	rewriteTags bool
+	// createdChanged is true when Created must be written
+	createdChanged bool
+	// rewrite marshals whole front matter instead of editing changed values only
+	rewrite bool
 }
 
 type mapSlice yaml.MapSlice
@@ -59,38 +83,52 @@ func (s mapSlice) isEmpty() bool {
 	return len(s) == 0
 }
 
+// ensureParsed parses front matter once. Invalid YAML or tags make the whole front matter unusable, whereas invalid
+// Created is returned only by Created, so tags can still be read and the note can be fixed.
 func (h *frontMatter) ensureParsed() error {
-	var err error
 	h.once.Do(func() {
 		frontMatter, e := h.original()
 		if e != nil {
-			err = e
+			h.err = e
 			return
 		}
-		if e := yaml.Unmarshal([]byte(frontMatter), &h.mapSlice); e != nil {
-			err = fmt.Errorf("%s YAML front matter unmarshal failed: %v", h.path, e)
+		h.raw = frontMatter
+		h.codec = codecOf(frontMatter)
+		if h.mapSlice, e = h.codec.unmarshal(frontMatter); e != nil {
+			h.err = fmt.Errorf("%s %s %w: %v", h.path, h.codec.name(), ErrInvalidFrontMatter, e)
 			return
 		}
 		tags, ok := h.mapSlice.at("Tags")
 		if ok {
 			tagsSlice, e := parseTags(tags)
 			if e != nil {
-				err = e
+				h.err = e
 				return
 			}
 			h.tags = append(h.tags, tagsSlice...)
+			_, h.tagsAsList = tags.([]interface{})
+			h.originalTags = append([]tag.Tag{}, h.tags...)
+			h.originalTagsAsList = h.tagsAsList
 		}
 		created, ok := h.mapSlice.at("Created")
 		if ok {
-			createdTime, e := date.ParseAbsolute(created.(string))
+			createdTime, e := parseCreated(created)
 			if e == nil {
 				h.created = createdTime
 			} else {
-				err = fmt.Errorf("%s parse failed: %v", h.path, e)
+				h.createdErr = fmt.Errorf("%s parse failed: %w", h.path, e)
 			}
 		}
 	})
-	return err
+	return h.err
+}
+
+// parseCreated returns Created given as a string or as a date, which is supported by TOML
+func parseCreated(created interface{}) (time.Time, error) {
+	if t, ok := created.(time.Time); ok {
+		return t, nil
+	}
+	return date.ParseAbsolute(fmt.Sprintf("%v", created))
 }
 
 func parseTags(tags interface{}) ([]tag.Tag, error) {
@@ -127,7 +165,18 @@ func (h *frontMatter) Created() (time.Time, error) {
 	if err := h.ensureParsed(); err != nil {
 		return time.Time{}, err
 	}
-	return h.created, nil
+	return h.created, h.createdErr
+}
+
+func (h *frontMatter) setCreated(created time.Time) error {
+	if err := h.ensureParsed(); err != nil {
+		return err
+	}
+	h.mapSlice = h.mapSlice.set("Created", created.Format(time.RFC3339))
+	h.created = created
+	h.createdErr = nil
+	h.createdChanged = true
+	return nil
 }
 
 func (h *frontMatter) Tags() ([]tag.Tag, error) {
@@ -155,6 +204,20 @@ func (h *frontMatter) setTag(newTag tag.Tag) error {
 	return nil
 }
 
+func (h *frontMatter) mapTags(f func(tag.Tag) (tag.Tag, error)) error {
+	if err := h.ensureParsed(); err != nil {
+		return err
+	}
+	for i, oldTag := range h.tags {
+		newTag, err := f(oldTag)
+		if err != nil {
+			return err
+		}
+		h.tags[i] = newTag
+	}
+	return nil
+}
+
 func (h *frontMatter) removeTag(newTag tag.Tag) error {
 	if err := h.ensureParsed(); err != nil {
 		return err
@@ -181,26 +244,56 @@ func (h *frontMatter) removeTagRegex(regex *regexp.Regexp) error {
 	return nil
 }
 
+// marshal returns front matter with changed values. Only changed values are edited, so comments, formatting and
+// other keys are preserved, unless whole front matter has to be rewritten.
 func (h *frontMatter) marshal() (string, error) {
 	tags, err := h.Tags()
 	if err != nil {
 		return "", err
 	}
-	var stringTags []string
+	stringTags := []string{}
 	for _, t := range tags {
 		stringTags = append(stringTags, t.String())
 	}
-	serializedTags := strings.Join(stringTags, " ")
+	var tagsValue interface{} = strings.Join(stringTags, " ")
+	if h.tagsAsList {
+		tagsValue = stringTags
+	}
 	_, tagsWereGivenBefore := h.mapSlice.at("Tags")
-	if len(serializedTags) != 0 || tagsWereGivenBefore {
-		h.mapSlice = h.mapSlice.set("Tags", serializedTags)
+	writeTags := len(stringTags) != 0 || tagsWereGivenBefore
+	if writeTags {
+		h.mapSlice = h.mapSlice.set("Tags", tagsValue)
 	}
-	marshaledBytes, err := yaml.Marshal(h.mapSlice)
-	if err != nil {
-		return "", err
+	if h.rewrite || h.raw == "" {
+		return h.marshalAll()
+	}
+	var changed []keyValue
+	tagsChanged := h.rewriteTags || h.tagsAsList != h.originalTagsAsList || !equalTags(tags, h.originalTags)
+	if writeTags && tagsChanged {
+		changed = append(changed, keyValue{key: "Tags", value: tagsValue})
 	}
-	if h.mapSlice.isEmpty() {
-		return "", nil
+	if created, ok := h.mapSlice.at("Created"); ok && h.createdChanged {
+		changed = append(changed, keyValue{key: "Created", value: created})
+	}
+	return editAll(h.codec, h.raw, changed...)
+}
+
+// marshalAll returns whole front matter in the original format, or in YAML for notes without front matter
+func (h *frontMatter) marshalAll() (string, error) {
+	if h.raw == "" {
+		return yamlCodec{}.marshal(h.mapSlice)
+	}
+	return h.codec.marshal(h.mapSlice)
+}
+
+func equalTags(a, b []tag.Tag) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
 	}
-	return "---\n" + string(marshaledBytes) + "---\n", nil
+	return true
 }
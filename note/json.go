@@ -0,0 +1,175 @@
+package note
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// jsonCodec reads and writes JSON front matter, which is an object given either between ;;; lines or directly with
+// { and } in separate lines
+type jsonCodec struct {
+	semicolons bool
+}
+
+func (jsonCodec) name() string {
+	return "JSON"
+}
+
+func (c jsonCodec) content(frontMatter string) (content string, offset int, firstLine int) {
+	if !c.semicolons {
+		return frontMatter, 0, 1
+	}
+	content, firstLine = contentOf(frontMatter)
+	return content, len(splitLines(frontMatter)[0]), firstLine
+}
+
+// jsonMember is a top level key with value position in content
+type jsonMember struct {
+	key        string
+	value      json.RawMessage
+	start, end int
+}
+
+// members returns top level keys and offset of the closing brace
+func (c jsonCodec) members(content string) ([]jsonMember, int, error) {
+	decoder := json.NewDecoder(strings.NewReader(content))
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, 0, err
+	}
+	if token != json.Delim('{') {
+		return nil, 0, errors.New("front matter is not a JSON object")
+	}
+	var members []jsonMember
+	for decoder.More() {
+		token, err = decoder.Token()
+		if err != nil {
+			return nil, 0, err
+		}
+		var value json.RawMessage
+		if err = decoder.Decode(&value); err != nil {
+			return nil, 0, err
+		}
+		end := int(decoder.InputOffset())
+		members = append(members, jsonMember{
+			key:   token.(string),
+			value: value,
+			start: end - len(value),
+			end:   end,
+		})
+	}
+	if _, err = decoder.Token(); err != nil {
+		return nil, 0, err
+	}
+	closingBrace := int(decoder.InputOffset()) - 1
+	if _, err = decoder.Token(); err != io.EOF {
+		return nil, 0, errors.New("unexpected data after JSON object")
+	}
+	return members, closingBrace, nil
+}
+
+func (c jsonCodec) unmarshal(frontMatter string) (mapSlice, error) {
+	content, _, firstLine := c.content(frontMatter)
+	members, _, err := c.members(content)
+	if err != nil {
+		line := 0
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			line = strings.Count(content[:syntaxErr.Offset], "\n") + firstLine
+		}
+		return nil, &syntaxError{line: line, err: err}
+	}
+	var items mapSlice
+	for _, m := range members {
+		var value interface{}
+		if err = json.Unmarshal(m.value, &value); err != nil {
+			return nil, &syntaxError{err: err}
+		}
+		items = append(items, yaml.MapItem{Key: m.key, Value: value})
+	}
+	return items, nil
+}
+
+func (c jsonCodec) edit(frontMatter string, kv keyValue) (string, error) {
+	value, err := marshalJSON(kv.value, "")
+	if err != nil {
+		return "", err
+	}
+	content, offset, _ := c.content(frontMatter)
+	members, closingBrace, err := c.members(content)
+	if err != nil {
+		return "", err
+	}
+	for _, m := range members {
+		if strings.EqualFold(m.key, kv.key) {
+			return frontMatter[:offset+m.start] + string(value) + frontMatter[offset+m.end:], nil
+		}
+	}
+	key, _ := marshalJSON(kv.key, "")
+	member := string(key) + ": " + string(value)
+	if len(members) == 0 {
+		insertAt := offset + closingBrace
+		return frontMatter[:insertAt] + member + frontMatter[insertAt:], nil
+	}
+	// new member is written in the same way as the first one: in a new line with the same indentation or inline
+	first := content[:members[0].start]
+	firstKeyStart := strings.LastIndex(first, `"`+members[0].key)
+	if firstKeyStart < 0 {
+		firstKeyStart = strings.LastIndexAny(first, `"`)
+	}
+	separator := " "
+	if lineStart := strings.LastIndex(content[:firstKeyStart], "\n"); lineStart >= 0 {
+		separator = newLineOf(frontMatter) + content[lineStart+1:firstKeyStart]
+	}
+	insertAt := offset + members[len(members)-1].end
+	return frontMatter[:insertAt] + "," + separator + member + frontMatter[insertAt:], nil
+}
+
+func (c jsonCodec) marshal(items mapSlice) (string, error) {
+	var b bytes.Buffer
+	if c.semicolons {
+		b.WriteString(";;;\n")
+	}
+	b.WriteString("{\n")
+	for i, item := range items {
+		key, err := marshalJSON(fmt.Sprintf("%v", item.Key), "")
+		if err != nil {
+			return "", err
+		}
+		value, err := marshalJSON(item.Value, "  ")
+		if err != nil {
+			return "", err
+		}
+		b.WriteString("  " + string(key) + ": " + string(value))
+		if i < len(items)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("}\n")
+	if c.semicolons {
+		b.WriteString(";;;\n")
+	}
+	return b.String(), nil
+}
+
+// marshalJSON returns value indented with two spaces when prefix is given. Characters such as < and & are written
+// as they are, unlike in json.Marshal, which escapes them for HTML.
+func marshalJSON(value interface{}, prefix string) ([]byte, error) {
+	var b bytes.Buffer
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	if prefix != "" {
+		encoder.SetIndent(prefix, "  ")
+	}
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n")), nil
+}
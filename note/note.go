@@ -1,7 +1,9 @@
 package note
 
 import (
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -13,6 +15,7 @@ import (
 
 type Note struct {
 	path            string
+	file            string // path used to read and save the note
 	modified        func() (time.Time, error)
 	originalContent *originalContent
 	frontMatter     *frontMatter
@@ -20,7 +23,21 @@ type Note struct {
 }
 
 func New(path string) *Note {
-	return newWithModifiedFunc(path, readModifiedFunc(path))
+	return newWithModifiedFunc(path, path, readModifiedFunc(path))
+}
+
+// NewInDir returns note with path relative to dir. The note is read from and saved to dir, regardless of the
+// current working directory.
+func NewInDir(dir, path string) *Note {
+	file := filepath.Join(dir, path)
+	return newWithModifiedFunc(path, file, readModifiedFunc(file))
+}
+
+// NewInDirWithModified is like NewInDir, but modification time is given instead of being read from the file.
+func NewInDirWithModified(dir, path string, modified time.Time) *Note {
+	return newWithModifiedFunc(path, filepath.Join(dir, path), func() (time.Time, error) {
+		return modified, nil
+	})
 }
 
 func readModifiedFunc(path string) func() (time.Time, error) {
@@ -35,10 +52,11 @@ func readModifiedFunc(path string) func() (time.Time, error) {
 	}
 }
 
-func newWithModifiedFunc(path string, modified func() (time.Time, error)) *Note {
-	original := &originalContent{path: path}
+func newWithModifiedFunc(path, file string, modified func() (time.Time, error)) *Note {
+	original := &originalContent{path: file}
 	return &Note{
 		path:            path,
+		file:            file,
 		modified:        modified,
 		originalContent: original,
 		frontMatter: &frontMatter{
@@ -52,8 +70,16 @@ func newWithModifiedFunc(path string, modified func() (time.Time, error)) *Note
 	}
 }
 
+// NewFromText returns note with given content, which is not read from the file. Save writes the note to path, but only
+// when content was changed, so use Content to get the text of a new note.
+func NewFromText(path, text string) *Note {
+	n := newWithModifiedFunc(path, path, readModifiedFunc(path))
+	n.originalContent.text = &text
+	return n
+}
+
 func NewWithModified(path string, modified time.Time) *Note {
-	return newWithModifiedFunc(path, func() (time.Time, error) {
+	return newWithModifiedFunc(path, path, func() (time.Time, error) {
 		return modified, nil
 	})
 }
@@ -70,18 +96,68 @@ func (n *Note) Created() (time.Time, error) {
 	return n.frontMatter.Created()
 }
 
+// SetCreated sets Created in front matter using RFC 3339 format
+func (n *Note) SetCreated(created time.Time) error {
+	return n.frontMatter.setCreated(created)
+}
+
 func (n *Note) Tags() ([]tag.Tag, error) {
 	return n.frontMatter.Tags()
 }
 
+// Field returns value of the front matter key, which is case-insensitive, e.g. Title
+func (n *Note) Field(key string) (interface{}, bool, error) {
+	if err := n.frontMatter.ensureParsed(); err != nil {
+		return nil, false, err
+	}
+	value, ok := n.frontMatter.mapSlice.at(key)
+	return value, ok, nil
+}
+
+// Size returns size of the file in bytes
+func (n *Note) Size() (int64, error) {
+	info, err := os.Stat(n.file)
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
 func (n *Note) Body() (string, error) {
 	return n.body.text()
 }
 
+// ReleaseBody drops cached body to bound memory when many notes are kept, e.g. for sorting. Body is read again when
+// needed. Body which was changed, but not saved, is kept.
+func (n *Note) ReleaseBody() {
+	if n.body.release() {
+		n.originalContent.releaseBody()
+	}
+}
+
+// SetBody replaces body. Front matter is preserved.
+func (n *Note) SetBody(body string) {
+	n.body.setText(body)
+}
+
+// BodyLine returns number of the line in the file where body starts (counting from 1)
+func (n *Note) BodyLine() (int, error) {
+	frontMatter, err := n.originalContent.FrontMatter()
+	if err != nil {
+		return 0, err
+	}
+	return strings.Count(frontMatter, "\n") + 1, nil
+}
+
 func (n *Note) SetTag(newTag tag.Tag) error {
 	return n.frontMatter.setTag(newTag)
 }
 
+// MapTags replaces each tag with the one returned by f. Stops on first error.
+func (n *Note) MapTags(f func(tag.Tag) (tag.Tag, error)) error {
+	return n.frontMatter.mapTags(f)
+}
+
 func (n *Note) RemoveTag(newTag tag.Tag) error {
 	return n.frontMatter.removeTag(newTag)
 }
@@ -90,97 +166,189 @@ func (n *Note) RemoveTagRegex(regex *regexp.Regexp) error {
 	return n.frontMatter.removeTagRegex(regex)
 }
 
+// UpdateLink updates links to the file or directory moved from one path to another. Paths are relative to the
+// working directory, the same as the path of the note.
 func (n *Note) UpdateLink(from, to string) error {
+	return n.rewriteLinks(func(linkPath string) (string, bool) {
+		return UpdatedLink(n.path, linkPath, from, to)
+	})
+}
+
+// RelocateLinks updates relative links of the note, which was moved from oldPath, so they point to the same files
+// from the new location
+func (n *Note) RelocateLinks(oldPath string) error {
+	return n.rewriteLinks(func(linkPath string) (string, bool) {
+		return RelocatedLink(oldPath, n.path, linkPath)
+	})
+}
+
+var markdownLinkRegexp = regexp.MustCompile(`(\[[^][]+])\(([^()]+)\)`) // TODO does not take into account code fences
+
+// rewriteLinks replaces paths of links in the body with paths returned by newPath. Titles and anchors are kept.
+func (n *Note) rewriteLinks(newPath func(linkPath string) (string, bool)) error {
 	body, err := n.body.text()
 	if err != nil {
 		return err
 	}
-	newBody, err := replaceLinks{notePath: n.path, from: from, to: to}.run(body)
-	if err != nil {
-		return err
+	newBody := markdownLinkRegexp.ReplaceAllStringFunc(body, func(s string) string {
+		match := markdownLinkRegexp.FindStringSubmatch(s)
+		lead, linkPath, rest := splitLinkTarget(match[2])
+		p, ok := newPath(linkPath)
+		if !ok || p == linkPath {
+			return s
+		}
+		return match[1] + "(" + lead + p + rest + ")"
+	})
+	if newBody != body {
+		n.body.setText(newBody)
 	}
-	n.body.setText(newBody)
 	return nil
 }
 
-type replaceLinks struct {
-	notePath string
-	from, to string
+// splitLinkTarget splits link target into leading spaces, path and the rest, such as an anchor or a title
+func splitLinkTarget(target string) (lead, path, rest string) {
+	path = strings.TrimLeft(target, " ")
+	lead = target[:len(target)-len(path)]
+	if end := strings.IndexAny(path, " \t#"); end >= 0 {
+		path, rest = path[:end], path[end:]
+	}
+	return lead, path, rest
 }
 
-func (u replaceLinks) run(body string) (newBody string, returnedError error) {
-	relativeFrom, err := u.relativePath(u.from)
+// Links returns paths of local files linked from the body, relative to the working directory. External links and
+// anchors are skipped.
+func (n *Note) Links() ([]string, error) {
+	body, err := n.body.text()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	markdownLinkRegexp := regexp.MustCompile(`(\[[^][]+])\(([^()]+)\)`) // TODO does not take into account code fences
-	newBody = markdownLinkRegexp.ReplaceAllStringFunc(body, func(s string) string {
-		linkPath := markdownLinkRegexp.FindStringSubmatch(s)[2]
-		relativeLinkPath, err := u.relativePath(linkPath)
-		if err != nil {
-			returnedError = err
-			return s
-		}
-		if relativeFrom == relativeLinkPath {
-			return markdownLinkRegexp.ReplaceAllString(s, `$1(`+u.to+`)`)
-		}
-		if isAncestorPath(relativeFrom, relativeLinkPath) {
-			newTo := u.to + strings.TrimPrefix(relativeLinkPath, relativeFrom)
-			newTo = filepath.ToSlash(newTo) // always use slashes, even on Windows
-			return markdownLinkRegexp.ReplaceAllString(s, `$1(`+newTo+`)`)
+	var links []string
+	for _, match := range markdownLinkRegexp.FindAllStringSubmatch(body, -1) {
+		_, linkPath, _ := splitLinkTarget(match[2])
+		if file, ok := LinkedFile(n.path, linkPath); ok {
+			links = append(links, file)
 		}
-		return s
-	})
-	return
+	}
+	return links, nil
+}
+
+// LinkedFile returns path of the file which link path, written in the note at notePath, points to. Relative link
+// path is joined with the directory of the note. Returns false for external links and empty paths.
+func LinkedFile(notePath, linkPath string) (string, bool) {
+	if linkPath == "" || strings.Contains(linkPath, "://") || strings.HasPrefix(linkPath, "mailto:") {
+		return "", false
+	}
+	if unescaped, err := url.PathUnescape(linkPath); err == nil {
+		linkPath = unescaped
+	}
+	p := filepath.FromSlash(linkPath)
+	if filepath.IsAbs(p) {
+		return p, true
+	}
+	return filepath.Join(filepath.Dir(notePath), p), true
 }
 
-// Returns relative path to note path
-func (u replaceLinks) relativePath(p string) (string, error) {
-	dir := filepath.Dir(u.notePath)
-	relativePath := p
-	if !filepath.IsAbs(relativePath) {
-		relativePath = filepath.Join(dir, p)
+// UpdatedLink returns new link path, written in the note at notePath, when the file or directory which the link
+// points to was moved from one path to another. Returns false when the link points elsewhere. Relative from and to
+// given with absolute notePath are relative to the directory of the note.
+func UpdatedLink(notePath, linkPath, from, to string) (string, bool) {
+	file, ok := LinkedFile(notePath, linkPath)
+	if !ok {
+		return "", false
+	}
+	from, to = resolvePath(notePath, from), resolvePath(notePath, to)
+	rel, err := filepath.Rel(from, filepath.Clean(file))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
 	}
-	return filepath.Rel(dir, relativePath)
+	return relativeLink(notePath, filepath.Join(to, rel))
 }
 
-func isAncestorPath(relativeAncestorPath string, relativeDescendantPath string) bool {
-	rel, err := filepath.Rel(relativeAncestorPath, relativeDescendantPath)
+// RelocatedLink returns new relative link path, written in the note moved from oldPath to newPath, which points to
+// the same file as before. Links to the note itself point to newPath. Returns false for absolute and external links.
+func RelocatedLink(oldPath, newPath, link string) (string, bool) {
+	if filepath.IsAbs(filepath.FromSlash(link)) {
+		return "", false
+	}
+	file, ok := LinkedFile(oldPath, link)
+	if !ok {
+		return "", false
+	}
+	if filepath.Clean(file) == filepath.Clean(oldPath) {
+		file = newPath
+	}
+	newLink, ok := relativeLink(newPath, file)
+	if old, _ := url.PathUnescape(link); ok && path.Clean(old) == path.Clean(strings.ReplaceAll(newLink, "%20", " ")) {
+		return "", false // link was not changed, but could be written differently
+	}
+	return newLink, ok
+}
+
+// relativeLink returns path of the file relative to the directory of the note at notePath, with slashes. Spaces are
+// escaped, because they end the path in Markdown link.
+func relativeLink(notePath, file string) (string, bool) {
+	rel, err := filepath.Rel(filepath.Dir(notePath), file)
 	if err != nil {
-		return false
+		return "", false
+	}
+	return strings.ReplaceAll(filepath.ToSlash(rel), " ", "%20"), true
+}
+
+func resolvePath(notePath, p string) string {
+	if filepath.IsAbs(notePath) && !filepath.IsAbs(p) {
+		p = filepath.Join(filepath.Dir(notePath), p)
 	}
-	return filepath.Dir(rel) == "."
+	return filepath.Clean(p)
 }
 
 // Save returns true if file was modified.
 func (n *Note) Save() (bool, error) {
+	newContent, changed, err := n.newContent()
+	if err != nil || !changed {
+		return false, err
+	}
+	newBytes := []byte(newContent)
+	if err := os.WriteFile(n.file, newBytes, 0664); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Content returns the text which is written by Save.
+func (n *Note) Content() (string, error) {
+	content, _, err := n.newContent()
+	return content, err
+}
+
+// Changed returns true if Save would modify the file.
+func (n *Note) Changed() (bool, error) {
+	_, changed, err := n.newContent()
+	return changed, err
+}
+
+func (n *Note) newContent() (string, bool, error) {
 	frontMatter, err := n.frontMatter.marshal()
 	if err != nil {
-		return false, err
+		return "", false, err
 	}
 	text, err := n.Body()
 	if err != nil {
-		return false, err
+		return "", false, err
 	}
 	newContent := frontMatter + text
 	original, err := n.originalContent.Full()
 	if err != nil {
-		return false, err
-	}
-	if original == newContent {
-		return false, nil
+		return "", false, err
 	}
-	newBytes := []byte(newContent)
-	if err := os.WriteFile(n.path, newBytes, 0664); err != nil {
-		return false, err
-	}
-	return true, nil
+	return newContent, original != newContent, nil
 }
 
 type body struct {
 	body     *string
 	original func() (string, error)
 	mutex    sync.Mutex
+	// modified is true when body was set and must not be released
+	modified bool
 }
 
 func (t *body) text() (string, error) {
@@ -202,4 +370,17 @@ func (t *body) setText(body string) {
 	defer t.mutex.Unlock()
 
 	t.body = &body
+	t.modified = true
+}
+
+// release drops not modified body and returns true if it was dropped
+func (t *body) release() bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.modified {
+		return false
+	}
+	t.body = nil
+	return true
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -27,6 +28,30 @@ func TestNewWithModified(t *testing.T) {
 	})
 }
 
+func TestNewInDir(t *testing.T) {
+	t.Run("should read and save file in dir", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "note.md"), []byte("body"), os.ModePerm))
+		n := note.NewInDir(dir, "note.md")
+		// when
+		body, err := n.Body()
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "body", body)
+		assert.Equal(t, "note.md", n.Path())
+		newTag, err := tag.New("tag")
+		require.NoError(t, err)
+		// when
+		require.NoError(t, n.SetTag(newTag))
+		_, err = n.Save()
+		// then
+		require.NoError(t, err)
+		bytes, err := os.ReadFile(filepath.Join(dir, "note.md"))
+		require.NoError(t, err)
+		assert.Equal(t, "---\nTags: tag\n---\nbody", string(bytes))
+	})
+}
+
 func TestNote_Path(t *testing.T) {
 	t.Run("should return path", func(t *testing.T) {
 		n := note.New("path")
@@ -88,6 +113,65 @@ func TestNote_Created(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, expectedTime, created)
 	})
+
+	t.Run("should return time from TOML and JSON front matter", func(t *testing.T) {
+		expectedTime := time.Date(2020, 9, 5, 12, 30, 5, 0, time.UTC)
+		for _, content := range []string{
+			"+++\ncreated = 2020-09-05T12:30:05Z\n+++\nbody",
+			"+++\ncreated = \"2020-09-05T12:30:05Z\"\n+++\nbody",
+			"{\n\"created\": \"2020-09-05T12:30:05Z\"\n}\nbody",
+			";;;\n{\"created\": \"2020-09-05T12:30:05Z\"}\n;;;\nbody",
+		} {
+			filename := writeTempFile(t, content)
+			n := note.New(filename)
+			// when
+			created, err := n.Created()
+			// then
+			require.NoError(t, err)
+			assert.True(t, expectedTime.Equal(created), content)
+		}
+	})
+}
+
+func TestNote_ReleaseBody(t *testing.T) {
+	t.Run("should read body again", func(t *testing.T) {
+		filename := writeTempFile(t, "---\nTags: a\n---\nold")
+		n := note.New(filename)
+		_, err := n.Body()
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filename, []byte("---\nTags: a\n---\nnew"), 0664))
+		// when
+		n.ReleaseBody()
+		// then
+		body, err := n.Body()
+		require.NoError(t, err)
+		assert.Equal(t, "new", body)
+	})
+
+	t.Run("should not read body of changed front matter", func(t *testing.T) {
+		filename := writeTempFile(t, "---\nTags: a\n---\nold")
+		n := note.New(filename)
+		_, err := n.Body()
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filename, []byte("---\nTags: b\n---\nnew"), 0664))
+		// when
+		n.ReleaseBody()
+		// then
+		_, err = n.Body()
+		assert.Error(t, err)
+	})
+
+	t.Run("should keep modified body", func(t *testing.T) {
+		filename := writeTempFile(t, "old")
+		n := note.New(filename)
+		n.SetBody("modified")
+		// when
+		n.ReleaseBody()
+		// then
+		body, err := n.Body()
+		require.NoError(t, err)
+		assert.Equal(t, "modified", body)
+	})
 }
 
 func TestNote_Tags(t *testing.T) {
@@ -126,6 +210,17 @@ func TestNote_Tags(t *testing.T) {
 				content:      "---\nTags: [\"tag \"]\n---",
 				expectedTags: []string{"tag"},
 			},
+			"TOML": {
+				content:      "+++\ntags = [\"tag1\", \"tag2\"]\n+++",
+				expectedTags: []string{"tag1", "tag2"},
+			},
+			"JSON": {
+				content:      "{\n  \"tags\": \"tag1 tag2\"\n}",
+				expectedTags: []string{"tag1", "tag2"},
+			},
+			"horizontal rule is not a front matter": {
+				content: "----\nTags: tag\n----",
+			},
 		}
 		for name, test := range tests {
 			t.Run(name, func(t *testing.T) {
@@ -136,6 +231,15 @@ func TestNote_Tags(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("should return ErrInvalidFrontMatter", func(t *testing.T) {
+		filename := writeTempFile(t, "---\nTags: [tag\n---")
+		n := note.New(filename)
+		// when
+		_, err := n.Tags()
+		// then
+		assert.ErrorIs(t, err, note.ErrInvalidFrontMatter)
+	})
 }
 
 func TestNote_SetTag(t *testing.T) {
@@ -201,6 +305,18 @@ func TestNote_RemoveTag(t *testing.T) {
 		assertNoTags(t, n)
 	})
 
+	t.Run("should keep comments of block sequence", func(t *testing.T) {
+		filename := writeTempFile(t, "---\nTags:\n  - a # c1\n  # between\n  - b # c2\n  - c\n---\ntext")
+		n := note.New(filename)
+		// when
+		err := n.RemoveTag(newTag(t, "b"))
+		// then
+		require.NoError(t, err)
+		_, err = n.Save()
+		require.NoError(t, err)
+		assertFileEquals(t, filename, "---\nTags:\n  - a # c1\n  # between\n  - c\n---\ntext")
+	})
+
 	t.Run("removing missing tag does nothing", func(t *testing.T) {
 		filename := writeTempFile(t, "content")
 		n := note.New(filename)
@@ -283,6 +399,27 @@ func TestNote_Body(t *testing.T) {
 	})
 }
 
+func TestNote_Field(t *testing.T) {
+	filename := writeTempFile(t, "---\ntitle: Meeting\nTags: tag\n---\nbody")
+	n := note.New(filename)
+	// when
+	value, found, err := n.Field("Title")
+	// then
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "Meeting", value)
+}
+
+func TestNote_Links(t *testing.T) {
+	n := note.NewInDir(t.TempDir(), filepath.Join("dir", "note.md"))
+	n.SetBody("[a](a.md) [b](../b%20c.md#section) [site](https://example.com) [anchor](#top) [mail](mailto:me@x)")
+	// when
+	links, err := n.Links()
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join("dir", "a.md"), "b c.md"}, links)
+}
+
 func TestNote_UpdateLink(t *testing.T) {
 	t.Run("should not change the body if link is missing", func(t *testing.T) {
 		filename := writeTempFile(t, "body")
@@ -366,6 +503,35 @@ func TestNote_UpdateLink(t *testing.T) {
 		assert.Equal(t, "[link](other.md)", body)
 	})
 
+	t.Run("should keep anchor and write link relative to the note directory", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "sub"), os.ModePerm))
+		filename := filepath.Join(dir, "sub", "note.md")
+		require.NoError(t, os.WriteFile(filename, []byte("[link](../from.md#section)"), os.ModePerm))
+		n := note.New(filename)
+		// when
+		err := n.UpdateLink(filepath.Join(dir, "from.md"), filepath.Join(dir, "archive", "to.md"))
+		require.NoError(t, err)
+		// then
+		body, err := n.Body()
+		require.NoError(t, err)
+		assert.Equal(t, "[link](../archive/to.md#section)", body)
+	})
+}
+
+func TestNote_RelocateLinks(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "sub"), os.ModePerm))
+	filename := filepath.Join(dir, "sub", "note.md")
+	require.NoError(t, os.WriteFile(filename, []byte("[a](other.md) [self](note.md#top) [web](https://x.com)"), os.ModePerm))
+	n := note.New(filename)
+	// when
+	err := n.RelocateLinks(filepath.Join(dir, "note.md"))
+	require.NoError(t, err)
+	// then
+	body, err := n.Body()
+	require.NoError(t, err)
+	assert.Equal(t, "[a](../other.md) [self](note.md#top) [web](https://x.com)", body)
 }
 
 func writeTempFile(t *testing.T, content string) string {
@@ -406,3 +572,176 @@ func newTag(t *testing.T, name string) tag.Tag {
 	require.NoError(t, err)
 	return createdTag
 }
+
+func TestNote_Check(t *testing.T) {
+	t.Run("should not report problems", func(t *testing.T) {
+		filename := writeTempFile(t, "---\nCreated: 2020-09-05T12:30:05Z\nTags: a b:1\n---\nbody")
+		// when
+		problems, err := note.New(filename).Check()
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, problems)
+	})
+
+	t.Run("should report problems", func(t *testing.T) {
+		tests := map[string]struct {
+			content         string
+			expectedLine    int
+			expectedMessage string
+			expectedFixable bool
+		}{
+			"invalid YAML": {
+				content:         "---\nCreated: 2020-09-05T12:30:05Z\nTags: [a\n---\n",
+				expectedLine:    3,
+				expectedMessage: "invalid YAML: line 3: did not find expected ',' or ']'",
+			},
+			"missing Created": {
+				content:         "body",
+				expectedMessage: "missing Created",
+				expectedFixable: true,
+			},
+			"Created in unsupported layout": {
+				content:         "---\nTags: a\nCreated: 2020-09-05 12:30\n---\n",
+				expectedLine:    3,
+				expectedMessage: `Created "2020-09-05 12:30" has unsupported layout`,
+				expectedFixable: true,
+			},
+			"unparsable Created": {
+				content:         "---\nCreated: someday\n---\n",
+				expectedLine:    2,
+				expectedMessage: `Created "someday" has unsupported layout`,
+			},
+			"tag with empty value": {
+				content:         "---\nCreated: 2020-09-05T12:30:05Z\nTags: [\"a:\"]\n---\n",
+				expectedLine:    3,
+				expectedMessage: "tag a: has empty value",
+				expectedFixable: true,
+			},
+			"duplicate tag": {
+				content:         "---\nCreated: 2020-09-05T12:30:05Z\nTags:\n- a\n- a\n---\n",
+				expectedLine:    4,
+				expectedMessage: "duplicate tag name a",
+				expectedFixable: true,
+			},
+			"duplicate tag name": {
+				content:         "---\nCreated: 2020-09-05T12:30:05Z\nTags: p:1 p:2\n---\n",
+				expectedLine:    3,
+				expectedMessage: "duplicate tag name p",
+			},
+			"invalid TOML": {
+				content:         "+++\ncreated = 2020-09-05T12:30:05Z\ntags = [\n+++\n",
+				expectedLine:    3,
+				expectedMessage: "invalid TOML: line 2 (last key \"tags\"): unexpected EOF; expected value",
+			},
+			"invalid tag": {
+				content:         "---\nCreated: 2020-09-05T12:30:05Z\nTags: [\"a b\"]\n---\n",
+				expectedLine:    3,
+				expectedMessage: "a b is not a valid tag",
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				filename := writeTempFile(t, test.content)
+				// when
+				problems, err := note.New(filename).Check()
+				// then
+				require.NoError(t, err)
+				require.Len(t, problems, 1)
+				assert.Equal(t, test.expectedLine, problems[0].Line)
+				assert.Equal(t, test.expectedMessage, problems[0].Message)
+				assert.Equal(t, test.expectedFixable, problems[0].Fixable())
+			})
+		}
+	})
+
+	t.Run("should fix problems", func(t *testing.T) {
+		filename := writeTempFile(t, "---\nCreated: 2020-09-05T12:30\nTags: [\"a:\", b, b]\n---\nbody")
+		n := note.New(filename)
+		problems, err := n.Check()
+		require.NoError(t, err)
+		// when
+		for _, problem := range problems {
+			require.NoError(t, problem.Fix(n))
+		}
+		_, err = n.Save()
+		// then
+		require.NoError(t, err)
+		created := time.Date(2020, 9, 5, 12, 30, 0, 0, time.Local).Format(time.RFC3339)
+		assertFileEquals(t, filename, "---\nCreated: \""+created+"\"\nTags: [a, b]\n---\nbody")
+	})
+}
+
+func TestNote_Format(t *testing.T) {
+	tests := map[string]struct {
+		content         string
+		format          note.Format
+		expectedContent string
+	}{
+		"date layout": {
+			content:         "---\nCreated: Sat Sep  5 12:30:05 UTC 2020\n---\nbody",
+			format:          note.Format{DateLayout: time.RFC3339},
+			expectedContent: "---\nCreated: \"2020-09-05T12:30:05Z\"\n---\nbody",
+		},
+		"tags as list": {
+			content:         "---\nTags: a,b\n---\nbody",
+			format:          note.Format{TagsAsList: true},
+			expectedContent: "---\nTags:\n- a\n- b\n---\nbody",
+		},
+		"tags as string": {
+			content:         "---\nTags: [a, b]\n---\nbody",
+			format:          note.Format{},
+			expectedContent: "---\nTags: a b\n---\nbody",
+		},
+		"key order and case": {
+			content:         "---\nfoo: bar\ntags: a\ncreated: 2020-09-05\n---\nbody",
+			format:          note.Format{KeyOrder: []string{"Created", "Tags"}, KeyCase: note.TitleCase},
+			expectedContent: "---\nCreated: \"2020-09-05\"\nTags: a\nFoo: bar\n---\nbody",
+		},
+		"JSON key order": {
+			content:         "{\n\"tags\": \"R&D\",\n\"title\": \"Q&A <draft>\"\n}\nbody",
+			format:          note.Format{KeyOrder: []string{"title"}},
+			expectedContent: "{\n  \"title\": \"Q&A <draft>\",\n  \"tags\": \"R&D\"\n}\nbody",
+		},
+		"no front matter": {
+			content:         "  body\n\n",
+			format:          note.Format{DateLayout: time.RFC3339, TagsAsList: true},
+			expectedContent: "  body\n\n",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			filename := writeTempFile(t, test.content)
+			n := note.New(filename)
+			// when
+			err := n.Format(test.format)
+			// then
+			require.NoError(t, err)
+			_, err = n.Save()
+			require.NoError(t, err)
+			assertFileEquals(t, filename, test.expectedContent)
+		})
+	}
+}
+
+func TestNote_Save_PreservesFrontMatter(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "edit", "*.md"))
+	require.NoError(t, err)
+	require.NotEmpty(t, inputs)
+	for _, input := range inputs {
+		name := strings.TrimSuffix(filepath.Base(input), ".md")
+		t.Run(name, func(t *testing.T) {
+			content, err := os.ReadFile(input)
+			require.NoError(t, err)
+			filename := writeTempFile(t, string(content))
+			n := note.New(filename)
+			// when
+			require.NoError(t, n.SetTag(newTag(t, "new")))
+			_, err = n.Save()
+			// then
+			require.NoError(t, err)
+			golden, err := os.ReadFile(strings.TrimSuffix(input, ".md") + ".golden")
+			require.NoError(t, err)
+			assertFileEquals(t, filename, string(golden))
+		})
+	}
+}
@@ -1,45 +1,84 @@
 package note
 
 import (
+	"fmt"
+	"io"
 	"os"
+	"strings"
 	"sync"
 
 	"github.com/elgopher/noteo/parser"
 )
 
+// originalContent is the content of the file. Front matter is read without reading the body, so filtering by tags
+// does not read whole files. Body is read when needed and can be released to bound memory.
 type originalContent struct {
-	path        string
-	once        sync.Once
-	frontMatter string
-	body        string
+	path string
+	// text, when not nil, is used instead of reading the file
+	text        *string
+	mutex       sync.Mutex
+	frontMatter *string
+	body        *string
 }
 
-func (c *originalContent) ensureLoaded() error {
-	var err error
-	c.once.Do(func() {
-		var file *os.File
-		file, err = os.Open(c.path)
+func (c *originalContent) FrontMatter() (string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.frontMatter == nil {
+		file, err := c.open()
 		if err != nil {
-			return
+			return "", err
 		}
 		defer file.Close()
-		c.frontMatter, c.body, err = parser.Parse(file)
-	})
-	return err
+		frontMatter, err := parser.ParseFrontMatter(file)
+		if err != nil {
+			return "", err
+		}
+		c.frontMatter = &frontMatter
+	}
+	return *c.frontMatter, nil
 }
 
-func (c *originalContent) FrontMatter() (string, error) {
-	if err := c.ensureLoaded(); err != nil {
-		return "", err
+func (c *originalContent) Body() (string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.body == nil {
+		file, err := c.open()
+		if err != nil {
+			return "", err
+		}
+		defer file.Close()
+		frontMatter, body, err := parser.Parse(file)
+		if err != nil {
+			return "", err
+		}
+		if c.frontMatter == nil {
+			c.frontMatter = &frontMatter
+		} else if *c.frontMatter != frontMatter {
+			// front matter and body would come from different versions of the file
+			return "", fmt.Errorf("%s was changed after its front matter had been read", c.path)
+		}
+		c.body = &body
 	}
-	return c.frontMatter, nil
+	return *c.body, nil
 }
 
-func (c *originalContent) Body() (string, error) {
-	if err := c.ensureLoaded(); err != nil {
-		return "", err
+func (c *originalContent) open() (io.ReadCloser, error) {
+	if c.text != nil {
+		return io.NopCloser(strings.NewReader(*c.text)), nil
 	}
-	return c.body, nil
+	return os.Open(c.path)
+}
+
+// releaseBody drops the body, which will be read again when needed. Body read again must come with the same front
+// matter.
+func (c *originalContent) releaseBody() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.body = nil
 }
 
 func (c *originalContent) Full() (string, error) {
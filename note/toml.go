@@ -0,0 +1,197 @@
+package note
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+)
+
+// tomlCodec reads and writes TOML front matter between +++ lines
+type tomlCodec struct{}
+
+func (tomlCodec) name() string {
+	return "TOML"
+}
+
+func (tomlCodec) unmarshal(frontMatter string) (mapSlice, error) {
+	content, firstLine := contentOf(frontMatter)
+	values := map[string]interface{}{}
+	metaData, err := toml.Decode(content, &values)
+	if err != nil {
+		line := 0
+		var parseError toml.ParseError
+		if errors.As(err, &parseError) {
+			line = parseError.Position.Line + firstLine - 1
+		}
+		return nil, &syntaxError{line: line, err: err}
+	}
+	var items mapSlice
+	for _, key := range metaData.Keys() {
+		if len(key) != 1 {
+			continue
+		}
+		items = append(items, yaml.MapItem{Key: key[0], Value: values[key[0]]})
+	}
+	return items, nil
+}
+
+func (tomlCodec) edit(frontMatter string, kv keyValue) (string, error) {
+	value, err := tomlValue(kv.value)
+	if err != nil {
+		return "", err
+	}
+	lines := splitLines(frontMatter)
+	if len(lines) < 2 {
+		return "", errors.New("front matter without closing +++")
+	}
+	closing := len(lines) - 1
+	offset := len(lines[0])
+	for i := 1; i < closing; i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			// top level keys end at the first table
+			closing = i
+			break
+		}
+		key, _, found := strings.Cut(trimmed, "=")
+		if found && strings.EqualFold(strings.Trim(strings.TrimSpace(key), `"'`), kv.key) {
+			valueStart := offset + strings.Index(line, "=") + 1
+			rest := frontMatter[valueStart:]
+			leadingSpace := len(rest) - len(strings.TrimLeft(rest, " \t"))
+			end := tomlValueEnd(rest)
+			oldValue := strings.TrimRight(rest[:end], " \t\r")
+			return frontMatter[:valueStart+leadingSpace] + value + frontMatter[valueStart+len(oldValue):], nil
+		}
+		offset += len(line)
+	}
+	for closing > 1 && strings.TrimSpace(lines[closing-1]) == "" {
+		closing--
+	}
+	added := kv.key + " = " + value + newLineOf(frontMatter)
+	return strings.Join(lines[:closing], "") + added + strings.Join(lines[closing:], ""), nil
+}
+
+// tomlValueEnd returns offset of the end of the value, which is a new line or comment outside strings and arrays
+func tomlValueEnd(s string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case strings.HasPrefix(s[i:], `"""`) || strings.HasPrefix(s[i:], `'''`):
+			end := strings.Index(s[i+3:], s[i:i+3])
+			if end < 0 {
+				return len(s)
+			}
+			i += 3 + end + 2
+		case s[i] == '"':
+			for i++; i < len(s) && s[i] != '"' && s[i] != '\n'; i++ {
+				if s[i] == '\\' {
+					i++
+				}
+			}
+		case s[i] == '\'':
+			for i++; i < len(s) && s[i] != '\'' && s[i] != '\n'; i++ {
+			}
+		case s[i] == '[' || s[i] == '{':
+			depth++
+		case s[i] == ']' || s[i] == '}':
+			depth--
+		case s[i] == '#':
+			if depth <= 0 {
+				return i
+			}
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+		case s[i] == '\n':
+			if depth <= 0 {
+				return i
+			}
+		}
+	}
+	return len(s)
+}
+
+func (tomlCodec) marshal(items mapSlice) (string, error) {
+	var simple, tables strings.Builder
+	for _, item := range items {
+		key := fmt.Sprintf("%v", item.Key)
+		value, err := tomlValue(item.Value)
+		if err == nil {
+			simple.WriteString(tomlKey(key) + " = " + value + "\n")
+			continue
+		}
+		var table bytes.Buffer
+		if err = toml.NewEncoder(&table).Encode(map[string]interface{}{key: item.Value}); err != nil {
+			return "", err
+		}
+		tables.WriteString("\n" + table.String())
+	}
+	return "+++\n" + simple.String() + tables.String() + "+++\n", nil
+}
+
+// tomlValue returns value which can be written in a single line, such as string, number, date or array of them
+func tomlValue(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case string:
+		if t, err := time.Parse(time.RFC3339, v); err == nil && t.Format(time.RFC3339) == v {
+			return v, nil // offset date-time
+		}
+		return tomlString(v), nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	case bool, int, int64, float64:
+		return fmt.Sprintf("%v", v), nil
+	case []string:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = item
+		}
+		return tomlValue(items)
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			value, err := tomlValue(item)
+			if err != nil {
+				return "", err
+			}
+			items[i] = value
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	default:
+		return "", unsupportedValue(v)
+	}
+}
+
+func tomlKey(key string) string {
+	for _, r := range key {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return tomlString(key)
+		}
+	}
+	return key
+}
+
+// tomlString returns basic string, which is enclosed in quotation marks
+func tomlString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20 || r == 0x7f:
+			b.WriteString(fmt.Sprintf(`\u%04X`, r))
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,251 @@
+package note
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v2"
+	yaml3 "gopkg.in/yaml.v3"
+)
+
+// editYAML replaces value of the key in YAML front matter (including --- lines), leaving everything else, such as
+// comments, quoting, anchors or line endings, byte-identical. Missing key is added before closing ---.
+func editYAML(frontMatter string, kv keyValue) (string, error) {
+	lines := splitLines(frontMatter)
+	if len(lines) < 2 {
+		return "", errors.New("front matter without closing ---")
+	}
+	newLine := "\n"
+	if strings.HasSuffix(lines[0], "\r\n") {
+		newLine = "\r\n"
+	}
+	closing := len(lines) - 1
+	mapping, err := parseMapping(strings.Join(lines[1:closing], ""))
+	if err != nil {
+		return "", err
+	}
+	// line numbers of nodes are counted from the line after opening ---, which has index 0 in lines
+	var content []*yaml3.Node
+	if mapping != nil {
+		content = mapping.Content
+	}
+	for i := 0; i+1 < len(content); i += 2 {
+		key, value := content[i], content[i+1]
+		if !strings.EqualFold(key.Value, kv.key) {
+			continue
+		}
+		last := closing - 1
+		if i+2 < len(content) {
+			last = content[i+2].Line - 1
+		}
+		// comments and blank lines before the next key are not part of the value
+		for last > key.Line && isBlankOrComment(lines[last]) {
+			last--
+		}
+		replaced := replaceValue(lines[key.Line:last+1], key, value, kv.value, newLine)
+		return strings.Join(lines[:key.Line], "") + replaced + strings.Join(lines[last+1:], ""), nil
+	}
+	added := kv.key + ":" + renderValue(kv.value, nil, newLine) + newLine
+	return strings.Join(lines[:closing], "") + added + lines[closing], nil
+}
+
+func parseMapping(content string) (*yaml3.Node, error) {
+	var document yaml3.Node
+	if err := yaml3.Unmarshal([]byte(content), &document); err != nil {
+		return nil, err
+	}
+	if document.Kind == 0 || len(document.Content) == 0 {
+		return nil, nil
+	}
+	mapping := document.Content[0]
+	if mapping.Kind != yaml3.MappingNode {
+		return nil, errors.New("front matter is not a YAML mapping")
+	}
+	return mapping, nil
+}
+
+// replaceValue returns lines with value of the key replaced. First line contains the key.
+func replaceValue(lines []string, key, oldValue *yaml3.Node, newValue interface{}, newLine string) string {
+	first := lines[0]
+	keyStart := byteOffset(first, key.Column-1)
+	colon := keyStart + strings.Index(first[keyStart:], ":")
+	if colon < keyStart {
+		colon = keyStart + len(key.Value)
+	}
+	prefix := first[:colon+1]
+	lastLine := lines[len(lines)-1]
+	lineEnding := lastLine[len(strings.TrimRight(lastLine, "\r\n")):]
+	suffix := lineEnding
+	comment := oldValue.LineComment
+	if comment == "" {
+		comment = key.LineComment
+	}
+	if comment != "" {
+		if i := strings.LastIndex(lastLine, comment); i >= 0 {
+			suffix = lastLine[len(strings.TrimRight(lastLine[:i], " \t")):]
+		}
+	}
+	items, isList := newValue.([]string)
+	if isList && len(items) > 0 && len(lines) > 1 && isBlockSequence(oldValue) {
+		// block sequence is written in the lines following the key, which is left as it is
+		itemLine := lines[1]
+		if len(oldValue.Content) > 0 && oldValue.Content[0].Line-key.Line < len(lines) {
+			itemLine = lines[oldValue.Content[0].Line-key.Line]
+		}
+		indent := itemLine[:len(itemLine)-len(strings.TrimLeft(itemLine, " \t"))]
+		return first + commentedBlockSequence(lines, key, oldValue, items, indent, newLine) + lineEnding
+	}
+	return prefix + renderValue(newValue, oldValue, newLine) + suffix
+}
+
+// commentedBlockSequence returns items written as block sequence without the first new line. Line comments of kept
+// items, such as "- a # comment", are kept. Comment lines are kept before the next kept item, or at the end.
+func commentedBlockSequence(lines []string, key, oldValue *yaml3.Node, items []string, indent, newLine string) string {
+	type oldItem struct {
+		value   string
+		before  []string // comment and blank lines before the item
+		comment string   // line comment with leading spaces
+		used    bool
+	}
+	var oldItems []*oldItem
+	next := 1
+	for _, item := range oldValue.Content {
+		i := item.Line - key.Line
+		if item.Kind != yaml3.ScalarNode || i < next || i >= len(lines) {
+			continue
+		}
+		old := &oldItem{value: item.Value, before: lines[next:i]}
+		line := strings.TrimRight(lines[i], "\r\n")
+		if item.LineComment != "" {
+			if c := strings.LastIndex(line, item.LineComment); c >= 0 {
+				old.comment = line[len(strings.TrimRight(line[:c], " \t")):]
+			}
+		}
+		oldItems = append(oldItems, old)
+		next = i + 1
+	}
+	matches := make([]*oldItem, len(items))
+	for i, item := range items {
+		for _, old := range oldItems {
+			if !old.used && old.value == item {
+				old.used = true
+				matches[i] = old
+				break
+			}
+		}
+	}
+	var orphaned []string // comment lines of removed items
+	for _, old := range oldItems {
+		if !old.used {
+			orphaned = append(orphaned, old.before...)
+			continue
+		}
+		old.before = append(orphaned, old.before...)
+		orphaned = nil
+	}
+	var block strings.Builder
+	writeLines := func(lines []string) {
+		for _, line := range lines {
+			block.WriteString(strings.TrimRight(line, "\r\n") + newLine)
+		}
+	}
+	for i, item := range items {
+		comment := ""
+		if old := matches[i]; old != nil {
+			writeLines(old.before)
+			comment = old.comment
+		}
+		block.WriteString(indent + "- " + scalar(item) + comment + newLine)
+	}
+	writeLines(orphaned)
+	return strings.TrimSuffix(block.String(), newLine)
+}
+
+func isBlockSequence(n *yaml3.Node) bool {
+	return n != nil && n.Kind == yaml3.SequenceNode && n.Style&yaml3.FlowStyle == 0
+}
+
+// renderValue returns value starting with a space, or with a new line for block sequence
+func renderValue(value interface{}, oldValue *yaml3.Node, newLine string) string {
+	anchor := ""
+	if oldValue != nil && oldValue.Anchor != "" {
+		anchor = " &" + oldValue.Anchor
+	}
+	switch v := value.(type) {
+	case []string:
+		if len(v) == 0 {
+			return anchor + " []"
+		}
+		if oldValue != nil && !isBlockSequence(oldValue) && oldValue.Kind == yaml3.SequenceNode {
+			items := make([]string, len(v))
+			for i, item := range v {
+				items[i] = flowScalar(item)
+			}
+			return anchor + " [" + strings.Join(items, ", ") + "]"
+		}
+		return anchor + blockSequence(v, "", newLine)
+	default:
+		value := fmt.Sprintf("%v", v)
+		if oldValue != nil && oldValue.Kind == yaml3.ScalarNode {
+			switch oldValue.Style {
+			case yaml3.DoubleQuotedStyle:
+				return anchor + " " + strconv.Quote(value)
+			case yaml3.SingleQuotedStyle:
+				return anchor + " '" + strings.ReplaceAll(value, "'", "''") + "'"
+			}
+		}
+		return anchor + " " + scalar(value)
+	}
+}
+
+// blockSequence returns items, each in a new line
+func blockSequence(items []string, indent, newLine string) string {
+	var block strings.Builder
+	for _, item := range items {
+		block.WriteString(newLine + indent + "- " + scalar(item))
+	}
+	return block.String()
+}
+
+// scalar returns s quoted when needed
+func scalar(s string) string {
+	bytes, err := yaml.Marshal(s)
+	if err != nil {
+		return fmt.Sprintf("%q", s)
+	}
+	return strings.TrimSuffix(string(bytes), "\n")
+}
+
+func flowScalar(s string) string {
+	quoted := scalar(s)
+	if strings.ContainsAny(s, ",[]{}") && quoted == s {
+		return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+	}
+	return quoted
+}
+
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func isBlankOrComment(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed == "" || strings.HasPrefix(trimmed, "#")
+}
+
+// byteOffset converts offset given in characters to bytes
+func byteOffset(line string, characters int) int {
+	offset := 0
+	for i := 0; i < characters && offset < len(line); i++ {
+		_, size := utf8.DecodeRuneInString(line[offset:])
+		offset += size
+	}
+	return offset
+}
@@ -4,51 +4,54 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/elgopher/noteo/date"
+	"github.com/elgopher/noteo/seq"
 	"github.com/elgopher/noteo/tag"
+	"github.com/elgopher/noteo/task"
 )
 
+// Filter is a channel adapter of FilterSeq
 func Filter(ctx context.Context, notes <-chan Note, predicates ...Predicate) (note <-chan Note, errors <-chan error) {
-	out := make(chan Note)
-	errs := make(chan error)
-	go func() {
-		defer close(out)
-		defer close(errs)
-		filterLoop(ctx, notes, predicates, out, errs)
-	}()
-	return out, errs
+	return seq.Channels(ctx, FilterSeq(seq.FromChannel(ctx, notes), predicates...))
 }
 
-func filterLoop(ctx context.Context, notes <-chan Note, predicates []Predicate, out chan<- Note, errs chan<- error) {
-	for {
-		select {
-		case note, ok := <-notes:
-			if !ok {
-				return
+// FilterSeq yields notes matching all predicates. Errors of notes and predicates are yielded in order.
+func FilterSeq(notes seq.Seq[Note], predicates ...Predicate) seq.Seq[Note] {
+	return func(yield func(Note, error) bool) {
+		notes(func(note Note, err error) bool {
+			if err != nil {
+				return yield(nil, err)
 			}
-			if noteMatches(note, predicates, errs) {
-				out <- note
+			matches, err := noteMatches(note, predicates)
+			if err == nil && matches {
+				return yield(note, nil)
 			}
-		case <-ctx.Done():
-			return
-		}
+			// body of the matching note is kept, so it does not have to be read again, e.g. when printed
+			if releaser, ok := note.(bodyReleaser); ok {
+				releaser.ReleaseBody()
+			}
+			if err != nil {
+				return yield(nil, err)
+			}
+			return true
+		})
 	}
 }
 
-func noteMatches(note Note, predicates []Predicate, errs chan<- error) bool {
+func noteMatches(note Note, predicates []Predicate) (bool, error) {
 	for _, predicate := range predicates {
 		matches, err := predicate(note)
 		if err != nil {
-			errs <- fmt.Errorf("executing predicate failed on note %s: %v", note.Path(), err)
-			return false
+			return false, fmt.Errorf("executing predicate failed on note %s: %w", note.Path(), err)
 		}
 		if !matches {
-			return false
+			return false, nil
 		}
 	}
-	return true
+	return true, nil
 }
 
 type Predicate func(note Note) (bool, error)
@@ -99,44 +102,217 @@ func TagGrep(regex *regexp.Regexp) Predicate {
 }
 
 func TagGreater(tagNameValue string) (Predicate, error) {
-	return tagNumber(tagNameValue, func(anotherNumber, number int) bool {
-		return anotherNumber > number
+	return tagCompare(tagNameValue, func(result int) bool {
+		return result > 0
 	})
 }
 
 func TagLower(tagNameValue string) (Predicate, error) {
-	return tagNumber(tagNameValue, func(anotherNumber, number int) bool {
-		return anotherNumber < number
+	return tagCompare(tagNameValue, func(result int) bool {
+		return result < 0
 	})
 }
 
-func tagNumber(tagNameValue string, f func(anotherNumber, number int) bool) (Predicate, error) {
+// tagCompare compares tag values which are numbers, floats, durations or dates
+func tagCompare(tagNameValue string, f func(result int) bool) (Predicate, error) {
 	kv, err := tag.New(tagNameValue)
 	if err != nil {
 		return nil, err
 	}
-	number, err := kv.Number()
-	if err != nil {
+	if kv, err = comparableOperand(kv); err != nil {
 		return nil, err
 	}
 	return func(note Note) (bool, error) {
-		tags, err := note.Tags()
+		another, found, err := FindTagByName(note, kv.Name())
+		if err != nil || !found {
+			return false, err
+		}
+		result, err := another.Compare(kv)
 		if err != nil {
+			return false, fmt.Errorf("error comparing tag \"%s\": %w", another, err)
+		}
+		return f(result), nil
+	}, nil
+}
+
+// comparableOperand validates that value is a number, duration or date. Relative dates, such as "today", are made
+// absolute once, so they can be compared with tag values.
+func comparableOperand(kv tag.Tag) (tag.Tag, error) {
+	value, err := kv.Value()
+	if err != nil {
+		return kv, err
+	}
+	if _, err = kv.Float(); err == nil {
+		return kv, nil
+	}
+	if _, err = kv.Duration(); err == nil {
+		return kv, nil
+	}
+	d, err := date.Parse(value)
+	if err != nil {
+		return kv, fmt.Errorf("%s is not a number, duration or date", value)
+	}
+	return kv.WithDate(d), nil
+}
+
+// TagIn matches notes having tag with one of given values, e.g. "status:open,blocked". Vocabulary can be nil.
+func TagIn(tagNameValues string, vocabulary *tag.Vocabulary) (Predicate, error) {
+	kv, err := tag.New(tagNameValues)
+	if err != nil {
+		return nil, err
+	}
+	values, err := kv.Value()
+	if err != nil {
+		return nil, err
+	}
+	var allowed []tag.Tag
+	for _, value := range strings.Split(values, ",") {
+		allowed = append(allowed, kv.WithValue(value))
+	}
+	return func(note Note) (bool, error) {
+		another, found, err := FindTagByName(note, kv.Name())
+		if err != nil || !found {
 			return false, err
 		}
-		for _, another := range tags {
-			if another.Name() == kv.Name() {
-				anotherNumber, err := another.Number()
-				if err != nil {
-					return false, fmt.Errorf("error getting number from tag \"%s\": %v", another, err)
-				}
-				return f(anotherNumber, number), nil
+		for _, t := range allowed {
+			if vocabulary.ValueEquals(another, t) {
+				return true, nil
 			}
 		}
 		return false, nil
 	}, nil
 }
 
+// TagEq matches notes having tag with equal value. Values are compared as typed values, so "priority:01" equals
+// "priority:1". Values of tags declared as bool in vocabulary are compared as booleans. Vocabulary can be nil.
+func TagEq(tagNameValue string, vocabulary *tag.Vocabulary) (Predicate, error) {
+	kv, err := tag.New(tagNameValue)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = kv.Value(); err != nil {
+		return nil, err
+	}
+	return func(note Note) (bool, error) {
+		another, found, err := FindTagByName(note, kv.Name())
+		if err != nil || !found {
+			return false, err
+		}
+		return vocabulary.ValueEquals(another, kv), nil
+	}, nil
+}
+
+// TagExists matches notes having tag with given name and any value
+func TagExists(name string) Predicate {
+	return func(note Note) (bool, error) {
+		_, found, err := FindTagByName(note, name)
+		return found, err
+	}
+}
+
+// TagRange matches notes having tag with value (number, duration or date) in inclusive range, e.g. "priority:1..3".
+// One side of the range can be omitted, e.g. "priority:2.." matches values greater or equal 2.
+func TagRange(tagNameRange string) (Predicate, error) {
+	kv, from, to, err := parseTagRange(tagNameRange)
+	if err != nil {
+		return nil, err
+	}
+	var fromTag, toTag tag.Tag
+	if from != "" {
+		if fromTag, err = comparableOperand(kv.WithValue(from)); err != nil {
+			return nil, err
+		}
+	}
+	if to != "" {
+		if toTag, err = comparableOperand(kv.WithValue(to)); err != nil {
+			return nil, err
+		}
+	}
+	return func(note Note) (bool, error) {
+		another, found, err := FindTagByName(note, kv.Name())
+		if err != nil || !found {
+			return false, err
+		}
+		if from != "" {
+			result, err := another.Compare(fromTag)
+			if err != nil {
+				return false, fmt.Errorf("error comparing tag \"%s\": %w", another, err)
+			}
+			if result < 0 {
+				return false, nil
+			}
+		}
+		if to != "" {
+			result, err := another.Compare(toTag)
+			if err != nil {
+				return false, fmt.Errorf("error comparing tag \"%s\": %w", another, err)
+			}
+			if result > 0 {
+				return false, nil
+			}
+		}
+		return true, nil
+	}, nil
+}
+
+// TagBetween matches notes having tag with date value in inclusive range. Range boundaries can be relative dates,
+// e.g. "deadline:today..tomorrow". One side of the range can be omitted.
+func TagBetween(tagNameRange string) (Predicate, error) {
+	kv, from, to, err := parseTagRange(tagNameRange)
+	if err != nil {
+		return nil, err
+	}
+	var fromDate, toDate time.Time
+	if from != "" {
+		if fromDate, err = date.Parse(from); err != nil {
+			return nil, err
+		}
+	}
+	if to != "" {
+		if toDate, err = date.Parse(to); err != nil {
+			return nil, err
+		}
+	}
+	return func(note Note) (bool, error) {
+		another, found, err := FindTagByName(note, kv.Name())
+		if err != nil || !found {
+			return false, err
+		}
+		anotherDate, err := another.AbsoluteDate()
+		if err != nil {
+			return false, fmt.Errorf("error getting date from tag \"%s\": %w", another, err)
+		}
+		if from != "" && anotherDate.Before(fromDate) {
+			return false, nil
+		}
+		if to != "" && anotherDate.After(toDate) {
+			return false, nil
+		}
+		return true, nil
+	}, nil
+}
+
+func parseTagRange(tagNameRange string) (kv tag.Tag, from, to string, err error) {
+	kv, err = tag.New(tagNameRange)
+	if err != nil {
+		return
+	}
+	value, err := kv.Value()
+	if err != nil {
+		return
+	}
+	if !strings.Contains(value, "..") {
+		err = fmt.Errorf("%s is not a range. Use name:from..to", tagNameRange)
+		return
+	}
+	from = value[:strings.Index(value, "..")]
+	to = value[strings.Index(value, "..")+2:]
+	if from == "" && to == "" {
+		err = fmt.Errorf("%s has empty range", tagNameRange)
+	}
+	return
+}
+
 func TagAfter(tagNameValue string) (Predicate, error) {
 	return tagDate(tagNameValue, func(anotherDate, date time.Time) bool {
 		return anotherDate.After(date)
@@ -167,7 +343,7 @@ func tagDate(tagNameValue string, f func(anotherDate, date time.Time) bool) (Pre
 			if another.Name() == kv.Name() {
 				anotherDate, err := another.AbsoluteDate()
 				if err != nil {
-					return false, fmt.Errorf("error getting date from tag \"%s\": %v", another, err)
+					return false, fmt.Errorf("error getting date from tag \"%s\": %w", another, err)
 				}
 				return f(anotherDate, relativeDate), nil
 			}
@@ -255,3 +431,19 @@ func Grep(expr string) (Predicate, error) {
 		return regex.MatchString(body), nil
 	}, nil
 }
+
+// HasOpenTasks matches notes having Markdown task list items which are not done, e.g. "- [ ] task"
+func HasOpenTasks() Predicate {
+	return func(note Note) (bool, error) {
+		body, err := note.Body()
+		if err != nil {
+			return false, err
+		}
+		for _, t := range task.Parse(body, 1) {
+			if !t.Done {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+}
@@ -2,10 +2,14 @@ package notes_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/elgopher/noteo/date"
 	"github.com/elgopher/noteo/notes"
+	"github.com/elgopher/noteo/seq"
+	"github.com/elgopher/noteo/tag"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -36,6 +40,29 @@ func TestFilter(t *testing.T) {
 	})
 }
 
+func TestFilterSeq(t *testing.T) {
+	expectedNote := &noteMock{path: "expected"}
+	invalidNote := &noteMock{path: "invalid"}
+	filteredOutNote := &noteMock{path: "filtered out"}
+	input := func(yield func(notes.Note, error) bool) {
+		_ = yield(invalidNote, nil) && yield(expectedNote, nil) && yield(filteredOutNote, nil)
+	}
+	predicate := func(note notes.Note) (bool, error) {
+		if note == invalidNote {
+			return false, errors.New("invalid")
+		}
+		return note == expectedNote, nil
+	}
+	// when
+	output, errs := seq.Collect(notes.FilterSeq(input, predicate))
+	// then
+	assert.Equal(t, []notes.Note{expectedNote}, output)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "invalid")
+	assert.False(t, expectedNote.released, "body of expected note should be kept")
+	assert.True(t, filteredOutNote.released, "body of filtered out note should be released")
+}
+
 func collectNotes(t *testing.T, filtered <-chan notes.Note, errors <-chan error) []notes.Note {
 	var output []notes.Note
 	for {
@@ -50,3 +77,192 @@ func collectNotes(t *testing.T, filtered <-chan notes.Note, errors <-chan error)
 		}
 	}
 }
+
+func TestTagGreater(t *testing.T) {
+	tests := map[string]struct {
+		filter   string
+		tags     []string
+		expected bool
+	}{
+		"greater number":     {filter: "priority:1", tags: []string{"priority:2"}, expected: true},
+		"lower number":       {filter: "priority:2", tags: []string{"priority:1"}, expected: false},
+		"greater float":      {filter: "ratio:0.5", tags: []string{"ratio:0.75"}, expected: true},
+		"greater duration":   {filter: "estimate:2h", tags: []string{"estimate:2h30m"}, expected: true},
+		"lower duration":     {filter: "estimate:2h", tags: []string{"estimate:90m"}, expected: false},
+		"missing tag":        {filter: "priority:1", tags: []string{"other:2"}, expected: false},
+		"equal is not great": {filter: "priority:1", tags: []string{"priority:1.0"}, expected: false},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			predicate, err := notes.TagGreater(test.filter)
+			require.NoError(t, err)
+			// when
+			matches, err := predicate(&noteMock{tags: test.tags})
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, matches)
+		})
+	}
+}
+
+func TestTagGreater_RelativeDate(t *testing.T) {
+	date.SetNow(func() time.Time {
+		return time.Date(2020, 9, 5, 10, 0, 0, 0, time.Local)
+	})
+	defer date.SetNow(time.Now)
+	predicate, err := notes.TagGreater("deadline:today")
+	require.NoError(t, err)
+	// when
+	matches, err := predicate(&noteMock{tags: []string{"deadline:2020-09-06"}})
+	// then
+	require.NoError(t, err)
+	assert.True(t, matches)
+}
+
+func TestTagLower(t *testing.T) {
+	t.Run("should return error for value which is not comparable", func(t *testing.T) {
+		// when
+		_, err := notes.TagLower("priority:high")
+		// then
+		assert.Error(t, err)
+	})
+
+	t.Run("should return error for tag without value", func(t *testing.T) {
+		// when
+		_, err := notes.TagLower("priority")
+		// then
+		assert.Error(t, err)
+	})
+}
+
+func TestTagIn(t *testing.T) {
+	predicate, err := notes.TagIn("status:open,blocked", nil)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		tags     []string
+		expected bool
+	}{
+		"first value":  {tags: []string{"status:open"}, expected: true},
+		"second value": {tags: []string{"status:blocked"}, expected: true},
+		"other value":  {tags: []string{"status:done"}, expected: false},
+		"no value":     {tags: []string{"status"}, expected: false},
+		"no tag":       {tags: []string{"idea"}, expected: false},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			matches, err := predicate(&noteMock{tags: test.tags})
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, matches)
+		})
+	}
+}
+
+func TestTagEq(t *testing.T) {
+	tests := map[string]struct {
+		filter   string
+		tags     []string
+		expected bool
+	}{
+		"same string":        {filter: "status:open", tags: []string{"status:open"}, expected: true},
+		"different string":   {filter: "status:open", tags: []string{"status:done"}, expected: false},
+		"leading zero":       {filter: "priority:1", tags: []string{"priority:01"}, expected: true},
+		"float and int":      {filter: "priority:1", tags: []string{"priority:1.0"}, expected: true},
+		"duration":           {filter: "estimate:90m", tags: []string{"estimate:1h30m"}, expected: true},
+		"boolean":            {filter: "done:yes", tags: []string{"done:true"}, expected: true},
+		"number and boolean": {filter: "priority:1", tags: []string{"priority:yes"}, expected: false},
+		"date in other zone": {filter: "d:2020-10-15T00:00:00Z", tags: []string{"d:2020-10-15T02:00:00+02:00"}, expected: true},
+		"missing tag":        {filter: "priority:1", tags: []string{"other:1"}, expected: false},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			predicate, err := notes.TagEq(test.filter, nil)
+			require.NoError(t, err)
+			// when
+			matches, err := predicate(&noteMock{tags: test.tags})
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, matches)
+		})
+	}
+}
+
+func TestTagEq_Vocabulary(t *testing.T) {
+	vocabulary := &tag.Vocabulary{Names: map[string]tag.ValueType{"done": tag.BoolValue}}
+	predicate, err := notes.TagEq("done:1", vocabulary)
+	require.NoError(t, err)
+	// when
+	matches, err := predicate(&noteMock{tags: []string{"done:yes"}})
+	// then
+	require.NoError(t, err)
+	assert.True(t, matches)
+}
+
+func TestTagRange(t *testing.T) {
+	tests := map[string]struct {
+		filter   string
+		tags     []string
+		expected bool
+	}{
+		"lower bound":      {filter: "priority:1..3", tags: []string{"priority:1"}, expected: true},
+		"upper bound":      {filter: "priority:1..3", tags: []string{"priority:3"}, expected: true},
+		"inside":           {filter: "priority:1..3", tags: []string{"priority:2.5"}, expected: true},
+		"below":            {filter: "priority:1..3", tags: []string{"priority:0"}, expected: false},
+		"above":            {filter: "priority:1..3", tags: []string{"priority:4"}, expected: false},
+		"open upper bound": {filter: "priority:2..", tags: []string{"priority:100"}, expected: true},
+		"open lower bound": {filter: "priority:..2", tags: []string{"priority:-1"}, expected: true},
+		"durations":        {filter: "estimate:1h..2h", tags: []string{"estimate:90m"}, expected: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			predicate, err := notes.TagRange(test.filter)
+			require.NoError(t, err)
+			// when
+			matches, err := predicate(&noteMock{tags: test.tags})
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, matches)
+		})
+	}
+
+	t.Run("should return error for invalid range", func(t *testing.T) {
+		for _, filter := range []string{"priority", "priority:1", "priority:..", "priority:low..3", "priority:1..high"} {
+			t.Run(filter, func(t *testing.T) {
+				_, err := notes.TagRange(filter)
+				assert.Error(t, err)
+			})
+		}
+	})
+}
+
+func TestTagBetween(t *testing.T) {
+	predicate, err := notes.TagBetween("deadline:2020-10-01..2020-10-31")
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		tags     []string
+		expected bool
+	}{
+		"first day": {tags: []string{"deadline:2020-10-01"}, expected: true},
+		"last day":  {tags: []string{"deadline:2020-10-31"}, expected: true},
+		"before":    {tags: []string{"deadline:2020-09-30"}, expected: false},
+		"after":     {tags: []string{"deadline:2020-11-01"}, expected: false},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			matches, err := predicate(&noteMock{tags: test.tags})
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, matches)
+		})
+	}
+}
+
+func TestTagExists(t *testing.T) {
+	predicate := notes.TagExists("deadline")
+	matches, err := predicate(&noteMock{tags: []string{"deadline:2020-10-01"}})
+	require.NoError(t, err)
+	assert.True(t, matches)
+	matches, err = predicate(&noteMock{tags: []string{"idea"}})
+	require.NoError(t, err)
+	assert.False(t, matches)
+}
@@ -14,6 +14,11 @@ type Note interface {
 	Body() (string, error)
 }
 
+// bodyReleaser is implemented by notes which can drop cached body, e.g. when the note did not match predicates
+type bodyReleaser interface {
+	ReleaseBody()
+}
+
 func FindTagByName(note Note, name string) (tag.Tag, bool, error) {
 	tags, err := note.Tags()
 	if err != nil {
@@ -5,50 +5,59 @@ import (
 	"fmt"
 	"sort"
 	"time"
+
+	"github.com/elgopher/noteo/seq"
 )
 
+// Top is a channel adapter of TopSeq
 func Top(ctx context.Context, limit int, notes <-chan Note, less Less) (note <-chan Note, errors <-chan error) {
-	out := make(chan Note)
-	errs := make(chan error)
-
-	go func() {
-		defer close(out)
-		defer close(errs)
-		slice := collectNotes(ctx, notes)
-		sortNotes(slice, less, errs)
+	return seq.Channels(ctx, TopSeq(limit, seq.FromChannel(ctx, notes), less))
+}
+
+// TopSeq yields at most limit notes sorted using less. All notes are read before the first note is yielded,
+// so errors are yielded first.
+func TopSeq(limit int, notes seq.Seq[Note], less Less) seq.Seq[Note] {
+	return func(yield func(Note, error) bool) {
+		var slice []Note
+		stopped := false
+		notes(func(note Note, err error) bool {
+			if err != nil {
+				stopped = !yield(nil, err)
+				return !stopped
+			}
+			slice = append(slice, note)
+			return true
+		})
+		if stopped {
+			return
+		}
+		for _, err := range Sort(slice, less) {
+			if !yield(nil, err) {
+				return
+			}
+		}
 		if len(slice) > limit {
 			slice = slice[:limit]
 		}
 		for _, note := range slice {
-			out <- note
-		}
-	}()
-	return out, errs
-}
-
-func collectNotes(ctx context.Context, notes <-chan Note) []Note {
-	var collectedNotes []Note
-	for {
-		select {
-		case note, ok := <-notes:
-			if !ok {
-				return collectedNotes
+			if !yield(note, nil) {
+				return
 			}
-			collectedNotes = append(collectedNotes, note)
-		case <-ctx.Done():
-			return nil
 		}
 	}
 }
 
-func sortNotes(slice []Note, less Less, errs chan<- error) {
-	sort.Slice(slice, func(i, j int) bool {
+// Sort sorts notes using less and returns errors returned by less. Order of equal notes is preserved.
+func Sort(slice []Note, less Less) []error {
+	var errs []error
+	sort.SliceStable(slice, func(i, j int) bool {
 		l, err := less(slice[i], slice[j])
 		if err != nil {
-			errs <- fmt.Errorf("comparing notes failed %s and %s: %v", slice[i].Path(), slice[j].Path(), err)
+			errs = append(errs, fmt.Errorf("comparing notes failed %s and %s: %w", slice[i].Path(), slice[j].Path(), err))
 		}
 		return l
 	})
+	return errs
 }
 
 type Less func(i, j Note) (bool, error)
@@ -135,19 +144,21 @@ func tagDateLess(name string, less func(first, second time.Time) bool) Less {
 	}
 }
 
+// TagNumberDesc sorts by tag value which can be a number, float, duration or date
 func TagNumberDesc(name string) Less {
-	return tagNumberLess(name, func(first, second int) bool {
-		return first > second
+	return tagValueLess(name, func(result int) bool {
+		return result > 0
 	})
 }
 
+// TagNumberAsc sorts by tag value which can be a number, float, duration or date
 func TagNumberAsc(name string) Less {
-	return tagNumberLess(name, func(first, second int) bool {
-		return first < second
+	return tagValueLess(name, func(result int) bool {
+		return result < 0
 	})
 }
 
-func tagNumberLess(name string, less func(first, second int) bool) Less {
+func tagValueLess(name string, less func(result int) bool) Less {
 	return func(first, second Note) (bool, error) {
 		firstTag, found, err := FindTagByName(first, name)
 		if err != nil || !found {
@@ -157,14 +168,10 @@ func tagNumberLess(name string, less func(first, second int) bool) Less {
 		if err != nil || !found {
 			return true, err
 		}
-		firstNumber, err := firstTag.Number()
-		if err != nil {
-			return false, err
-		}
-		secondNumber, err := secondTag.Number()
+		result, err := firstTag.Compare(secondTag)
 		if err != nil {
 			return false, err
 		}
-		return less(firstNumber, secondNumber), nil
+		return less(result), nil
 	}
 }
@@ -2,6 +2,7 @@ package notes_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -59,6 +60,30 @@ func TestTop(t *testing.T) {
 	})
 }
 
+func TestTopSeq(t *testing.T) {
+	note2019 := &noteMock{modified: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)}
+	note2020 := &noteMock{modified: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	givenError := errors.New("given")
+	input := func(yield func(notes.Note, error) bool) {
+		_ = yield(note2020, nil) && yield(nil, givenError) && yield(note2019, nil)
+	}
+	var output []notes.Note
+	var errs []error
+	// when
+	notes.TopSeq(10, input, sortByModified)(func(note notes.Note, err error) bool {
+		if err != nil {
+			errs = append(errs, err)
+			assert.Empty(t, output, "errors should be yielded before notes")
+		} else {
+			output = append(output, note)
+		}
+		return true
+	})
+	// then
+	assert.Equal(t, []notes.Note{note2019, note2020}, output)
+	assert.Equal(t, []error{givenError}, errs)
+}
+
 func sortByModified(i, j notes.Note) (bool, error) {
 	iModified, _ := i.Modified()
 	jModified, _ := j.Modified()
@@ -142,6 +167,7 @@ type noteMock struct {
 	tags       []string
 	stringTags []string
 	text       string
+	released   bool
 }
 
 func (n *noteMock) Modified() (time.Time, error) {
@@ -174,3 +200,7 @@ func (n *noteMock) StringTags() ([]string, error) {
 func (n *noteMock) Body() (string, error) {
 	return n.text, nil
 }
+
+func (n *noteMock) ReleaseBody() {
+	n.released = true
+}
@@ -4,11 +4,17 @@ import (
 	"encoding/json"
 	"time"
 
+	"github.com/elgopher/noteo/date"
 	"github.com/elgopher/noteo/notes"
 	"github.com/elgopher/noteo/output"
 )
 
-type Formatter struct{}
+type Formatter struct {
+	// DateFormat is optional. When empty, dates are marshalled as time.Time
+	DateFormat date.Format
+	// Location is optional. When nil, dates are not converted
+	Location *time.Location
+}
 
 func (f Formatter) Header() string {
 	return ""
@@ -19,40 +25,50 @@ func (f Formatter) Footer() string {
 }
 
 func (f Formatter) Note(note notes.Note) string {
-	body, err := note.Body()
+	n, err := f.Convert(note)
 	if err != nil {
 		return err.Error()
 	}
+	bytes, err := json.Marshal(n)
+	if err != nil {
+		return "error marshalling note: " + err.Error()
+	}
+	return string(bytes) + "\n"
+}
+
+// Convert returns JSON representation of the note
+func (f Formatter) Convert(note notes.Note) (*Note, error) {
+	body, err := note.Body()
+	if err != nil {
+		return nil, err
+	}
 	created, err := note.Created()
 	if err != nil {
-		return err.Error()
+		return nil, err
 	}
 	modified, err := note.Modified()
 	if err != nil {
-		return err.Error()
+		return nil, err
 	}
 	tags, err := output.StringTags(note)
 	if err != nil {
-		return err.Error()
+		return nil, err
 	}
-	n := noteToMarshal{
+	return &Note{
+		Repo:     output.Repo(note),
 		File:     note.Path(),
-		Modified: modified,
-		Created:  created,
+		Modified: output.Date(modified, f.DateFormat, f.Location),
+		Created:  output.Date(created, f.DateFormat, f.Location),
 		Text:     body,
 		Tags:     tags,
-	}
-	bytes, err := json.Marshal(n)
-	if err != nil {
-		return "error marshalling note: " + err.Error()
-	}
-	return string(bytes) + "\n"
+	}, nil
 }
 
-type noteToMarshal struct {
-	File     string    `json:"file"`
-	Modified time.Time `json:"modified"`
-	Created  time.Time `json:"created"`
-	Tags     []string  `json:"tags"`
-	Text     string    `json:"text"`
+type Note struct {
+	Repo     string      `json:"repo,omitempty"`
+	File     string      `json:"file"`
+	Modified interface{} `json:"modified"`
+	Created  interface{} `json:"created"`
+	Tags     []string    `json:"tags"`
+	Text     string      `json:"text"`
 }
@@ -0,0 +1,59 @@
+// Package jsonpath formats notes using JSONPath template evaluated on JSON representation of each note,
+// e.g. {.file}{"\t"}{.tags[0]}
+package jsonpath
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/elgopher/noteo/date"
+	"github.com/elgopher/noteo/notes"
+	"github.com/elgopher/noteo/output"
+	"github.com/elgopher/noteo/output/jayson"
+)
+
+type Formatter struct {
+	jayson   jayson.Formatter
+	template []node
+}
+
+// New parses the template. DateFormat and location are optional, like in jayson.Formatter.
+func New(template string, dateFormat date.Format, location *time.Location) (*Formatter, error) {
+	nodes, err := parse(template)
+	if err != nil {
+		return nil, err
+	}
+	return &Formatter{
+		jayson:   jayson.Formatter{DateFormat: dateFormat, Location: location},
+		template: nodes,
+	}, nil
+}
+
+func (f *Formatter) Header() string {
+	return ""
+}
+
+func (f *Formatter) Footer() string {
+	return ""
+}
+
+func (f *Formatter) Note(note notes.Note) string {
+	n, err := f.jayson.Convert(note)
+	if err != nil {
+		return err.Error() + "\n"
+	}
+	bytes, err := json.Marshal(n)
+	if err != nil {
+		return "error marshalling note: " + err.Error() + "\n"
+	}
+	var data interface{}
+	if err = json.Unmarshal(bytes, &data); err != nil {
+		return "error unmarshalling note: " + err.Error() + "\n"
+	}
+	var out strings.Builder
+	if err = execute(f.template, data, data, &out); err != nil {
+		return err.Error() + "\n"
+	}
+	return output.Line(out.String())
+}
@@ -0,0 +1,68 @@
+package jsonpath_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/noteo/note"
+	"github.com/elgopher/noteo/output/jsonpath"
+)
+
+func TestFormatter_Note(t *testing.T) {
+	n := newNote(t, "---\nCreated: 2020-09-05T12:30:00Z\nTags: priority:2 work\n---\nText")
+	tests := map[string]struct {
+		template string
+		expected string
+	}{
+		"field":               {template: "{.created}", expected: "2020-09-05T12:30:00Z\n"},
+		"index":               {template: "{.tags[0]}", expected: "priority:2\n"},
+		"negative index":      {template: "{.tags[-1]}", expected: "work\n"},
+		"bracket field":       {template: "{['text']}", expected: "Text\n"},
+		"all items":           {template: "{.tags[*]}", expected: "priority:2 work\n"},
+		"list as json":        {template: "{.tags}", expected: `["priority:2","work"]` + "\n"},
+		"string literal":      {template: `{.tags[0]}{"\t"}{.text}`, expected: "priority:2\tText\n"},
+		"text outside braces": {template: "tags: {.tags[1]}", expected: "tags: work\n"},
+		"range":               {template: "{range .tags[*]}[{.}]{end}", expected: "[priority:2][work]\n"},
+		"root in range":       {template: "{range .tags[*]}{$.text}{end}", expected: "TextText\n"},
+		"missing field":       {template: "{.repo}", expected: ""},
+		"index out of range":  {template: "{.tags[5]}", expected: ""},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			f, err := jsonpath.New(test.template, "", nil)
+			require.NoError(t, err)
+			// when
+			out := f.Note(n)
+			// then
+			assert.Equal(t, test.expected, out)
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := map[string]string{
+		"unclosed expression": "{.file",
+		"missing end":         "{range .tags[*]}{.}",
+		"unexpected end":      "{.file}{end}",
+		"invalid path":        "{file}",
+		"invalid index":       "{.tags[x]}",
+	}
+	for name, template := range tests {
+		t.Run(name, func(t *testing.T) {
+			// when
+			_, err := jsonpath.New(template, "", nil)
+			// then
+			assert.Error(t, err)
+		})
+	}
+}
+
+func newNote(t *testing.T, content string) *note.Note {
+	file := filepath.Join(t.TempDir(), "note.md")
+	require.NoError(t, os.WriteFile(file, []byte(content), 0664))
+	return note.New(file)
+}
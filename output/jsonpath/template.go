@@ -0,0 +1,268 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// node is a part of the template: literal text, path or range
+type node interface{}
+
+type text string
+
+// path selects values. Steps are applied to the current value, or to the root value when path starts with $.
+type path struct {
+	fromRoot bool
+	steps    []step
+}
+
+// step is a field name, * (all items) or an index, which can be negative to count from the end
+type step struct {
+	field    string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// rangeNode executes the body for each value selected by path
+type rangeNode struct {
+	path path
+	body []node
+}
+
+// parse parses template with expressions in braces, such as {.tags[*]}, {"\t"} or {range .tags[*]}{.}{"\n"}{end}.
+// Text outside braces is printed as is.
+func parse(template string) ([]node, error) {
+	nodes, rest, err := parseNodes(template, false)
+	if err != nil {
+		return nil, err
+	}
+	if rest != "" {
+		return nil, errors.New("unexpected {end}")
+	}
+	return nodes, nil
+}
+
+// parseNodes parses nodes up to {end} when inRange is true, returning the rest of the template after {end}
+func parseNodes(template string, inRange bool) (nodes []node, rest string, err error) {
+	for template != "" {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			nodes = append(nodes, text(template))
+			break
+		}
+		if start > 0 {
+			nodes = append(nodes, text(template[:start]))
+		}
+		end := closingBrace(template, start)
+		if end < 0 {
+			return nil, "", fmt.Errorf("unclosed expression: %s", template[start:])
+		}
+		expression := strings.TrimSpace(template[start+1 : end])
+		template = template[end+1:]
+		switch {
+		case expression == "end":
+			if !inRange {
+				return nodes, "{end}" + template, nil
+			}
+			return nodes, template, nil
+		case strings.HasPrefix(expression, `"`):
+			s, err := strconv.Unquote(expression)
+			if err != nil {
+				return nil, "", fmt.Errorf("invalid string %s: %v", expression, err)
+			}
+			nodes = append(nodes, text(s))
+		case strings.HasPrefix(expression, "range "):
+			p, err := parsePath(strings.TrimSpace(strings.TrimPrefix(expression, "range ")))
+			if err != nil {
+				return nil, "", err
+			}
+			var body []node
+			body, template, err = parseNodes(template, true)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, rangeNode{path: p, body: body})
+		default:
+			p, err := parsePath(expression)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, p)
+		}
+	}
+	if inRange {
+		return nil, "", errors.New("missing {end} of {range}")
+	}
+	return nodes, "", nil
+}
+
+// closingBrace returns index of the brace closing the one at start, skipping braces in quoted strings
+func closingBrace(template string, start int) int {
+	var quote byte
+	for i := start + 1; i < len(template); i++ {
+		c := template[i]
+		switch {
+		case quote != 0 && c == '\\':
+			i++
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '}':
+			return i
+		}
+	}
+	return -1
+}
+
+func parsePath(expression string) (path, error) {
+	p := path{}
+	s := expression
+	switch {
+	case strings.HasPrefix(s, "$"):
+		p.fromRoot = true
+		s = s[1:]
+	case strings.HasPrefix(s, "."), strings.HasPrefix(s, "["):
+	default:
+		return p, fmt.Errorf("invalid expression %s: path must start with ., [ or $", expression)
+	}
+	for s != "" {
+		switch {
+		case s == ".":
+			s = ""
+		case s[0] == '.':
+			end := strings.IndexAny(s[1:], ".[")
+			if end < 0 {
+				end = len(s) - 1
+			}
+			name := s[1 : end+1]
+			if name == "" {
+				return p, fmt.Errorf("invalid expression %s: empty field name", expression)
+			}
+			p.steps = append(p.steps, step{field: name, wildcard: name == "*"})
+			s = s[end+1:]
+		case s[0] == '[':
+			end := strings.IndexByte(s, ']')
+			if end < 0 {
+				return p, fmt.Errorf("invalid expression %s: missing ]", expression)
+			}
+			st, err := parseBracket(s[1:end])
+			if err != nil {
+				return p, fmt.Errorf("invalid expression %s: %v", expression, err)
+			}
+			p.steps = append(p.steps, st)
+			s = s[end+1:]
+		default:
+			return p, fmt.Errorf("invalid expression %s: unexpected %s", expression, s)
+		}
+	}
+	return p, nil
+}
+
+// parseBracket parses *, index or quoted field name given in brackets
+func parseBracket(s string) (step, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case s == "*":
+		return step{wildcard: true}, nil
+	case len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0]:
+		return step{field: s[1 : len(s)-1]}, nil
+	}
+	index, err := strconv.Atoi(s)
+	if err != nil {
+		return step{}, fmt.Errorf("unsupported index %s", s)
+	}
+	return step{index: index, isIndex: true}, nil
+}
+
+func execute(nodes []node, current, root interface{}, out *strings.Builder) error {
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case text:
+			out.WriteString(string(n))
+		case path:
+			values := n.eval(current, root)
+			for i, v := range values {
+				if i > 0 {
+					out.WriteString(" ")
+				}
+				s, err := printValue(v)
+				if err != nil {
+					return err
+				}
+				out.WriteString(s)
+			}
+		case rangeNode:
+			for _, v := range n.path.eval(current, root) {
+				if err := execute(n.body, v, root, out); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// eval returns selected values. Missing fields and indexes out of range select nothing.
+func (p path) eval(current, root interface{}) []interface{} {
+	values := []interface{}{current}
+	if p.fromRoot {
+		values = []interface{}{root}
+	}
+	for _, s := range p.steps {
+		var selected []interface{}
+		for _, v := range values {
+			selected = append(selected, s.eval(v)...)
+		}
+		values = selected
+	}
+	return values
+}
+
+func (s step) eval(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if s.wildcard {
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			values := make([]interface{}, len(keys))
+			for i, k := range keys {
+				values[i] = v[k]
+			}
+			return values
+		}
+		if field, ok := v[s.field]; ok && !s.isIndex {
+			return []interface{}{field}
+		}
+	case []interface{}:
+		if s.wildcard {
+			return v
+		}
+		index := s.index
+		if index < 0 {
+			index += len(v)
+		}
+		if s.isIndex && index >= 0 && index < len(v) {
+			return []interface{}{v[index]}
+		}
+	}
+	return nil
+}
+
+// printValue returns strings as they are and other values as JSON
+func printValue(value interface{}) (string, error) {
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	bytes, err := json.Marshal(value)
+	return string(bytes), err
+}
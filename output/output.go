@@ -1,6 +1,77 @@
 package output
 
-import "github.com/elgopher/noteo/notes"
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/elgopher/noteo/date"
+	"github.com/elgopher/noteo/notes"
+)
+
+// Repo returns name of the repository of the note. It is empty unless notes of many repositories are listed.
+func Repo(note notes.Note) string {
+	if n, ok := note.(interface{ Repo() string }); ok {
+		return n.Repo()
+	}
+	return ""
+}
+
+// Field returns value of the front matter key. It is not found when the note does not provide front matter fields.
+func Field(note notes.Note, key string) (value interface{}, found bool, err error) {
+	if n, ok := note.(interface {
+		Field(string) (interface{}, bool, error)
+	}); ok {
+		return n.Field(key)
+	}
+	return nil, false, nil
+}
+
+// Title returns Title field of the front matter or the first Markdown heading of the body
+func Title(note notes.Note) (string, error) {
+	title, found, err := Field(note, "Title")
+	if err != nil {
+		return "", err
+	}
+	if found && title != nil {
+		return fmt.Sprint(title), nil
+	}
+	body, err := note.Body()
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if heading := strings.TrimLeft(line, "#"); heading != line && (heading == "" || heading[0] == ' ') {
+			return strings.TrimSpace(heading), nil
+		}
+	}
+	return "", nil
+}
+
+// Size returns size of the note file in bytes, or -1 when it is unknown
+func Size(note notes.Note) (int64, error) {
+	if n, ok := note.(interface{ Size() (int64, error) }); ok {
+		return n.Size()
+	}
+	return -1, nil
+}
+
+// Links returns paths of local files linked from the note
+func Links(note notes.Note) ([]string, error) {
+	if n, ok := note.(interface{ Links() ([]string, error) }); ok {
+		return n.Links()
+	}
+	return nil, nil
+}
+
+// Backlinks returns number of notes linking to the note. It is -1 unless backlinks were counted before listing.
+func Backlinks(note notes.Note) int {
+	if n, ok := note.(interface{ Backlinks() int }); ok {
+		return n.Backlinks()
+	}
+	return -1
+}
 
 func StringTags(note notes.Note) ([]string, error) {
 	var ret []string
@@ -13,3 +84,52 @@ func StringTags(note notes.Note) ([]string, error) {
 	}
 	return ret, nil
 }
+
+// Date returns time in given location, or its string representation if format is given. Used by formatters
+// marshalling notes, which by default use time.Time representation of the serialization library.
+func Date(t time.Time, format date.Format, location *time.Location) interface{} {
+	if location != nil {
+		t = t.In(location)
+	}
+	if format == "" {
+		return t
+	}
+	return date.FormatWithType(t, format)
+}
+
+// Beginning returns the first line of text without Markdown heading or list markers
+func Beginning(text string) string {
+	t := strings.Trim(text, "\n")
+	if strings.Contains(t, "\n") {
+		t = t[:strings.IndexRune(t, '\n')]
+	}
+	t = strings.ReplaceAll(t, "\t", " ")
+	for i := 0; i < 5; i++ {
+		t = strings.TrimPrefix(t, "#")
+	}
+	t = strings.TrimPrefix(t, "*")
+	t = strings.ReplaceAll(t, "\r", "")
+	t = strings.Trim(t, " ")
+	return t
+}
+
+// Excerpt returns at most limit runes of text. Truncated text ends with an ellipsis.
+func Excerpt(text string, limit int) string {
+	runes := []rune(text)
+	switch {
+	case len(runes) <= limit:
+		return text
+	case limit < 1:
+		return ""
+	default:
+		return string(runes[:limit-1]) + "…"
+	}
+}
+
+// Line ends non-empty text with a new line. Used by formatters printing user-defined text for each note.
+func Line(text string) string {
+	if text == "" || strings.HasSuffix(text, "\n") {
+		return text
+	}
+	return text + "\n"
+}
@@ -3,7 +3,10 @@ package table
 import (
 	"bytes"
 	"fmt"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/juju/ansiterm"
 	"golang.org/x/term"
@@ -14,26 +17,47 @@ import (
 )
 
 var mapping = map[string]column{
+	"REPO":      repoColumn{},
 	"FILE":      fileColumn{},
+	"DIR":       dirColumn{},
+	"TITLE":     titleColumn{},
 	"BEGINNING": beginningColumn{},
 	"MODIFIED":  modifiedColumn{},
 	"CREATED":   createdColumn{},
 	"TAGS":      tagsColumn{},
+	"SIZE":      sizeColumn{},
+	"WORDS":     wordsColumn{},
+	"LINKS":     linksColumn{},
+	"BACKLINKS": backlinksColumn{},
 }
 
-func NewFormatter(columns []string, dateFormat date.Format) (*Formatter, error) {
+// mappingWithName has columns which require a name, e.g. tag:deadline
+var mappingWithName = map[string]func(name string) column{
+	"TAG":   func(name string) column { return tagColumn{name: name} },
+	"FIELD": func(name string) column { return fieldColumn{key: name} },
+}
+
+// defaultWidths limits columns with long text. Other columns are not limited by default.
+var defaultWidths = map[string]int{
+	"TITLE":     34,
+	"BEGINNING": 34,
+}
+
+// NewFormatter returns formatter of given columns. Column can be followed by its width, e.g. beginning:60, which
+// limits the text. Columns tag and field require a name, e.g. tag:deadline or field:author:20.
+func NewFormatter(columns []string, dateFormat date.Format, location *time.Location) (*Formatter, error) {
 	w, h, err := term.GetSize(0)
 	if err != nil {
 		w = 80
 		h = 25
 	}
-	var cols []string
+	var cols []tableColumn
 	for _, c := range columns {
-		c = strings.ToUpper(c)
-		if _, ok := mapping[c]; !ok {
-			return nil, fmt.Errorf("unsupported output column: %s", c)
+		col, err := parseColumn(c)
+		if err != nil {
+			return nil, err
 		}
-		cols = append(cols, c)
+		cols = append(cols, col)
 	}
 	buffer := bytes.NewBuffer([]byte{})
 	writer := ansiterm.NewTabWriter(buffer, 0, 8, 1, '\t', 0)
@@ -41,6 +65,7 @@ func NewFormatter(columns []string, dateFormat date.Format) (*Formatter, error)
 	return &Formatter{
 			columns:    cols,
 			dateFormat: dateFormat,
+			location:   location,
 			width:      w,
 			height:     h,
 			buffer:     buffer,
@@ -49,9 +74,43 @@ func NewFormatter(columns []string, dateFormat date.Format) (*Formatter, error)
 		nil
 }
 
+type tableColumn struct {
+	column column
+	width  int // 0 means no limit
+}
+
+func parseColumn(spec string) (tableColumn, error) {
+	parts := strings.Split(strings.TrimSpace(spec), ":")
+	name := strings.ToUpper(parts[0])
+	c := tableColumn{width: defaultWidths[name]}
+	if newColumn, ok := mappingWithName[name]; ok {
+		if len(parts) < 2 || parts[1] == "" {
+			return c, fmt.Errorf("output column %s requires a name, e.g. %s:deadline", name, strings.ToLower(name))
+		}
+		c.column = newColumn(parts[1])
+		parts = parts[2:]
+	} else if c.column, ok = mapping[name]; ok {
+		parts = parts[1:]
+	} else {
+		return c, fmt.Errorf("unsupported output column: %s", name)
+	}
+	if len(parts) > 1 {
+		return c, fmt.Errorf("unsupported output column: %s", spec)
+	}
+	if len(parts) == 1 {
+		width, err := strconv.Atoi(parts[0])
+		if err != nil || width < 1 {
+			return c, fmt.Errorf("invalid width of output column %s: %s", name, parts[0])
+		}
+		c.width = width
+	}
+	return c, nil
+}
+
 type Formatter struct {
-	columns    []string
+	columns    []tableColumn
 	dateFormat date.Format
+	location   *time.Location
 	width      int
 	height     int
 	line       int
@@ -59,6 +118,11 @@ type Formatter struct {
 	buffer     *bytes.Buffer
 }
 
+// SetColorCapable enables or disables colors, which are enabled by default
+func (o *Formatter) SetColorCapable(capable bool) {
+	o.writer.SetColorCapable(capable)
+}
+
 func (o *Formatter) flush() string {
 	_ = o.writer.Flush()
 	out := o.buffer.String()
@@ -70,15 +134,17 @@ func (o *Formatter) flush() string {
 func (o *Formatter) Header() string {
 	o.line++
 	for _, c := range o.columns {
-		options := opts{dateFormat: o.dateFormat}
-		column := mapping[c]
-		column.printHeader(options, o.writer)
+		c.column.printHeader(o.opts(c), o.writer)
 		_, _ = o.writer.Write([]byte("\t"))
 	}
 	_, _ = o.writer.Write([]byte("\n"))
 	return ""
 }
 
+func (o *Formatter) opts(c tableColumn) opts {
+	return opts{dateFormat: o.dateFormat, location: o.location, width: c.width}
+}
+
 func (o *Formatter) Footer() string {
 	return o.flush()
 }
@@ -90,9 +156,7 @@ func (o *Formatter) Note(note notes.Note) string {
 	}
 	o.line++
 	for _, c := range o.columns {
-		options := opts{dateFormat: o.dateFormat}
-		column := mapping[c]
-		column.printValue(note, options, o.writer)
+		c.column.printValue(note, o.opts(c), o.writer)
 		_, _ = o.writer.Write([]byte("\t"))
 	}
 	_, _ = o.writer.Write([]byte("\n"))
@@ -108,21 +172,6 @@ func format(text string, limit int) string {
 	return fmt.Sprintf("%-*s", limit, string(runes))
 }
 
-func beginning(text string) string {
-	t := strings.Trim(text, "\n")
-	if strings.Contains(t, "\n") {
-		t = t[:strings.IndexRune(t, '\n')]
-	}
-	t = strings.ReplaceAll(t, "\t", " ")
-	for i := 0; i < 5; i++ {
-		t = strings.TrimPrefix(t, "#")
-	}
-	t = strings.TrimPrefix(t, "*")
-	t = strings.ReplaceAll(t, "\r", "")
-	t = strings.Trim(t, " ")
-	return t
-}
-
 type column interface {
 	printHeader(opts opts, writer *ansiterm.TabWriter)
 	printValue(note notes.Note, opts opts, writer *ansiterm.TabWriter)
@@ -130,36 +179,90 @@ type column interface {
 
 type opts struct {
 	dateFormat date.Format
+	location   *time.Location
+	width      int
+}
+
+// fit limits text to the width of the column
+func (o opts) fit(text string) string {
+	if o.width == 0 {
+		return text
+	}
+	return format(text, o.width)
+}
+
+func (o opts) formatDate(t time.Time) string {
+	if o.location != nil {
+		t = t.In(o.location)
+	}
+	return date.FormatWithType(t, o.dateFormat)
+}
+
+type repoColumn struct{}
+
+func (r repoColumn) printHeader(opts opts, writer *ansiterm.TabWriter) {
+	_, _ = fmt.Fprint(writer, opts.fit("REPO"))
+}
+
+func (r repoColumn) printValue(note notes.Note, opts opts, writer *ansiterm.TabWriter) {
+	_, _ = fmt.Fprint(writer, opts.fit(output.Repo(note)))
 }
 
 type fileColumn struct{}
 
 func (f fileColumn) printHeader(opts opts, writer *ansiterm.TabWriter) {
-	_, _ = writer.Write([]byte("FILE"))
+	_, _ = fmt.Fprint(writer, opts.fit("FILE"))
 }
 
 func (f fileColumn) printValue(note notes.Note, opts opts, writer *ansiterm.TabWriter) {
 	writer.SetForeground(ansiterm.BrightBlue)
 	defer writer.Reset()
-	_, _ = fmt.Fprint(writer, note.Path())
+	_, _ = fmt.Fprint(writer, opts.fit(note.Path()))
+}
+
+type dirColumn struct{}
+
+func (d dirColumn) printHeader(opts opts, writer *ansiterm.TabWriter) {
+	_, _ = fmt.Fprint(writer, opts.fit("DIR"))
+}
+
+func (d dirColumn) printValue(note notes.Note, opts opts, writer *ansiterm.TabWriter) {
+	_, _ = fmt.Fprint(writer, opts.fit(filepath.Dir(note.Path())))
+}
+
+type titleColumn struct{}
+
+func (t titleColumn) printHeader(opts opts, writer *ansiterm.TabWriter) {
+	_, _ = fmt.Fprint(writer, opts.fit("TITLE"))
+}
+
+func (t titleColumn) printValue(note notes.Note, opts opts, writer *ansiterm.TabWriter) {
+	title, err := output.Title(note)
+	if err != nil {
+		writeError(err, writer)
+		return
+	}
+	writer.SetStyle(ansiterm.Bold)
+	defer writer.Reset()
+	_, _ = fmt.Fprint(writer, opts.fit(title))
 }
 
 type beginningColumn struct{}
 
 func (s beginningColumn) printHeader(opts opts, writer *ansiterm.TabWriter) {
-	_, _ = fmt.Fprint(writer, format("BEGINNING", 34))
+	_, _ = fmt.Fprint(writer, opts.fit("BEGINNING"))
 }
 func (s beginningColumn) printValue(note notes.Note, opts opts, writer *ansiterm.TabWriter) {
 	body, _ := note.Body()
 	writer.SetStyle(ansiterm.Bold)
 	defer writer.Reset()
-	_, _ = fmt.Fprint(writer, format(beginning(body), 34))
+	_, _ = fmt.Fprint(writer, opts.fit(output.Beginning(body)))
 }
 
 type modifiedColumn struct{}
 
-func (m modifiedColumn) printHeader(_ opts, writer *ansiterm.TabWriter) {
-	_, _ = writer.Write([]byte("MODIFIED"))
+func (m modifiedColumn) printHeader(opts opts, writer *ansiterm.TabWriter) {
+	_, _ = fmt.Fprint(writer, opts.fit("MODIFIED"))
 }
 
 func (m modifiedColumn) printValue(note notes.Note, opts opts, writer *ansiterm.TabWriter) {
@@ -168,8 +271,8 @@ func (m modifiedColumn) printValue(note notes.Note, opts opts, writer *ansiterm.
 		writeError(err, writer)
 		return
 	}
-	formatted := date.FormatWithType(modified, opts.dateFormat)
-	_, _ = fmt.Fprint(writer, formatted)
+	formatted := opts.formatDate(modified)
+	_, _ = fmt.Fprint(writer, opts.fit(formatted))
 }
 
 func writeError(err error, writer *ansiterm.TabWriter) {
@@ -180,8 +283,8 @@ func writeError(err error, writer *ansiterm.TabWriter) {
 
 type createdColumn struct{}
 
-func (c createdColumn) printHeader(_ opts, writer *ansiterm.TabWriter) {
-	_, _ = writer.Write([]byte("CREATED"))
+func (c createdColumn) printHeader(opts opts, writer *ansiterm.TabWriter) {
+	_, _ = fmt.Fprint(writer, opts.fit("CREATED"))
 }
 
 func (c createdColumn) printValue(note notes.Note, opts opts, writer *ansiterm.TabWriter) {
@@ -190,18 +293,148 @@ func (c createdColumn) printValue(note notes.Note, opts opts, writer *ansiterm.T
 		writeError(err, writer)
 		return
 	}
-	modified := date.FormatWithType(created, opts.dateFormat)
-	_, _ = fmt.Fprint(writer, modified)
+	_, _ = fmt.Fprint(writer, opts.fit(opts.formatDate(created)))
 }
 
 type tagsColumn struct{}
 
-func (t tagsColumn) printHeader(_ opts, writer *ansiterm.TabWriter) {
-	_, _ = writer.Write([]byte("TAGS"))
+func (t tagsColumn) printHeader(opts opts, writer *ansiterm.TabWriter) {
+	_, _ = fmt.Fprint(writer, opts.fit("TAGS"))
 }
 
 func (t tagsColumn) printValue(note notes.Note, opts opts, writer *ansiterm.TabWriter) {
 	tags, _ := output.StringTags(note)
 	tagsString := strings.Join(tags, " ")
-	_, _ = fmt.Fprint(writer, tagsString)
+	_, _ = fmt.Fprint(writer, opts.fit(tagsString))
+}
+
+type sizeColumn struct{}
+
+func (s sizeColumn) printHeader(opts opts, writer *ansiterm.TabWriter) {
+	_, _ = fmt.Fprint(writer, opts.fit("SIZE"))
+}
+
+func (s sizeColumn) printValue(note notes.Note, opts opts, writer *ansiterm.TabWriter) {
+	size, err := output.Size(note)
+	if err != nil {
+		writeError(err, writer)
+		return
+	}
+	if size >= 0 {
+		_, _ = fmt.Fprint(writer, opts.fit(strconv.FormatInt(size, 10)))
+	}
+}
+
+type wordsColumn struct{}
+
+func (w wordsColumn) printHeader(opts opts, writer *ansiterm.TabWriter) {
+	_, _ = fmt.Fprint(writer, opts.fit("WORDS"))
+}
+
+func (w wordsColumn) printValue(note notes.Note, opts opts, writer *ansiterm.TabWriter) {
+	body, err := note.Body()
+	if err != nil {
+		writeError(err, writer)
+		return
+	}
+	_, _ = fmt.Fprint(writer, opts.fit(strconv.Itoa(len(strings.Fields(body)))))
+}
+
+type linksColumn struct{}
+
+func (l linksColumn) printHeader(opts opts, writer *ansiterm.TabWriter) {
+	_, _ = fmt.Fprint(writer, opts.fit("LINKS"))
+}
+
+func (l linksColumn) printValue(note notes.Note, opts opts, writer *ansiterm.TabWriter) {
+	links, err := output.Links(note)
+	if err != nil {
+		writeError(err, writer)
+		return
+	}
+	_, _ = fmt.Fprint(writer, opts.fit(strconv.Itoa(len(links))))
+}
+
+type backlinksColumn struct{}
+
+func (b backlinksColumn) printHeader(opts opts, writer *ansiterm.TabWriter) {
+	_, _ = fmt.Fprint(writer, opts.fit("BACKLINKS"))
+}
+
+func (b backlinksColumn) printValue(note notes.Note, opts opts, writer *ansiterm.TabWriter) {
+	if backlinks := output.Backlinks(note); backlinks >= 0 {
+		_, _ = fmt.Fprint(writer, opts.fit(strconv.Itoa(backlinks)))
+	}
+}
+
+// tagColumn shows value of the tag. Dates are formatted like other dates, e.g. "in 3 days". Tag without value is
+// shown as its name.
+type tagColumn struct {
+	name string
+}
+
+func (t tagColumn) printHeader(opts opts, writer *ansiterm.TabWriter) {
+	_, _ = fmt.Fprint(writer, opts.fit(strings.ToUpper(t.name)))
+}
+
+func (t tagColumn) printValue(note notes.Note, opts opts, writer *ansiterm.TabWriter) {
+	found, ok, err := notes.FindTagByName(note, t.name)
+	if err != nil {
+		writeError(err, writer)
+		return
+	}
+	if !ok {
+		return
+	}
+	value, err := found.Value()
+	if err != nil {
+		_, _ = fmt.Fprint(writer, opts.fit(found.Name()))
+		return
+	}
+	if d, err := found.AbsoluteDate(); err == nil {
+		value = opts.formatDate(d)
+	}
+	_, _ = fmt.Fprint(writer, opts.fit(value))
+}
+
+// fieldColumn shows value of the front matter key. Dates are formatted like other dates and lists are separated
+// with spaces.
+type fieldColumn struct {
+	key string
+}
+
+func (f fieldColumn) printHeader(opts opts, writer *ansiterm.TabWriter) {
+	_, _ = fmt.Fprint(writer, opts.fit(strings.ToUpper(f.key)))
+}
+
+func (f fieldColumn) printValue(note notes.Note, opts opts, writer *ansiterm.TabWriter) {
+	value, found, err := output.Field(note, f.key)
+	if err != nil {
+		writeError(err, writer)
+		return
+	}
+	if !found || value == nil {
+		return
+	}
+	_, _ = fmt.Fprint(writer, opts.fit(f.format(value, opts)))
+}
+
+func (f fieldColumn) format(value interface{}, opts opts) string {
+	switch v := value.(type) {
+	case time.Time:
+		return opts.formatDate(v)
+	case string:
+		if d, err := date.ParseAbsolute(v); err == nil {
+			return opts.formatDate(d)
+		}
+		return v
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = f.format(item, opts)
+		}
+		return strings.Join(items, " ")
+	default:
+		return fmt.Sprint(v)
+	}
 }
@@ -0,0 +1,79 @@
+package table_test
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/noteo/date"
+	"github.com/elgopher/noteo/note"
+	"github.com/elgopher/noteo/output/table"
+)
+
+func TestFormatter_Note(t *testing.T) {
+	dir := t.TempDir()
+	content := "---\nTitle: Plan\nAuthor: [ann, bob]\nTags: priority:2 deadline:2020-09-08 urgent\n---\n" +
+		"# Heading\nSee [b](b.md) and [site](https://example.com)\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.md"), []byte(content), 0664))
+	n := note.NewInDir(dir, "a.md")
+	date.SetNow(func() time.Time {
+		return time.Date(2020, 9, 5, 0, 0, 0, 0, time.UTC)
+	})
+	defer date.SetNow(time.Now)
+
+	tests := map[string]struct {
+		column string
+		header string
+		value  string
+	}{
+		"title from field":   {column: "title", header: "TITLE" + strings.Repeat(" ", 29), value: "Plan" + strings.Repeat(" ", 30)},
+		"beginning of width": {column: "beginning:5", header: "BEGI…", value: "Head…"},
+		"dir":                {column: "dir", header: "DIR", value: "."},
+		"size":               {column: "size", header: "SIZE", value: strconv.Itoa(len(content))},
+		"words":              {column: "words", header: "WORDS", value: "6"},
+		"links":              {column: "links", header: "LINKS", value: "1"},
+		"tag value":          {column: "tag:priority", header: "PRIORITY", value: "2"},
+		"tag date":           {column: "tag:deadline", header: "DEADLINE", value: "in 3 days"},
+		"tag without value":  {column: "tag:urgent", header: "URGENT", value: "urgent"},
+		"missing tag":        {column: "tag:status", header: "STATUS", value: ""},
+		"field":              {column: "field:author", header: "AUTHOR", value: "ann bob"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			f, err := table.NewFormatter([]string{test.column}, date.Relative, nil)
+			require.NoError(t, err)
+			f.SetColorCapable(false)
+			// when
+			out := f.Header() + f.Note(n) + f.Footer()
+			// then
+			lines := strings.Split(out, "\n")
+			require.Len(t, lines, 3)
+			assert.Equal(t, test.header, strings.TrimRight(lines[0], "\t"))
+			assert.Equal(t, test.value, strings.TrimRight(lines[1], "\t"))
+		})
+	}
+}
+
+func TestNewFormatter(t *testing.T) {
+	tests := map[string]string{
+		"unknown":   "unsupported output column: UNKNOWN",
+		"tag":       "output column TAG requires a name, e.g. tag:deadline",
+		"file:wide": "invalid width of output column FILE: wide",
+		"file:0":    "invalid width of output column FILE: 0",
+		"tag:a:1:2": "unsupported output column: tag:a:1:2",
+	}
+	for column, expectedError := range tests {
+		t.Run(column, func(t *testing.T) {
+			// when
+			_, err := table.NewFormatter([]string{column}, date.Relative, nil)
+			// then
+			assert.EqualError(t, err, expectedError)
+		})
+	}
+}
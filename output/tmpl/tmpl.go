@@ -0,0 +1,117 @@
+// Package tmpl formats notes using Go templates, e.g. {{.File}}\t{{.Tag "priority"}}
+package tmpl
+
+import (
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/elgopher/noteo/date"
+	"github.com/elgopher/noteo/notes"
+	"github.com/elgopher/noteo/output"
+)
+
+type Formatter struct {
+	template *template.Template
+}
+
+// New parses the template executed for each note. Dates are formatted by date function using dateFormat in given
+// location, which is optional.
+func New(text string, dateFormat date.Format, location *time.Location) (*Formatter, error) {
+	in := func(t time.Time) time.Time {
+		if location != nil {
+			return t.In(location)
+		}
+		return t
+	}
+	t, err := template.New("output").Funcs(template.FuncMap{
+		"date": func(t time.Time) string {
+			return date.FormatWithType(in(t), dateFormat)
+		},
+		"formatDate": func(format string, t time.Time) (string, error) {
+			f, err := date.ParseFormat(format)
+			if err != nil {
+				return "", err
+			}
+			return date.FormatWithType(in(t), f), nil
+		},
+		"excerpt": func(limit int, text string) string {
+			return output.Excerpt(text, limit)
+		},
+		"join": func(sep string, items []string) string {
+			return strings.Join(items, sep)
+		},
+	}).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return &Formatter{template: t}, nil
+}
+
+func (f *Formatter) Header() string {
+	return ""
+}
+
+func (f *Formatter) Footer() string {
+	return ""
+}
+
+func (f *Formatter) Note(note notes.Note) string {
+	var out strings.Builder
+	if err := f.template.Execute(&out, Note{note: note}); err != nil {
+		return err.Error() + "\n"
+	}
+	return output.Line(out.String())
+}
+
+// Note is a data of the template
+type Note struct {
+	note notes.Note
+}
+
+func (n Note) File() string {
+	return n.note.Path()
+}
+
+// Repo returns name of the repository, which is empty unless notes of all repositories are listed
+func (n Note) Repo() string {
+	return output.Repo(n.note)
+}
+
+func (n Note) Created() (time.Time, error) {
+	return n.note.Created()
+}
+
+func (n Note) Modified() (time.Time, error) {
+	return n.note.Modified()
+}
+
+func (n Note) Tags() ([]string, error) {
+	return output.StringTags(n.note)
+}
+
+// Tag returns value of the tag with given name. It is empty when the note has no such tag or the tag has no value.
+func (n Note) Tag(name string) (string, error) {
+	t, found, err := notes.FindTagByName(n.note, name)
+	if err != nil || !found {
+		return "", err
+	}
+	value, _ := t.Value()
+	return value, nil
+}
+
+func (n Note) HasTag(name string) (bool, error) {
+	_, found, err := notes.FindTagByName(n.note, name)
+	return found, err
+}
+
+// Text returns the body of the note, without front matter
+func (n Note) Text() (string, error) {
+	return n.note.Body()
+}
+
+// Beginning returns the first line of the body
+func (n Note) Beginning() (string, error) {
+	body, err := n.note.Body()
+	return output.Beginning(body), err
+}
@@ -0,0 +1,56 @@
+package tmpl_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/noteo/date"
+	"github.com/elgopher/noteo/note"
+	"github.com/elgopher/noteo/output/tmpl"
+)
+
+func TestFormatter_Note(t *testing.T) {
+	n := newNote(t, "---\nCreated: 2020-09-05T12:30:00Z\nTags: priority:2 work\n---\n# Fix bug\nin parser\n")
+	tests := map[string]struct {
+		template string
+		expected string
+	}{
+		"tag value":           {template: `{{.Tag "priority"}}`, expected: "2\n"},
+		"missing tag":         {template: `[{{.Tag "deadline"}}]`, expected: "[]\n"},
+		"has tag":             {template: `{{if .HasTag "work"}}work{{end}}`, expected: "work\n"},
+		"joined tags":         {template: `{{.Tags | join ","}}`, expected: "priority:2,work\n"},
+		"date":                {template: `{{date .Created}}`, expected: "2020-09-05 12:30:00 Z\n"},
+		"formatted date":      {template: `{{formatDate "format:%Y-%m-%d" .Created}}`, expected: "2020-09-05\n"},
+		"beginning":           {template: `{{.Beginning}}`, expected: "Fix bug\n"},
+		"excerpt":             {template: `{{excerpt 4 .Beginning}}`, expected: "Fix…\n"},
+		"ending with newline": {template: "{{.Tag \"priority\"}}\n", expected: "2\n"},
+		"empty":               {template: `{{if .HasTag "home"}}home{{end}}`, expected: ""},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			f, err := tmpl.New(test.template, date.ISO8601, nil)
+			require.NoError(t, err)
+			// when
+			out := f.Note(n)
+			// then
+			assert.Equal(t, test.expected, out)
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	// when
+	_, err := tmpl.New("{{.File", date.ISO8601, nil)
+	// then
+	assert.Error(t, err)
+}
+
+func newNote(t *testing.T, content string) *note.Note {
+	file := filepath.Join(t.TempDir(), "note.md")
+	require.NoError(t, os.WriteFile(file, []byte(content), 0664))
+	return note.New(file)
+}
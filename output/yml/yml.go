@@ -3,12 +3,18 @@ package yml
 import (
 	"time"
 
+	"github.com/elgopher/noteo/date"
 	"github.com/elgopher/noteo/notes"
 	"github.com/elgopher/noteo/output"
 	"gopkg.in/yaml.v2"
 )
 
-type Formatter struct{}
+type Formatter struct {
+	// DateFormat is optional. When empty, dates are marshalled as time.Time
+	DateFormat date.Format
+	// Location is optional. When nil, dates are not converted
+	Location *time.Location
+}
 
 func (f Formatter) Header() string {
 	return ""
@@ -36,9 +42,10 @@ func (f Formatter) Note(note notes.Note) string {
 		return err.Error()
 	}
 	n := noteToMarshal{
+		Repo:     output.Repo(note),
 		File:     note.Path(),
-		Modified: modified,
-		Created:  created,
+		Modified: output.Date(modified, f.DateFormat, f.Location),
+		Created:  output.Date(created, f.DateFormat, f.Location),
 		Text:     body,
 		Tags:     tags,
 	}
@@ -50,9 +57,10 @@ func (f Formatter) Note(note notes.Note) string {
 }
 
 type noteToMarshal struct {
-	File     string    `yaml:"file"`
-	Modified time.Time `yaml:"modified"`
-	Created  time.Time `yaml:"created"`
-	Tags     []string  `yaml:"tags"`
-	Text     string    `yaml:"text"`
+	Repo     string      `yaml:"repo,omitempty"`
+	File     string      `yaml:"file"`
+	Modified interface{} `yaml:"modified"`
+	Created  interface{} `yaml:"created"`
+	Tags     []string    `yaml:"tags"`
+	Text     string      `yaml:"text"`
 }
@@ -2,56 +2,123 @@ package parser
 
 import (
 	"bufio"
-	"bytes"
+	"encoding/json"
+	"errors"
 	"io"
-	"regexp"
+	"strings"
 )
 
-var frontMatterDividerRegex = regexp.MustCompile(`^---`)
+// Format of the front matter
+type Format string
 
+const (
+	YAML Format = "yaml"
+	TOML Format = "toml"
+	JSON Format = "json"
+)
+
+// delimiters are lines opening and closing front matter. JSON front matter can be given either between ;;; lines or
+// as an object with { and } in separate lines.
+var delimiters = []struct {
+	open, close string
+	format      Format
+}{
+	{open: "---", close: "---", format: YAML},
+	{open: "+++", close: "+++", format: TOML},
+	{open: ";;;", close: ";;;", format: JSON},
+	{open: "{", close: "}", format: JSON},
+}
+
+// Parse splits content into front matter, including delimiters, and body. Front matter is recognized only when the
+// first line is exactly one of the delimiters: ---, +++, ;;; or {. A line such as ---- is a part of the body. JSON
+// object started with { ends with the } line closing the object, not a nested one.
+// Lines can be of any length.
 func Parse(reader io.Reader) (frontMatter string, body string, err error) {
-	scanner := bufio.NewScanner(reader)
-	scanner.Split(splitLinesIncludeEOL)
-	if scanner.Scan() {
-		firstLine := scanner.Text()
-		lines := ""
-		if frontMatterDividerRegex.MatchString(firstLine) {
-			lines += firstLine
-			for scanner.Scan() {
-				line := scanner.Text()
-				lines += line
-				if frontMatterDividerRegex.MatchString(line) {
-					frontMatter = lines
-					for scanner.Scan() {
-						bodyLine := scanner.Text()
-						body += bodyLine
-					}
-					return
-				}
-			}
-			body = lines
-		} else {
-			body += firstLine
-			for scanner.Scan() {
-				body += scanner.Text()
-			}
+	r := bufio.NewReader(reader)
+	frontMatter, consumed, err := parseFrontMatter(r)
+	if err != nil {
+		return "", "", err
+	}
+	var b strings.Builder
+	b.WriteString(consumed)
+	if _, err = io.Copy(&b, r); err != nil {
+		return "", "", err
+	}
+	return frontMatter, b.String(), nil
+}
+
+// ParseFrontMatter is like Parse, but returns only front matter and stops reading after the closing delimiter. Body
+// is not read, unless front matter is not closed.
+func ParseFrontMatter(reader io.Reader) (string, error) {
+	frontMatter, _, err := parseFrontMatter(bufio.NewReader(reader))
+	return frontMatter, err
+}
+
+// parseFrontMatter reads front matter. When there is no front matter, lines read so far are returned as consumed.
+func parseFrontMatter(r *bufio.Reader) (frontMatter string, consumed string, err error) {
+	firstLine, err := readLine(r)
+	if err != nil || firstLine == "" {
+		return "", firstLine, err
+	}
+	closing, ok := closingDelimiter(firstLine)
+	if !ok {
+		return "", firstLine, nil
+	}
+	var lines strings.Builder
+	lines.WriteString(firstLine)
+	for {
+		line, err := readLine(r)
+		if err != nil {
+			return "", "", err
+		}
+		if line == "" {
+			// front matter is not closed, so it is a part of the body
+			return "", lines.String(), nil
+		}
+		lines.WriteString(line)
+		if isDelimiter(line, closing) && (closing != "}" || isObjectClosed(lines.String())) {
+			return lines.String(), "", nil
 		}
 	}
-	return
 }
 
-func splitLinesIncludeEOL(data []byte, atEOF bool) (advance int, token []byte, err error) {
-	if atEOF && len(data) == 0 {
-		return 0, nil, nil
+// readLine returns the next line including end of line. Returns empty string at the end of input.
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err == io.EOF {
+		err = nil
 	}
-	if i := bytes.IndexByte(data, '\n'); i >= 0 {
-		// We have a full newline-terminated line.
-		return i + 1, data[0 : i+1], nil
+	return line, err
+}
+
+func closingDelimiter(firstLine string) (string, bool) {
+	for _, d := range delimiters {
+		if isDelimiter(firstLine, d.open) {
+			return d.close, true
+		}
 	}
-	// If we're at EOF, we have a final, non-terminated line. Return it.
-	if atEOF {
-		return len(data), data, nil
+	return "", false
+}
+
+// isObjectClosed returns false when JSON object ends before its closing brace, e.g. when } closes a nested object.
+// Invalid JSON is treated as closed, so the error is reported when the front matter is used.
+func isObjectClosed(object string) bool {
+	var value json.RawMessage
+	err := json.NewDecoder(strings.NewReader(object)).Decode(&value)
+	return !errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+func isDelimiter(line, delimiter string) bool {
+	return strings.TrimRight(line, " \t\r\n") == delimiter
+}
+
+// FormatOf returns format of the front matter returned by Parse. Empty front matter is YAML.
+func FormatOf(frontMatter string) Format {
+	firstLine, _, _ := strings.Cut(frontMatter, "\n")
+	for _, d := range delimiters {
+		if isDelimiter(firstLine, d.open) {
+			return d.format
+		}
 	}
-	// Request more data.
-	return 0, nil, nil
+	return YAML
 }
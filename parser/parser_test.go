@@ -1,6 +1,8 @@
 package parser_test
 
 import (
+	"errors"
+	"io"
 	"strings"
 	"testing"
 
@@ -51,6 +53,46 @@ func TestParse(t *testing.T) {
 			expectedFrontMatter: "",
 			expectedBody:        "---\ntags:abc",
 		},
+		"horizontal rule": {
+			content:             "----\ntags: abc\n----\ntext",
+			expectedFrontMatter: "",
+			expectedBody:        "----\ntags: abc\n----\ntext",
+		},
+		"closing line with trailing text": {
+			content:             "---\ntags: abc\n--- x\n---\ntext",
+			expectedFrontMatter: "---\ntags: abc\n--- x\n---\n",
+			expectedBody:        "text",
+		},
+		"CRLF": {
+			content:             "---\r\ntags: abc\r\n---\r\ntext",
+			expectedFrontMatter: "---\r\ntags: abc\r\n---\r\n",
+			expectedBody:        "text",
+		},
+		"TOML": {
+			content:             "+++\ntags = [\"abc\"]\n+++\ntext",
+			expectedFrontMatter: "+++\ntags = [\"abc\"]\n+++\n",
+			expectedBody:        "text",
+		},
+		"JSON between ;;;": {
+			content:             ";;;\n{\"tags\": \"abc\"}\n;;;\ntext",
+			expectedFrontMatter: ";;;\n{\"tags\": \"abc\"}\n;;;\n",
+			expectedBody:        "text",
+		},
+		"JSON object": {
+			content:             "{\n  \"tags\": \"abc\"\n}\ntext",
+			expectedFrontMatter: "{\n  \"tags\": \"abc\"\n}\n",
+			expectedBody:        "text",
+		},
+		"JSON object with nested object closed in the first column": {
+			content:             "{\n\"meta\": {\n\"a\": 1\n}\n, \"tags\": \"abc\"\n}\ntext\n}\n",
+			expectedFrontMatter: "{\n\"meta\": {\n\"a\": 1\n}\n, \"tags\": \"abc\"\n}\n",
+			expectedBody:        "text\n}\n",
+		},
+		"invalid JSON object": {
+			content:             "{\n\"tags\": \"abc\",\n}\ntext",
+			expectedFrontMatter: "{\n\"tags\": \"abc\",\n}\n",
+			expectedBody:        "text",
+		},
 		"tag ---": {
 			content:             "---\ntags: ---\n---",
 			expectedFrontMatter: "---\ntags: ---\n---",
@@ -67,3 +109,57 @@ func TestParse(t *testing.T) {
 	}
 
 }
+
+func TestParse_LongLine(t *testing.T) {
+	longLine := strings.Repeat("x", 1024*1024)
+	// when
+	frontMatter, body, err := parser.Parse(strings.NewReader("---\ntags: " + longLine + "\n---\n" + longLine))
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "---\ntags: "+longLine+"\n---\n", frontMatter)
+	assert.Equal(t, longLine, body)
+}
+
+var errBodyRead = errors.New("body should not be read")
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errBodyRead
+}
+
+func TestParseFrontMatter(t *testing.T) {
+	t.Run("should not read body", func(t *testing.T) {
+		reader := io.MultiReader(strings.NewReader("---\ntags: abc\n---\n"), failingReader{})
+		// when
+		frontMatter, err := parser.ParseFrontMatter(reader)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "---\ntags: abc\n---\n", frontMatter)
+	})
+
+	t.Run("should return empty front matter", func(t *testing.T) {
+		for _, content := range []string{"", "body", "---\nnot closed"} {
+			// when
+			frontMatter, err := parser.ParseFrontMatter(strings.NewReader(content))
+			// then
+			require.NoError(t, err)
+			assert.Empty(t, frontMatter)
+		}
+	})
+}
+
+func TestFormatOf(t *testing.T) {
+	tests := map[string]parser.Format{
+		"":                       parser.YAML,
+		"---\ntags: a\n---\n":    parser.YAML,
+		"+++\ntags = 'a'\n+++\n": parser.TOML,
+		";;;\n{}\n;;;\n":         parser.JSON,
+		"{\n}\n":                 parser.JSON,
+	}
+	for frontMatter, expected := range tests {
+		t.Run(frontMatter, func(t *testing.T) {
+			assert.Equal(t, expected, parser.FormatOf(frontMatter))
+		})
+	}
+}
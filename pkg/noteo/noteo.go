@@ -0,0 +1,327 @@
+// Package noteo is a stable API for embedding noteo in Go programs. Unlike the command line tool, it does not depend
+// on the current working directory of the process and returns slices with errors instead of channels.
+package noteo
+
+import (
+	"context"
+	"errors"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/elgopher/noteo/listing"
+	"github.com/elgopher/noteo/note"
+	"github.com/elgopher/noteo/notes"
+	"github.com/elgopher/noteo/repository"
+	"github.com/elgopher/noteo/seq"
+	"github.com/elgopher/noteo/tag"
+)
+
+// Repository gives access to notes in a directory and its subdirectories
+type Repository struct {
+	repo *repository.Repository
+}
+
+// Open opens notes in dir. The dir must be inside a noteo repository, that is a directory initialized using
+// "noteo init" or one of its subdirectories.
+func Open(dir string) (*Repository, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := repository.ForWorkDir(abs)
+	if err != nil {
+		return nil, err
+	}
+	return &Repository{repo: repo}, nil
+}
+
+// Dir returns absolute path of the directory given in Open
+func (r *Repository) Dir() string {
+	return r.repo.WorkDir()
+}
+
+// Note is a snapshot of the note taken when the note was read
+type Note struct {
+	// Path is relative to the directory given in Open
+	Path     string
+	Modified time.Time
+	// Created is taken from the front matter. It is zero when not given.
+	Created time.Time
+	Tags    []string
+	// Text is the note without the front matter
+	Text string
+}
+
+// Query selects notes. Zero Query selects all notes sorted by modification time descending.
+type Query struct {
+	// Tags which all selected notes must have, such as "idea" or "priority:1"
+	Tags []string
+	// Filters are additional conditions, such as created by TagGreater or Grep
+	Filters []Filter
+	// Sort is SortByModified when zero
+	Sort Sort
+	// Limit is the maximum number of notes returned. No limit when 0.
+	Limit int
+}
+
+// Filter is a condition which selected notes must meet
+type Filter struct {
+	predicate notes.Predicate
+}
+
+// TagGreater selects notes having tag with value (number, duration or date) greater than given, e.g. "priority:1"
+func TagGreater(tagNameValue string) (Filter, error) {
+	predicate, err := notes.TagGreater(tagNameValue)
+	return Filter{predicate: predicate}, err
+}
+
+// TagLower selects notes having tag with value (number, duration or date) lower than given, e.g. "deadline:today"
+func TagLower(tagNameValue string) (Filter, error) {
+	predicate, err := notes.TagLower(tagNameValue)
+	return Filter{predicate: predicate}, err
+}
+
+// Grep selects notes with text matching regular expression
+func Grep(expr string) (Filter, error) {
+	predicate, err := notes.Grep(expr)
+	return Filter{predicate: predicate}, err
+}
+
+// Sort is an order of notes, descending unless reversed
+type Sort struct {
+	desc, asc notes.Less
+}
+
+// SortByModified sorts by modification time
+func SortByModified() Sort {
+	return Sort{desc: notes.ModifiedDesc, asc: notes.ModifiedAsc}
+}
+
+// SortByCreated sorts by Created given in the front matter
+func SortByCreated() Sort {
+	return Sort{desc: notes.CreatedDesc, asc: notes.CreatedAsc}
+}
+
+// SortByTagNumber sorts by number, duration or date given in a tag with name
+func SortByTagNumber(name string) Sort {
+	return Sort{desc: notes.TagNumberDesc(name), asc: notes.TagNumberAsc(name)}
+}
+
+// SortByTagDate sorts by date given in a tag with name
+func SortByTagDate(name string) Sort {
+	return Sort{desc: notes.TagDateDesc(name), asc: notes.TagDateAsc(name)}
+}
+
+// Reverse returns ascending sort for descending one and vice versa
+func (s Sort) Reverse() Sort {
+	return Sort{desc: s.asc, asc: s.desc}
+}
+
+func (s Sort) less() notes.Less {
+	if s.desc == nil {
+		return notes.ModifiedDesc
+	}
+	return s.desc
+}
+
+// ParseQuery returns query built from ls filtering, sorting and limiting flags, for example
+// ParseQuery("--tag-greater", "priority:1", "--sort-by-created", "--limit", "10"). Tag types declared in the
+// repository vocabulary are used.
+func (r *Repository) ParseQuery(args ...string) (Query, error) {
+	config, err := r.repo.Config()
+	if err != nil {
+		return Query{}, err
+	}
+	vocabulary, err := config.Vocabulary()
+	if err != nil {
+		return Query{}, err
+	}
+	lsQuery, err := listing.ParseQuery(args, vocabulary)
+	if err != nil {
+		return Query{}, err
+	}
+	query := Query{
+		Sort:  Sort{desc: lsQuery.Less, asc: lsQuery.ReversedLess},
+		Limit: lsQuery.Limit,
+	}
+	for _, predicate := range lsQuery.Predicates {
+		query.Filters = append(query.Filters, Filter{predicate: predicate})
+	}
+	return query, nil
+}
+
+// Notes returns notes selected by query. When some notes could not be read or filtered, they are skipped and returned
+// error joins all errors. Notes read successfully are returned anyway.
+func (r *Repository) Notes(ctx context.Context, query Query) ([]Note, error) {
+	var predicates []notes.Predicate
+	for _, filter := range query.Filters {
+		predicates = append(predicates, filter.predicate)
+	}
+	for _, t := range query.Tags {
+		predicates = append(predicates, notes.Tag(t))
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = math.MaxInt32
+	}
+	dirNotes := seq.Map(r.repo.NotesSeq(ctx), func(n *note.Note) notes.Note {
+		return n
+	})
+	var result []Note
+	var errs []error
+	notes.TopSeq(limit, notes.FilterSeq(dirNotes, predicates...), query.Sort.less())(func(n notes.Note, err error) bool {
+		var converted Note
+		if err == nil {
+			converted, err = snapshot(n)
+		}
+		if err != nil {
+			errs = append(errs, err)
+			return true
+		}
+		result = append(result, converted)
+		return true
+	})
+	return result, errors.Join(errs...)
+}
+
+// Formatter formats notes the same way as ls
+type Formatter struct {
+	formatter listing.Formatter
+}
+
+// NewFormatter returns formatter of ls output, such as "table=file,tags", "json", "yaml" or "template={{.File}}".
+// Date format, such as "iso8601" or "format:%Y-%m-%d", and time zone are the same as in ls --date and --tz flags.
+// Empty values are defaults of ls: table output, relative dates in tables and local time. User settings are not used.
+func NewFormatter(output, dateFormat, timezone string) (*Formatter, error) {
+	formatter, err := listing.NewFormatter(listing.Output{Format: output, DateFormat: dateFormat, Timezone: timezone})
+	if err != nil {
+		return nil, err
+	}
+	return &Formatter{formatter: formatter}, nil
+}
+
+// Format returns notes formatted together with a header and footer of the output format
+func (f *Formatter) Format(list []Note) string {
+	var b strings.Builder
+	b.WriteString(f.formatter.Header())
+	for i := range list {
+		b.WriteString(f.formatter.Note(snapshotNote{note: &list[i]}))
+	}
+	b.WriteString(f.formatter.Footer())
+	return b.String()
+}
+
+// snapshotNote is notes.Note for a Note snapshot
+type snapshotNote struct {
+	note *Note
+}
+
+func (n snapshotNote) Path() string {
+	return n.note.Path
+}
+
+func (n snapshotNote) Modified() (time.Time, error) {
+	return n.note.Modified, nil
+}
+
+func (n snapshotNote) Created() (time.Time, error) {
+	return n.note.Created, nil
+}
+
+func (n snapshotNote) Tags() ([]tag.Tag, error) {
+	tags := make([]tag.Tag, 0, len(n.note.Tags))
+	for _, t := range n.note.Tags {
+		parsed, err := tag.New(t)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, parsed)
+	}
+	return tags, nil
+}
+
+func (n snapshotNote) Body() (string, error) {
+	return n.note.Text, nil
+}
+
+func snapshot(n notes.Note) (Note, error) {
+	modified, err := n.Modified()
+	if err != nil {
+		return Note{}, err
+	}
+	created, err := n.Created()
+	if err != nil {
+		return Note{}, err
+	}
+	tags, err := n.Tags()
+	if err != nil {
+		return Note{}, err
+	}
+	text, err := n.Body()
+	if err != nil {
+		return Note{}, err
+	}
+	stringTags := make([]string, 0, len(tags))
+	for _, t := range tags {
+		stringTags = append(stringTags, t.String())
+	}
+	return Note{
+		Path:     n.Path(),
+		Modified: modified,
+		Created:  created,
+		Tags:     stringTags,
+		Text:     text,
+	}, nil
+}
+
+// Tags returns unique tags of all notes, sorted alphabetically
+func (r *Repository) Tags(ctx context.Context) ([]string, error) {
+	tags, errs := seq.Collect(r.repo.TagsSeq(ctx))
+	unique := map[string]struct{}{}
+	for _, t := range tags {
+		unique[t.String()] = struct{}{}
+	}
+	result := make([]string, 0, len(unique))
+	for t := range unique {
+		result = append(result, t)
+	}
+	sort.Strings(result)
+	return result, errors.Join(errs...)
+}
+
+// Add creates a new note with a file name generated from the text. Returns path relative to the directory given in Open.
+func (r *Repository) Add(text string) (string, error) {
+	return r.repo.Add(text)
+}
+
+// Tag adds the tag to the note or replaces the value of the tag with the same name. Returns true if the file was changed.
+func (r *Repository) Tag(file, tag string) (bool, error) {
+	return r.repo.TagFileWith(file, tag)
+}
+
+// Untag removes the tag from the note. Returns true if the file was changed.
+func (r *Repository) Untag(file, tag string) (bool, error) {
+	return r.repo.UntagFile(file, tag)
+}
+
+// Move moves the note or directory and updates links in other notes. Returns paths of updated notes.
+func (r *Repository) Move(ctx context.Context, source, target string) ([]string, error) {
+	updatedNotes, err := r.repo.MoveSeq(ctx, source, target)
+	if err != nil {
+		return nil, err
+	}
+	var updated []string
+	var errs []error
+	updatedNotes(func(n *note.Note, err error) bool {
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			updated = append(updated, n.Path())
+		}
+		return true
+	})
+	return updated, errors.Join(errs...)
+}
@@ -0,0 +1,211 @@
+package noteo_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/noteo/pkg/noteo"
+	"github.com/elgopher/noteo/repository"
+)
+
+// Tests do not change the working directory, so they check that the library does not depend on it
+
+func TestOpen(t *testing.T) {
+	t.Run("should return error when dir is not a repository", func(t *testing.T) {
+		// when
+		_, err := noteo.Open(t.TempDir())
+		// then
+		assert.Error(t, err)
+	})
+}
+
+func TestRepository_Notes(t *testing.T) {
+	t.Run("should return all notes", func(t *testing.T) {
+		dir, repo := openRepo(t)
+		writeFile(t, filepath.Join(dir, "a.md"), "---\nTags: idea priority:2\nCreated: 2020-08-30\n---\ntext")
+		// when
+		all, err := repo.Notes(context.Background(), noteo.Query{})
+		// then
+		require.NoError(t, err)
+		require.Len(t, all, 1)
+		assert.Equal(t, "a.md", all[0].Path)
+		assert.Equal(t, []string{"idea", "priority:2"}, all[0].Tags)
+		assert.Equal(t, "text", all[0].Text)
+		assert.Equal(t, 2020, all[0].Created.Year())
+		assert.False(t, all[0].Modified.IsZero())
+	})
+
+	t.Run("should select notes using query", func(t *testing.T) {
+		dir, repo := openRepo(t)
+		writeFile(t, filepath.Join(dir, "a.md"), "---\nTags: idea priority:1\n---\na")
+		writeFile(t, filepath.Join(dir, "b.md"), "---\nTags: idea priority:3\n---\nb")
+		writeFile(t, filepath.Join(dir, "c.md"), "---\nTags: idea priority:2\n---\nc")
+		writeFile(t, filepath.Join(dir, "d.md"), "---\nTags: priority:4\n---\nd")
+		priorityGreaterThan1, err := noteo.TagGreater("priority:1")
+		require.NoError(t, err)
+		query := noteo.Query{
+			Tags:    []string{"idea"},
+			Filters: []noteo.Filter{priorityGreaterThan1},
+			Sort:    noteo.SortByTagNumber("priority"),
+			Limit:   1,
+		}
+		// when
+		selected, err := repo.Notes(context.Background(), query)
+		// then
+		require.NoError(t, err)
+		require.Len(t, selected, 1)
+		assert.Equal(t, "b.md", selected[0].Path)
+	})
+
+	t.Run("should select notes using query parsed from ls flags", func(t *testing.T) {
+		dir, repo := openRepo(t)
+		writeFile(t, filepath.Join(dir, "a.md"), "---\nTags: idea priority:1\n---\na")
+		writeFile(t, filepath.Join(dir, "b.md"), "---\nTags: idea priority:3\n---\nb")
+		writeFile(t, filepath.Join(dir, "c.md"), "---\nTags: priority:2\n---\nc")
+		query, err := repo.ParseQuery("-t", "idea", "--sort-by-tag-number", "priority", "--reverse")
+		require.NoError(t, err)
+		// when
+		selected, err := repo.Notes(context.Background(), query)
+		// then
+		require.NoError(t, err)
+		require.Len(t, selected, 2)
+		assert.Equal(t, "a.md", selected[0].Path)
+		assert.Equal(t, "b.md", selected[1].Path)
+	})
+
+	t.Run("should reverse sort of query parsed from ls flags", func(t *testing.T) {
+		dir, repo := openRepo(t)
+		writeFile(t, filepath.Join(dir, "a.md"), "---\nTags: priority:1\n---\na")
+		writeFile(t, filepath.Join(dir, "b.md"), "---\nTags: priority:3\n---\nb")
+		query, err := repo.ParseQuery("--sort-by-tag-number", "priority")
+		require.NoError(t, err)
+		query.Sort = query.Sort.Reverse()
+		// when
+		selected, err := repo.Notes(context.Background(), query)
+		// then
+		require.NoError(t, err)
+		require.Len(t, selected, 2)
+		assert.Equal(t, "a.md", selected[0].Path)
+		assert.Equal(t, "b.md", selected[1].Path)
+	})
+
+	t.Run("should return error for invalid ls flags", func(t *testing.T) {
+		_, repo := openRepo(t)
+		// when
+		_, err := repo.ParseQuery("--tag-greater", "priority")
+		// then
+		assert.Error(t, err)
+	})
+
+	t.Run("should return notes and error when some note is invalid", func(t *testing.T) {
+		dir, repo := openRepo(t)
+		writeFile(t, filepath.Join(dir, "valid.md"), "valid")
+		writeFile(t, filepath.Join(dir, "invalid.md"), "---\nTags: [\n---\n")
+		// when
+		all, err := repo.Notes(context.Background(), noteo.Query{})
+		// then
+		assert.Error(t, err)
+		require.Len(t, all, 1)
+		assert.Equal(t, "valid.md", all[0].Path)
+	})
+}
+
+func TestFormatter_Format(t *testing.T) {
+	formatter, err := noteo.NewFormatter(`template={{.File}} {{.Tag "priority"}}`, "", "")
+	require.NoError(t, err)
+	list := []noteo.Note{
+		{Path: "a.md", Tags: []string{"priority:1"}},
+		{Path: "b.md", Tags: []string{"priority:2"}},
+	}
+	// when
+	formatted := formatter.Format(list)
+	// then
+	assert.Equal(t, "a.md 1\nb.md 2\n", formatted)
+}
+
+func TestRepository_Tags(t *testing.T) {
+	dir, repo := openRepo(t)
+	writeFile(t, filepath.Join(dir, "a.md"), "---\nTags: b a\n---\n")
+	writeFile(t, filepath.Join(dir, "b.md"), "---\nTags: a\n---\n")
+	// when
+	tags, err := repo.Tags(context.Background())
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, tags)
+}
+
+func TestRepository_Add(t *testing.T) {
+	dir, repo := openRepo(t)
+	// when
+	file, err := repo.Add("new note")
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "new-note.md", file)
+	assert.FileExists(t, filepath.Join(dir, file))
+}
+
+func TestRepository_Tag(t *testing.T) {
+	dir, repo := openRepo(t)
+	file := filepath.Join(dir, "a.md")
+	writeFile(t, file, "text")
+	// when
+	updated, err := repo.Tag("a.md", "idea")
+	// then
+	require.NoError(t, err)
+	assert.True(t, updated)
+	assertFileEquals(t, file, "---\nTags: idea\n---\ntext")
+	// when
+	updated, err = repo.Untag("a.md", "idea")
+	// then
+	require.NoError(t, err)
+	assert.True(t, updated)
+	assertFileEquals(t, file, "---\nTags: \"\"\n---\ntext")
+}
+
+func TestRepository_Move(t *testing.T) {
+	t.Run("should move note and update links", func(t *testing.T) {
+		dir, repo := openRepo(t)
+		writeFile(t, filepath.Join(dir, "source.md"), "source")
+		writeFile(t, filepath.Join(dir, "link.md"), "[link](source.md)")
+		// when
+		updated, err := repo.Move(context.Background(), "source.md", "target.md")
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"link.md"}, updated)
+		assert.NoFileExists(t, filepath.Join(dir, "source.md"))
+		assert.FileExists(t, filepath.Join(dir, "target.md"))
+		assertFileEquals(t, filepath.Join(dir, "link.md"), "[link](target.md)")
+	})
+
+	t.Run("should return error when source does not exist", func(t *testing.T) {
+		_, repo := openRepo(t)
+		// when
+		_, err := repo.Move(context.Background(), "missing.md", "target.md")
+		// then
+		assert.Error(t, err)
+	})
+}
+
+func openRepo(t *testing.T) (string, *noteo.Repository) {
+	dir := t.TempDir()
+	_, err := repository.Init(dir)
+	require.NoError(t, err)
+	repo, err := noteo.Open(dir)
+	require.NoError(t, err)
+	return dir, repo
+}
+
+func writeFile(t *testing.T, filename, content string) {
+	require.NoError(t, os.WriteFile(filename, []byte(content), os.ModePerm))
+}
+
+func assertFileEquals(t *testing.T, filename, expected string) {
+	bytes, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(bytes))
+}
@@ -1,27 +1,229 @@
 package repository
 
 import (
+	"fmt"
 	"os"
+	"strings"
 
 	"gopkg.in/yaml.v2"
+
+	"github.com/elgopher/noteo/note"
+	"github.com/elgopher/noteo/tag"
 )
 
 func parse(file string) (*Config, error) {
 	c := &Config{}
+	if err := c.merge(file); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// merge overrides settings with the ones given in file. Keys of maps, such as tags.vocabulary, are added, lists are
+// replaced and views are replaced by name.
+func (r *Config) merge(file string) error {
 	bytes, err := os.ReadFile(file)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	if err = yaml.Unmarshal(bytes, c); err != nil {
-		return nil, err
+	views := r.Views
+	r.Views = nil
+	if err = yaml.Unmarshal(bytes, r); err != nil {
+		return fmt.Errorf("%s: %w", file, err)
 	}
-	return c, nil
+	r.Views = mergeViews(views, r.Views)
+	return nil
+}
+
+func mergeViews(views, overrides yaml.MapSlice) yaml.MapSlice {
+	merged := append(yaml.MapSlice{}, views...)
+	for _, override := range overrides {
+		replaced := false
+		for i := range merged {
+			if fmt.Sprint(merged[i].Key) == fmt.Sprint(override.Key) {
+				merged[i] = override
+				replaced = true
+			}
+		}
+		if !replaced {
+			merged = append(merged, override)
+		}
+	}
+	return merged
 }
 
 type Config struct {
+	// Root is false in .noteo.yml of a subdirectory which overrides settings of the repository above instead of
+	// being a root of a nested repository
+	Root   *bool  `yaml:"root"`
 	Editor string `yaml:"editor"`
+	// DateFormat is a default date format used by ls, e.g. iso8601 or format:%Y-%m-%d
+	DateFormat string `yaml:"date-format"`
+	// Timezone is an IANA time zone name used by ls to render dates, e.g. Europe/Warsaw
+	Timezone string       `yaml:"timezone"`
+	Tags     TagsConfig   `yaml:"tags"`
+	Agenda   AgendaConfig `yaml:"agenda"`
+	Fmt      FmtConfig    `yaml:"fmt"`
+	// Extensions are extra extensions of note files, e.g. [.markdown, .txt]. Files with *.md extension are always notes.
+	Extensions []string `yaml:"extensions"`
+	// Views are named sets of ls flags, e.g. "inbox: {tag: [inbox], sort-by-created: true}"
+	Views yaml.MapSlice `yaml:"views"`
+}
+
+// View is a named set of ls flags, which are given in the order of the config file
+type View struct {
+	Name  string
+	Flags []ViewFlag
+}
+
+// ViewFlag is a long name of ls flag without dashes, e.g. tag, and its values. Flag with many values is given
+// many times.
+type ViewFlag struct {
+	Name   string
+	Values []string
+}
+
+// ViewList returns views in the order of the config file
+func (r *Config) ViewList() ([]View, error) {
+	views := make([]View, 0, len(r.Views))
+	for _, item := range r.Views {
+		view := View{Name: fmt.Sprint(item.Key)}
+		flags, ok := item.Value.(yaml.MapSlice)
+		if !ok && item.Value != nil {
+			return nil, fmt.Errorf("view %s must be a mapping of ls flags to values", view.Name)
+		}
+		for _, flag := range flags {
+			f := ViewFlag{Name: fmt.Sprint(flag.Key)}
+			switch v := flag.Value.(type) {
+			case []interface{}:
+				for _, value := range v {
+					f.Values = append(f.Values, fmt.Sprint(value))
+				}
+			case yaml.MapSlice:
+				return nil, fmt.Errorf("view %s: value of %s must be a scalar or a list", view.Name, f.Name)
+			case nil:
+				return nil, fmt.Errorf("view %s: %s has no value", view.Name, f.Name)
+			default:
+				f.Values = []string{fmt.Sprint(v)}
+			}
+			view.Flags = append(view.Flags, f)
+		}
+		views = append(views, view)
+	}
+	return views, nil
+}
+
+// View returns the view with given name
+func (r *Config) View(name string) (View, error) {
+	views, err := r.ViewList()
+	if err != nil {
+		return View{}, err
+	}
+	for _, v := range views {
+		if v.Name == name {
+			return v, nil
+		}
+	}
+	return View{}, fmt.Errorf("unknown view: %s. Views can be defined in .noteo.yml, e.g. "+
+		"views: {inbox: {tag: [inbox], sort-by-created: true}}", name)
+}
+
+// NoteExtensions returns extensions of note files, starting with .md
+func (r *Config) NoteExtensions() []string {
+	extensions := []string{".md"}
+	for _, ext := range r.Extensions {
+		ext = strings.TrimSpace(ext)
+		if ext == "" {
+			continue
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		extensions = append(extensions, ext)
+	}
+	return extensions
+}
+
+// FmtConfig defines canonical form of front matter used by fmt command
+type FmtConfig struct {
+	// DateLayout is a layout of Created: rfc3339 (default), rfc2822, iso8601 or unixdate
+	DateLayout string `yaml:"date-layout"`
+	// Tags is either string (default), which is space separated, or list
+	Tags string `yaml:"tags"`
+	// KeyOrder lists keys which go first, e.g. [Created, Tags]. Other keys keep their order.
+	KeyOrder []string `yaml:"key-order"`
+	// KeyCase is preserve (default), lower or title
+	KeyCase string `yaml:"key-case"`
+}
+
+func (r *Config) FrontMatterFormat() (note.Format, error) {
+	f := note.Format{KeyOrder: r.Fmt.KeyOrder}
+	var err error
+	if f.DateLayout, err = note.ParseDateLayout(r.Fmt.DateLayout); err != nil {
+		return f, err
+	}
+	switch strings.ToLower(r.Fmt.Tags) {
+	case "", "string":
+	case "list":
+		f.TagsAsList = true
+	default:
+		return f, fmt.Errorf("unsupported fmt.tags: %s. Supported values are string and list", r.Fmt.Tags)
+	}
+	switch strings.ToLower(r.Fmt.KeyCase) {
+	case "", "preserve":
+	case "lower":
+		f.KeyCase = note.LowerCase
+	case "title":
+		f.KeyCase = note.TitleCase
+	default:
+		return f, fmt.Errorf("unsupported fmt.key-case: %s. Supported values are preserve, lower and title", r.Fmt.KeyCase)
+	}
+	return f, nil
+}
+
+type AgendaConfig struct {
+	// Tags are names of tags with dates listed in agenda. Default is deadline and scheduled
+	Tags []string `yaml:"tags"`
+}
+
+func (r *Config) AgendaTags() []string {
+	if len(r.Agenda.Tags) == 0 {
+		return []string{"deadline", "scheduled"}
+	}
+	return r.Agenda.Tags
+}
+
+type TagsConfig struct {
+	// Strict rejects tags not declared in vocabulary or aliases
+	Strict bool `yaml:"strict"`
+	// Vocabulary maps tag name to optional value type, e.g. "priority: number"
+	Vocabulary map[string]string `yaml:"vocabulary"`
+	// Aliases maps alias to tag name, e.g. "bugs: bug"
+	Aliases map[string]string `yaml:"aliases"`
 }
 
 func (r *Config) EditorCommand() string {
 	return r.Editor
 }
+
+func (r *Config) Vocabulary() (*tag.Vocabulary, error) {
+	v := &tag.Vocabulary{
+		Strict:  r.Tags.Strict,
+		Names:   map[string]tag.ValueType{},
+		Aliases: map[string]string{},
+	}
+	for name, valueType := range r.Tags.Vocabulary {
+		t, err := tag.ParseValueType(valueType)
+		if err != nil {
+			return nil, fmt.Errorf("invalid vocabulary entry %s: %v", name, err)
+		}
+		v.Names[name] = t
+	}
+	for alias, name := range r.Tags.Aliases {
+		if _, ok := v.Names[name]; !ok && r.Tags.Strict {
+			return nil, fmt.Errorf("alias %s points to tag %s which is not in the vocabulary", alias, name)
+		}
+		v.Aliases[alias] = name
+	}
+	return v, nil
+}
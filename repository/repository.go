@@ -9,14 +9,18 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/google/uuid"
 	godiacritics "gopkg.in/Regis24GmbH/go-diacritics.v2"
 
+	"github.com/elgopher/noteo/agenda"
 	"github.com/elgopher/noteo/note"
 	"github.com/elgopher/noteo/parser"
+	"github.com/elgopher/noteo/seq"
 	"github.com/elgopher/noteo/tag"
+	"github.com/elgopher/noteo/task"
 )
 
 func Init(dir string) (string, error) {
@@ -29,6 +33,39 @@ func Init(dir string) (string, error) {
 	if ok && e.IsNotRepository() {
 		return file, os.WriteFile(file, []byte(`# This is a Noteo configuration for repository (YAML format)
 # editor: vim +
+# date-format: iso8601
+# timezone: Europe/Warsaw
+# naming: title
+# color: auto
+# ls:
+#   output: table
+#   columns: [file, beginning, modified, tags]
+# extensions: [.markdown, .txt]
+# add:
+#   tags: [journal]
+#   template: template.md
+# views:
+#   inbox:
+#     tag: [inbox]
+#     no-tag: [done]
+#     sort-by-created: true
+#     limit: 20
+#     output: table=file,beginning,created
+# agenda:
+#   tags: [deadline, scheduled]
+# tags:
+#   strict: false
+#   vocabulary:
+#     bug:
+#     priority: number
+#     deadline: date
+#   aliases:
+#     bugs: bug
+# fmt:
+#   date-layout: rfc3339
+#   tags: string
+#   key-order: [Created, Tags]
+#   key-case: title
 `), 0664)
 	}
 	return file, err
@@ -51,8 +88,45 @@ type Repository struct {
 	dir  string
 }
 
+// ConfigFile returns path of the repository config, .noteo.yml in the root directory
+func (r *Repository) ConfigFile() string {
+	return dotFile(r.root)
+}
+
+// ConfigFiles returns .noteo.yml files from the working directory up to the root, nearest first. Files in
+// subdirectories, which have "root: false", override settings of the root config for notes below them.
+func (r *Repository) ConfigFiles() []string {
+	var files []string
+	for dir := r.dir; ; dir = filepath.Dir(dir) {
+		if _, err := os.Stat(dotFile(dir)); err == nil {
+			files = append(files, dotFile(dir))
+		}
+		if dir == r.root || dir == filepath.Dir(dir) {
+			return files
+		}
+	}
+}
+
+// Naming is a strategy of generating file names of new notes
+type Naming string
+
+const (
+	NamingTitle     Naming = "title"      // first line of the note, e.g. shopping-list.md
+	NamingDateTitle Naming = "date-title" // date followed by the title, e.g. 2020-09-05-shopping-list.md
+	NamingTimestamp Naming = "timestamp"  // date and time, e.g. 20200905123005.md
+)
+
 func (r *Repository) Add(text string) (string, error) {
-	name, err := generateFilename(text)
+	return r.AddNamed(text, NamingTitle)
+}
+
+// AddNamed adds a note with file name generated using given naming strategy
+func (r *Repository) AddNamed(text string, naming Naming) (string, error) {
+	vocabulary, err := r.vocabulary()
+	if err != nil {
+		return "", err
+	}
+	name, err := generateFilename(text, naming, time.Now())
 	if err != nil {
 		return "", err
 	}
@@ -72,7 +146,38 @@ func (r *Repository) Add(text string) (string, error) {
 	if err != nil {
 		return file, err
 	}
-	return rel, os.WriteFile(file, []byte(text), 0664)
+	text, err = normalizeTags(file, text, vocabulary)
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(file, []byte(text), 0664); err != nil {
+		return rel, err
+	}
+	return rel, nil
+}
+
+// normalizeTags returns text with tags normalized by vocabulary. Malformed front matter is kept as is, unless
+// vocabulary is strict.
+func normalizeTags(file, text string, vocabulary *tag.Vocabulary) (string, error) {
+	n := note.NewFromText(file, text)
+	if _, err := n.Tags(); err != nil {
+		if vocabulary.Strict {
+			return "", err
+		}
+		return text, nil
+	}
+	changed := false
+	err := n.MapTags(func(t tag.Tag) (tag.Tag, error) {
+		normalized, err := vocabulary.Normalize(t)
+		if normalized != t {
+			changed = true
+		}
+		return normalized, err
+	})
+	if err != nil || !changed {
+		return text, err
+	}
+	return n.Content()
 }
 
 func (r *Repository) TagFileWith(file string, newTag string) (bool, error) {
@@ -80,12 +185,18 @@ func (r *Repository) TagFileWith(file string, newTag string) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	if filepath.Ext(file) != ".md" {
-		return false, fmt.Errorf("%s has no *.md extension", file)
+	vocabulary, err := r.vocabulary()
+	if err != nil {
+		return false, err
 	}
-	if !filepath.IsAbs(file) {
-		file = filepath.Join(r.dir, file)
+	t, err = vocabulary.Normalize(t)
+	if err != nil {
+		return false, err
 	}
+	if err = r.CheckExtension(file); err != nil {
+		return false, err
+	}
+	file = r.path(file)
 	n := note.New(file)
 	if err := n.SetTag(t); err != nil {
 		return false, err
@@ -98,9 +209,15 @@ func (r *Repository) UntagFile(file string, tagToRemove string) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	if filepath.Ext(file) != ".md" {
-		return false, fmt.Errorf("%s has no *.md extension", file)
+	vocabulary, err := r.vocabulary()
+	if err != nil {
+		return false, err
+	}
+	t = vocabulary.Canonical(t)
+	if err = r.CheckExtension(file); err != nil {
+		return false, err
 	}
+	file = r.path(file)
 	n := note.New(file)
 	if err := n.RemoveTag(t); err != nil {
 		return false, err
@@ -113,9 +230,10 @@ func (r *Repository) UntagFileRegex(file string, tagRegexToRemove string) (bool,
 	if err != nil {
 		return false, err
 	}
-	if filepath.Ext(file) != ".md" {
-		return false, fmt.Errorf("%s has no *.md extension", file)
+	if err = r.CheckExtension(file); err != nil {
+		return false, err
 	}
+	file = r.path(file)
 	n := note.New(file)
 	if err := n.RemoveTagRegex(regex); err != nil {
 		return false, err
@@ -123,6 +241,48 @@ func (r *Repository) UntagFileRegex(file string, tagRegexToRemove string) (bool,
 	return n.Save()
 }
 
+// Done marks note as done. Agenda date tags of recurring notes are moved to the next occurrence, other notes
+// are tagged with "done". Returns tags which were set.
+func (r *Repository) Done(file string) ([]tag.Tag, error) {
+	config, err := r.Config()
+	if err != nil {
+		return nil, err
+	}
+	file = r.path(file)
+	n := note.New(file)
+	tags, err := agenda.Done(n, config.AgendaTags())
+	if err != nil {
+		return nil, err
+	}
+	if _, err = n.Save(); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// CompleteTask ticks off the Markdown task list item in a given line of the file
+func (r *Repository) CompleteTask(file string, line int) error {
+	file = r.path(file)
+	n := note.New(file)
+	body, err := n.Body()
+	if err != nil {
+		return err
+	}
+	bodyLine, err := n.BodyLine()
+	if err != nil {
+		return err
+	}
+	newBody, err := task.Complete(body, bodyLine, line)
+	if err != nil {
+		return err
+	}
+	n.SetBody(newBody)
+	_, err = n.Save()
+	return err
+}
+
+// Move is a channel adapter of MoveSeq. Success channel receives false when the file could not be moved and true
+// after all links were updated.
 func (r *Repository) Move(ctx context.Context, source, target string) (<-chan *note.Note, <-chan bool, <-chan error) {
 	updated := make(chan *note.Note)
 	errs := make(chan error)
@@ -132,53 +292,73 @@ func (r *Repository) Move(ctx context.Context, source, target string) (<-chan *n
 		defer close(updated)
 		defer close(errs)
 		defer close(success)
-		target, err := addSourceFileToTargetIfTargetIsDirectory(source, target)
+		updatedNotes, err := r.MoveSeq(ctx, source, target)
 		if err != nil {
-			errs <- err
-			return
-		}
-		if err := os.Rename(source, target); err != nil {
 			errs <- err
 			success <- false
 			return
 		}
-		defer func() {
-			success <- true
-		}()
-		notes, notesErr := r.AllNotes(ctx)
-		for {
+		updatedNotes(func(n *note.Note, err error) bool {
+			if err != nil {
+				select {
+				case errs <- err:
+					return true
+				case <-ctx.Done():
+					return false
+				}
+			}
 			select {
+			case updated <- n:
+				return true
 			case <-ctx.Done():
-				return
-			case err, ok := <-notesErr:
-				if !ok {
-					continue
-				}
-				errs <- err
-			case note, ok := <-notes:
-				if !ok {
-					return
-				}
-				if err := note.UpdateLink(source, target); err != nil {
-					errs <- err
-					continue
-				}
-				ok, err = note.Save()
-				if err != nil {
-					errs <- err
-					continue
-				}
-				if ok {
-					updated <- note
-				}
+				return false
 			}
-		}
+		})
+		success <- true
 	}()
 	return updated, success, errs
 }
 
-func addSourceFileToTargetIfTargetIsDirectory(source, target string) (string, error) {
-	targetStat, err := os.Lstat(target)
+// MoveSeq moves the file (or directory) and updates links in all notes of the repository, including relative links
+// of the moved note, before returning, so returned Seq does not have to be iterated. Seq yields updated notes and
+// errors of notes which could not be updated. Returns error when the file could not be moved.
+func (r *Repository) MoveSeq(ctx context.Context, source, target string) (seq.Seq[*note.Note], error) {
+	target, err := r.addSourceFileToTargetIfTargetIsDirectory(source, target)
+	if err != nil {
+		return nil, err
+	}
+	if err = os.Rename(r.path(source), r.path(target)); err != nil {
+		return nil, err
+	}
+	return seq.Eager(func(yield func(*note.Note, error) bool) {
+		r.AllNotesSeq(ctx)(func(n *note.Note, err error) bool {
+			if err != nil {
+				return yield(nil, err)
+			}
+			if filepath.Clean(n.Path()) == filepath.Clean(filepath.FromSlash(target)) {
+				// relative links of the moved note must point to the same files from the new directory
+				if err = n.RelocateLinks(source); err != nil {
+					return yield(nil, err)
+				}
+			}
+			if err = n.UpdateLink(source, target); err != nil {
+				return yield(nil, err)
+			}
+			saved, err := n.Save()
+			if err != nil {
+				return yield(nil, err)
+			}
+			if saved {
+				n.ReleaseBody()
+				return yield(n, nil)
+			}
+			return true
+		})
+	}), nil
+}
+
+func (r *Repository) addSourceFileToTargetIfTargetIsDirectory(source, target string) (string, error) {
+	targetStat, err := os.Lstat(r.path(target))
 	if err != nil && !os.IsNotExist(err) {
 		return target, err
 	}
@@ -190,84 +370,167 @@ func addSourceFileToTargetIfTargetIsDirectory(source, target string) (string, er
 	return target, nil
 }
 
+// path joins relative file with the working directory of the repository, so the file can be accessed regardless
+// of the current working directory of the process
+func (r *Repository) path(file string) string {
+	if filepath.IsAbs(file) {
+		return file
+	}
+	return filepath.Join(r.dir, file)
+}
+
+// Notes is a channel adapter of NotesSeq
 func (r *Repository) Notes(ctx context.Context) (<-chan *note.Note, <-chan error) {
-	return r.notes(ctx, r.dir)
+	return seq.Channels(ctx, r.NotesSeq(ctx))
 }
 
+// AllNotes is a channel adapter of AllNotesSeq
 func (r *Repository) AllNotes(ctx context.Context) (<-chan *note.Note, <-chan error) {
+	return seq.Channels(ctx, r.AllNotesSeq(ctx))
+}
+
+// NotesSeq yields notes in the working directory and its subdirectories. Paths are relative to the working directory.
+func (r *Repository) NotesSeq(ctx context.Context) seq.Seq[*note.Note] {
+	return r.notes(ctx, r.dir)
+}
+
+// AllNotesSeq yields all notes in the repository. Paths are relative to the working directory.
+func (r *Repository) AllNotesSeq(ctx context.Context) seq.Seq[*note.Note] {
 	return r.notes(ctx, r.root)
 }
 
-func (r *Repository) notes(ctx context.Context, dir string) (<-chan *note.Note, <-chan error) {
-	names := make(chan *note.Note)
-	errs := make(chan error)
-	go func() {
-		defer close(names)
-		defer close(errs)
-		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+var errStopped = errors.New("stopped")
+
+func (r *Repository) notes(ctx context.Context, dir string) seq.Seq[*note.Note] {
+	return func(yield func(*note.Note, error) bool) {
+		w, err := r.newWalker()
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		err = w.walk(ctx, dir, func(path string, info os.FileInfo) error {
+			if !w.isNote(path, info) {
+				return nil
+			}
+			relPath, err := filepath.Rel(r.dir, path)
 			if err != nil {
 				return err
 			}
-			select {
-			case <-ctx.Done():
-				return fmt.Errorf("cancelled")
-			default:
-				if strings.HasSuffix(path, ".md") {
-					relPath, err := filepath.Rel(r.dir, path)
-					if err != nil {
-						return err
-					}
-					names <- note.NewWithModified(relPath, info.ModTime())
-				}
+			if !yield(note.NewInDirWithModified(r.dir, relPath, info.ModTime()), nil) {
+				return errStopped
 			}
 			return nil
 		})
+		if err != nil && err != errStopped {
+			yield(nil, err)
+		}
+	}
+}
+
+// NonNoteFilesSeq yields paths of files in the repository which are not notes. Hidden files, such as .noteo.yml,
+// and files skipped when looking for notes, such as .git directory, are not yielded. Paths are relative to the
+// working directory.
+func (r *Repository) NonNoteFilesSeq(ctx context.Context) seq.Seq[string] {
+	return func(yield func(string, error) bool) {
+		w, err := r.newWalker()
 		if err != nil {
-			errs <- err
+			yield("", err)
+			return
 		}
-	}()
-	return names, errs
+		err = w.walk(ctx, r.root, func(path string, info os.FileInfo) error {
+			if info.IsDir() || strings.HasPrefix(info.Name(), ".") || w.isNote(path, info) {
+				return nil
+			}
+			relPath, err := filepath.Rel(r.dir, path)
+			if err != nil {
+				return err
+			}
+			if !yield(relPath, nil) {
+				return errStopped
+			}
+			return nil
+		})
+		if err != nil && err != errStopped {
+			yield("", err)
+		}
+	}
 }
 
+// Tags is a channel adapter of TagsSeq
 func (r *Repository) Tags(ctx context.Context) (<-chan tag.Tag, <-chan error) {
-	tags := make(chan tag.Tag)
-	errs := make(chan error)
-	notes, notesErrs := r.Notes(ctx)
-	go func() {
-		defer close(tags)
-		defer close(errs)
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case err, ok := <-notesErrs:
-				if !ok {
-					return
-				}
-				errs <- err
-			case note, ok := <-notes:
-				if !ok {
-					return
-				}
-				noteTags, err := note.Tags()
-				if err != nil {
-					errs <- err
-					continue
-				}
-				for _, t := range noteTags {
-					tags <- t
+	return seq.Channels(ctx, r.TagsSeq(ctx))
+}
+
+// TagsSeq yields tags of all notes in the working directory and its subdirectories. Tags are not unique.
+func (r *Repository) TagsSeq(ctx context.Context) seq.Seq[tag.Tag] {
+	return func(yield func(tag.Tag, error) bool) {
+		r.NotesSeq(ctx)(func(n *note.Note, err error) bool {
+			if err != nil {
+				return yield(tag.Tag{}, err)
+			}
+			noteTags, err := n.Tags()
+			if err != nil {
+				return yield(tag.Tag{}, err)
+			}
+			for _, t := range noteTags {
+				if !yield(t, nil) {
+					return false
 				}
 			}
-		}
-	}()
-	return tags, errs
+			return true
+		})
+	}
 }
 
+// Config returns settings merged from ConfigFiles. Settings of files with "root: false" override the ones given in
+// the directories above, see Config.merge.
 func (r *Repository) Config() (*Config, error) {
-	return parse(dotFile(r.root))
+	files := r.ConfigFiles()
+	config := &Config{}
+	for i := len(files) - 1; i >= 0; i-- {
+		if err := config.merge(files[i]); err != nil {
+			return nil, err
+		}
+	}
+	return config, nil
+}
+
+// CheckExtension returns error when file has none of configured note extensions
+func (r *Repository) CheckExtension(file string) error {
+	config, err := r.Config()
+	if err != nil {
+		return err
+	}
+	extensions := config.NoteExtensions()
+	if !hasExtension(file, extensions) {
+		return fmt.Errorf("%s has no *%s extension", file, strings.Join(extensions, ", *"))
+	}
+	return nil
 }
 
-func generateFilename(text string) (string, error) {
+func (r *Repository) vocabulary() (*tag.Vocabulary, error) {
+	config, err := r.Config()
+	if err != nil {
+		return nil, err
+	}
+	return config.Vocabulary()
+}
+
+func generateFilename(text string, naming Naming, now time.Time) (string, error) {
+	switch naming {
+	case NamingTitle:
+		return title(text)
+	case NamingDateTitle:
+		name, err := title(text)
+		return now.Format("2006-01-02") + "-" + name, err
+	case NamingTimestamp:
+		return now.Format("20060102150405"), nil
+	default:
+		return "", fmt.Errorf("unsupported naming: %s. Supported values are title, date-title and timestamp", naming)
+	}
+}
+
+func title(text string) (string, error) {
 	_, body, err := parser.Parse(strings.NewReader(text))
 	if err != nil {
 		return "", err
@@ -326,25 +589,43 @@ func generateUUID() string {
 	return strings.ReplaceAll(uuid.New().String(), "-", "")
 }
 
+// configFileName is the name of repository config, which marks the root of the repository
+const configFileName = ".noteo.yml"
+
 func dotFile(dir string) string {
-	return filepath.Join(dir, ".noteo.yml")
+	return filepath.Join(dir, configFileName)
 }
 
+// findRoot returns the nearest directory with .noteo.yml file, skipping files with "root: false", which override
+// settings of the repository above.
 func findRoot(dir string) (string, error) {
-	f := dotFile(dir)
-	_, err := os.Stat(f)
-	if err == nil {
-		return dir, nil
-	}
-	if !os.IsNotExist(err) {
-		return "", err
-	} else {
+	for {
+		isRoot, err := isRootDir(dir)
+		if err != nil {
+			return "", err
+		}
+		if isRoot {
+			return dir, nil
+		}
 		parent := filepath.Dir(dir)
 		if dir == parent {
 			return "", repoError("repo not initialized: .noteo file note found")
 		}
-		return findRoot(parent)
+		dir = parent
+	}
+}
+
+// isRootDir returns true when dir has .noteo.yml file without "root: false". Malformed file is treated as a root,
+// so the error is reported when the config is used.
+func isRootDir(dir string) (bool, error) {
+	file := dotFile(dir)
+	if _, err := os.Stat(file); os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
 	}
+	config, err := parse(file)
+	return err != nil || config.Root == nil || *config.Root, nil
 }
 
 type repoError string
@@ -12,6 +12,7 @@ import (
 
 	"github.com/elgopher/noteo/note"
 	"github.com/elgopher/noteo/repository"
+	"github.com/elgopher/noteo/seq"
 )
 
 func TestRepository_Add(t *testing.T) {
@@ -116,6 +117,132 @@ second`,
 	})
 }
 
+func TestRepository_AddNamed(t *testing.T) {
+	t.Run("should prefix title with date", func(t *testing.T) {
+		_, repo := repo(t)
+		// when
+		file, err := repo.AddNamed("foo bar", repository.NamingDateTitle)
+		// then
+		require.NoError(t, err)
+		assert.Regexp(t, `^\d{4}-\d{2}-\d{2}-foo-bar\.md$`, file)
+	})
+
+	t.Run("should use timestamp", func(t *testing.T) {
+		_, repo := repo(t)
+		// when
+		file, err := repo.AddNamed("foo bar", repository.NamingTimestamp)
+		// then
+		require.NoError(t, err)
+		assert.Regexp(t, `^\d{14}\.md$`, file)
+	})
+
+	t.Run("should reject unsupported naming", func(t *testing.T) {
+		_, repo := repo(t)
+		// when
+		_, err := repo.AddNamed("foo bar", "unknown")
+		// then
+		assert.Error(t, err)
+	})
+}
+
+func TestRepository_TagFileWith(t *testing.T) {
+	t.Run("should replace alias with tag name from vocabulary", func(t *testing.T) {
+		dir, repo := repoWithConfig(t, "tags:\n  aliases:\n    bugs: bug\n")
+		file := filepath.Join(dir, "note.md")
+		writeFile(t, file, "text")
+		// when
+		updated, err := repo.TagFileWith(file, "bugs")
+		// then
+		require.NoError(t, err)
+		assert.True(t, updated)
+		assertFileEquals(t, file, "---\nTags: bug\n---\ntext")
+	})
+
+	t.Run("should reject unknown tag in strict mode", func(t *testing.T) {
+		dir, repo := repoWithConfig(t, "tags:\n  strict: true\n  vocabulary:\n    bug:\n")
+		file := filepath.Join(dir, "note.md")
+		writeFile(t, file, "text")
+		// when
+		_, err := repo.TagFileWith(file, "unknown")
+		// then
+		assert.Error(t, err)
+		assertFileEquals(t, file, "text")
+	})
+
+	t.Run("should reject tag with value of wrong type", func(t *testing.T) {
+		dir, repo := repoWithConfig(t, "tags:\n  vocabulary:\n    priority: number\n")
+		file := filepath.Join(dir, "note.md")
+		writeFile(t, file, "text")
+		// when
+		_, err := repo.TagFileWith(file, "priority:high")
+		// then
+		assert.Error(t, err)
+	})
+
+	t.Run("should reject file without note extension", func(t *testing.T) {
+		dir, repo := repo(t)
+		file := filepath.Join(dir, "note.txt")
+		writeFile(t, file, "text")
+		// when
+		_, err := repo.TagFileWith(file, "tag")
+		// then
+		assert.EqualError(t, err, file+" has no *.md extension")
+	})
+
+	t.Run("should tag file with extension from config", func(t *testing.T) {
+		dir, repo := repoWithConfig(t, "extensions: [.txt]\n")
+		file := filepath.Join(dir, "note.txt")
+		writeFile(t, file, "text")
+		// when
+		updated, err := repo.TagFileWith(file, "tag")
+		// then
+		require.NoError(t, err)
+		assert.True(t, updated)
+		assertFileEquals(t, file, "---\nTags: tag\n---\ntext")
+	})
+}
+
+func TestRepository_AddWithVocabulary(t *testing.T) {
+	t.Run("should replace alias", func(t *testing.T) {
+		dir, repo := repoWithConfig(t, "tags:\n  aliases:\n    bugs: bug\n")
+		// when
+		file, err := repo.Add("---\nTags: bugs idea\n---\ntext")
+		// then
+		require.NoError(t, err)
+		assertFileEquals(t, filepath.Join(dir, file), "---\nTags: bug idea\n---\ntext")
+	})
+
+	t.Run("should not add note with unknown tag in strict mode", func(t *testing.T) {
+		dir, repo := repoWithConfig(t, "tags:\n  strict: true\n")
+		// when
+		_, err := repo.Add("---\nTags: unknown\n---\ntext")
+		// then
+		assert.Error(t, err)
+		assert.NoFileExists(t, filepath.Join(dir, "text.md"))
+	})
+
+	t.Run("should not add note with malformed front matter in strict mode", func(t *testing.T) {
+		dir, repo := repoWithConfig(t, "tags:\n  strict: true\n")
+		// when
+		_, err := repo.Add("---\nTags: [\n---\ntext")
+		// then
+		assert.Error(t, err)
+		files, err := filepath.Glob(filepath.Join(dir, "*.md"))
+		require.NoError(t, err)
+		assert.Empty(t, files)
+	})
+
+	t.Run("should add note with malformed front matter", func(t *testing.T) {
+		dir, repo := repoWithConfig(t, "tags:\n  aliases:\n    bugs: bug\n")
+		text := "---\nTags: [\n---\ntext"
+		// when
+		file, err := repo.Add(text)
+		// then
+		require.NoError(t, err)
+		assertFileEquals(t, filepath.Join(dir, file), text)
+	})
+}
+
 func TestRepository_Move(t *testing.T) {
 	t.Run("should rename file", func(t *testing.T) {
 		dir, repo := repo(t)
@@ -175,6 +302,307 @@ func TestRepository_Move(t *testing.T) {
 	})
 }
 
+func TestRepository_MoveSeq(t *testing.T) {
+	t.Run("should move file and yield updated notes", func(t *testing.T) {
+		dir, repo := repo(t)
+		writeFile(t, filepath.Join(dir, "source.md"), "source")
+		writeFile(t, filepath.Join(dir, "link.md"), "[link](source.md)")
+		writeFile(t, filepath.Join(dir, "other.md"), "other")
+		// when
+		updated, err := repo.MoveSeq(context.Background(), "source.md", "target.md")
+		// then
+		require.NoError(t, err)
+		assert.FileExists(t, filepath.Join(dir, "target.md"))
+		updatedNotes, errs := seq.Collect(updated)
+		assert.Empty(t, errs)
+		require.Len(t, updatedNotes, 1)
+		assert.Equal(t, "link.md", updatedNotes[0].Path())
+		assertFileEquals(t, filepath.Join(dir, "link.md"), "[link](target.md)")
+	})
+
+	t.Run("should update links of note moved to other directory", func(t *testing.T) {
+		dir, repo := repo(t)
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "sub"), os.ModePerm))
+		writeFile(t, filepath.Join(dir, "source.md"), "[other](other.md)")
+		writeFile(t, filepath.Join(dir, "other.md"), "other")
+		writeFile(t, filepath.Join(dir, "sub", "link.md"), "[link](../source.md)")
+		// when
+		updated, err := repo.MoveSeq(context.Background(), "source.md", "sub")
+		// then
+		require.NoError(t, err)
+		_, errs := seq.Collect(updated)
+		assert.Empty(t, errs)
+		assertFileEquals(t, filepath.Join(dir, "sub", "source.md"), "[other](../other.md)")
+		assertFileEquals(t, filepath.Join(dir, "sub", "link.md"), "[link](source.md)")
+	})
+
+	t.Run("should return error when file does not exist", func(t *testing.T) {
+		_, repo := repo(t)
+		// when
+		_, err := repo.MoveSeq(context.Background(), "missing.md", "target.md")
+		// then
+		assert.Error(t, err)
+	})
+}
+
+func TestRepository_NotesSeq(t *testing.T) {
+	t.Run("should yield notes in subdirectories", func(t *testing.T) {
+		dir, repo := repo(t)
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), os.ModePerm))
+		writeFile(t, filepath.Join(dir, "a.md"), "a")
+		writeFile(t, filepath.Join(dir, "sub", "b.md"), "b")
+		writeFile(t, filepath.Join(dir, "c.txt"), "c")
+		// when
+		notes, errs := seq.Collect(repo.NotesSeq(context.Background()))
+		// then
+		assert.Empty(t, errs)
+		var paths []string
+		for _, n := range notes {
+			paths = append(paths, n.Path())
+		}
+		assert.ElementsMatch(t, []string{"a.md", filepath.Join("sub", "b.md")}, paths)
+	})
+
+	t.Run("should skip hidden directories and directories with note extension", func(t *testing.T) {
+		dir, repo := repo(t)
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, ".git"), os.ModePerm))
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "dir.md"), os.ModePerm))
+		writeFile(t, filepath.Join(dir, ".git", "a.md"), "a")
+		writeFile(t, filepath.Join(dir, "dir.md", "b.md"), "b")
+		// when
+		notes, errs := seq.Collect(repo.NotesSeq(context.Background()))
+		// then
+		assert.Empty(t, errs)
+		assert.Equal(t, []string{filepath.Join("dir.md", "b.md")}, paths(notes))
+	})
+
+	t.Run("should skip paths from .noteoignore files", func(t *testing.T) {
+		dir, repo := repo(t)
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "node_modules", "lib"), os.ModePerm))
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), os.ModePerm))
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, ".github"), os.ModePerm))
+		writeFile(t, filepath.Join(dir, repository.IgnoreFile), "node_modules/\ndraft-*.md\n!.github/\n")
+		writeFile(t, filepath.Join(dir, "sub", repository.IgnoreFile), "/a.md\n!draft-keep.md\n")
+		writeFile(t, filepath.Join(dir, "node_modules", "lib", "README.md"), "readme")
+		writeFile(t, filepath.Join(dir, "draft-1.md"), "draft")
+		writeFile(t, filepath.Join(dir, "a.md"), "a")
+		writeFile(t, filepath.Join(dir, "sub", "a.md"), "a")
+		writeFile(t, filepath.Join(dir, "sub", "draft-keep.md"), "draft")
+		writeFile(t, filepath.Join(dir, ".github", "issue.md"), "issue")
+		// when
+		notes, errs := seq.Collect(repo.NotesSeq(context.Background()))
+		// then
+		assert.Empty(t, errs)
+		assert.ElementsMatch(t, []string{"a.md", filepath.Join("sub", "draft-keep.md"), filepath.Join(".github", "issue.md")},
+			paths(notes))
+	})
+
+	t.Run("should use .noteoignore files from parent directories of working directory", func(t *testing.T) {
+		dir, _ := repo(t)
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), os.ModePerm))
+		writeFile(t, filepath.Join(dir, repository.IgnoreFile), "a.md\n")
+		writeFile(t, filepath.Join(dir, "sub", "a.md"), "a")
+		writeFile(t, filepath.Join(dir, "sub", "b.md"), "b")
+		repo, err := repository.ForWorkDir(filepath.Join(dir, "sub"))
+		require.NoError(t, err)
+		// when
+		notes, errs := seq.Collect(repo.NotesSeq(context.Background()))
+		// then
+		assert.Empty(t, errs)
+		assert.Equal(t, []string{"b.md"}, paths(notes))
+	})
+
+	t.Run("should yield files with extensions from config", func(t *testing.T) {
+		dir, repo := repoWithConfig(t, "extensions: [.markdown, txt]\n")
+		writeFile(t, filepath.Join(dir, "a.md"), "a")
+		writeFile(t, filepath.Join(dir, "b.markdown"), "b")
+		writeFile(t, filepath.Join(dir, "c.txt"), "c")
+		writeFile(t, filepath.Join(dir, "d.html"), "d")
+		// when
+		notes, errs := seq.Collect(repo.NotesSeq(context.Background()))
+		// then
+		assert.Empty(t, errs)
+		assert.ElementsMatch(t, []string{"a.md", "b.markdown", "c.txt"}, paths(notes))
+	})
+
+	t.Run("should follow symbolic links to directories without loops", func(t *testing.T) {
+		dir, repo := repo(t)
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, ".archive"), os.ModePerm))
+		writeFile(t, filepath.Join(dir, ".archive", "linked.md"), "linked")
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), os.ModePerm))
+		writeFile(t, filepath.Join(dir, "sub", "a.md"), "a")
+		require.NoError(t, os.Symlink(filepath.Join(dir, ".archive"), filepath.Join(dir, "linked")))
+		require.NoError(t, os.Symlink(dir, filepath.Join(dir, "sub", "loop")))
+		require.NoError(t, os.Symlink(filepath.Join(dir, "missing"), filepath.Join(dir, "broken.md")))
+		// when
+		notes, errs := seq.Collect(repo.NotesSeq(context.Background()))
+		// then
+		assert.Empty(t, errs)
+		assert.ElementsMatch(t, []string{filepath.Join("linked", "linked.md"), filepath.Join("sub", "a.md")},
+			paths(notes))
+	})
+
+	t.Run("should skip symbolic links outside the repository", func(t *testing.T) {
+		dir, repo := repo(t)
+		outside := t.TempDir()
+		writeFile(t, filepath.Join(outside, "linked.md"), "linked")
+		writeFile(t, filepath.Join(dir, "a.md"), "a")
+		require.NoError(t, os.Symlink(outside, filepath.Join(dir, "linked")))
+		require.NoError(t, os.Symlink(filepath.Join(outside, "linked.md"), filepath.Join(dir, "file.md")))
+		// when
+		notes, errs := seq.Collect(repo.NotesSeq(context.Background()))
+		// then
+		assert.Empty(t, errs)
+		assert.Equal(t, []string{"a.md"}, paths(notes))
+	})
+
+	t.Run("should stop when yield returns false", func(t *testing.T) {
+		dir, repo := repo(t)
+		writeFile(t, filepath.Join(dir, "a.md"), "a")
+		writeFile(t, filepath.Join(dir, "b.md"), "b")
+		yielded := 0
+		// when
+		repo.NotesSeq(context.Background())(func(n *note.Note, err error) bool {
+			yielded++
+			return false
+		})
+		// then
+		assert.Equal(t, 1, yielded)
+	})
+
+	t.Run("should yield error when context is cancelled", func(t *testing.T) {
+		dir, repo := repo(t)
+		writeFile(t, filepath.Join(dir, "a.md"), "a")
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		// when
+		notes, errs := seq.Collect(repo.NotesSeq(ctx))
+		// then
+		assert.Empty(t, notes)
+		assert.Equal(t, []error{context.Canceled}, errs)
+	})
+}
+
+func TestRepository_NonNoteFilesSeq(t *testing.T) {
+	dir, repo := repo(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), os.ModePerm))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".git"), os.ModePerm))
+	writeFile(t, filepath.Join(dir, "a.md"), "a")
+	writeFile(t, filepath.Join(dir, "sub", "b.txt"), "b")
+	writeFile(t, filepath.Join(dir, ".git", "config"), "c")
+	writeFile(t, filepath.Join(dir, "sub", "ignored.pdf"), "d")
+	writeFile(t, filepath.Join(dir, repository.IgnoreFile), "*.pdf\n")
+	// when
+	files, errs := seq.Collect(repo.NonNoteFilesSeq(context.Background()))
+	// then
+	assert.Empty(t, errs)
+	assert.Equal(t, []string{filepath.Join("sub", "b.txt")}, files)
+}
+
+func TestRepository_ConfigFiles(t *testing.T) {
+	t.Run("should return config files from working directory up to the root", func(t *testing.T) {
+		dir, _ := repo(t)
+		journal := filepath.Join(dir, "journal")
+		require.NoError(t, os.MkdirAll(filepath.Join(journal, "2024"), os.ModePerm))
+		writeFile(t, filepath.Join(journal, ".noteo.yml"), "root: false\nnaming: date-title\n")
+		repo, err := repository.ForWorkDir(filepath.Join(journal, "2024"))
+		require.NoError(t, err)
+		// when
+		files := repo.ConfigFiles()
+		// then
+		assert.Equal(t, []string{filepath.Join(journal, ".noteo.yml"), filepath.Join(dir, ".noteo.yml")}, files)
+		assert.Equal(t, filepath.Join(dir, ".noteo.yml"), repo.ConfigFile())
+	})
+
+	t.Run("should yield notes from subdirectory with its own config", func(t *testing.T) {
+		dir, _ := repo(t)
+		journal := filepath.Join(dir, "journal")
+		require.NoError(t, os.MkdirAll(journal, os.ModePerm))
+		writeFile(t, filepath.Join(journal, ".noteo.yml"), "root: false\nnaming: date-title\n")
+		writeFile(t, filepath.Join(journal, "a.md"), "a")
+		writeFile(t, filepath.Join(dir, "b.md"), "b")
+		repo, err := repository.ForWorkDir(dir)
+		require.NoError(t, err)
+		// when
+		notes, errs := seq.Collect(repo.NotesSeq(context.Background()))
+		// then
+		assert.Empty(t, errs)
+		assert.ElementsMatch(t, []string{filepath.Join("journal", "a.md"), "b.md"}, paths(notes))
+	})
+}
+
+func TestRepository_Config(t *testing.T) {
+	t.Run("should override root config with config of subdirectory", func(t *testing.T) {
+		dir, _ := repoWithConfig(t, "extensions: [.txt]\ntags:\n  vocabulary:\n    bug:\n"+
+			"views:\n  inbox:\n    tag: [inbox]\n  done:\n    tag: [done]\n")
+		journal := filepath.Join(dir, "journal")
+		require.NoError(t, os.MkdirAll(journal, os.ModePerm))
+		writeFile(t, filepath.Join(journal, ".noteo.yml"), "root: false\nextensions: [.markdown]\n"+
+			"tags:\n  strict: true\n  vocabulary:\n    mood:\nviews:\n  inbox:\n    tag: [journal]\n  week:\n    limit: 7\n")
+		repo, err := repository.ForWorkDir(journal)
+		require.NoError(t, err)
+		// when
+		config, err := repo.Config()
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{".md", ".markdown"}, config.NoteExtensions())
+		assert.True(t, config.Tags.Strict)
+		assert.Contains(t, config.Tags.Vocabulary, "bug")
+		assert.Contains(t, config.Tags.Vocabulary, "mood")
+		views, err := config.ViewList()
+		require.NoError(t, err)
+		require.Len(t, views, 3)
+		assert.Equal(t, "inbox", views[0].Name)
+		assert.Equal(t, []repository.ViewFlag{{Name: "tag", Values: []string{"journal"}}}, views[0].Flags)
+		assert.Equal(t, "done", views[1].Name)
+		assert.Equal(t, "week", views[2].Name)
+	})
+
+	t.Run("should not use config of subdirectory outside of it", func(t *testing.T) {
+		dir, repo := repoWithConfig(t, "tags:\n  strict: false\n")
+		journal := filepath.Join(dir, "journal")
+		require.NoError(t, os.MkdirAll(journal, os.ModePerm))
+		writeFile(t, filepath.Join(journal, ".noteo.yml"), "root: false\ntags:\n  strict: true\n")
+		// when
+		config, err := repo.Config()
+		// then
+		require.NoError(t, err)
+		assert.False(t, config.Tags.Strict)
+	})
+}
+
+func TestRepository_Nested(t *testing.T) {
+	t.Run("should use the nearest root", func(t *testing.T) {
+		dir, _ := repo(t)
+		nested := filepath.Join(dir, "nested")
+		require.NoError(t, os.MkdirAll(nested, os.ModePerm))
+		writeFile(t, filepath.Join(nested, ".noteo.yml"), "extensions: [.txt]\n")
+		// when
+		repo, err := repository.ForWorkDir(nested)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(nested, ".noteo.yml"), repo.ConfigFile())
+		assert.Equal(t, []string{filepath.Join(nested, ".noteo.yml")}, repo.ConfigFiles())
+		config, err := repo.Config()
+		require.NoError(t, err)
+		assert.Contains(t, config.NoteExtensions(), ".txt")
+	})
+
+	t.Run("should not yield notes of nested repository", func(t *testing.T) {
+		dir, repo := repo(t)
+		nested := filepath.Join(dir, "nested")
+		require.NoError(t, os.MkdirAll(filepath.Join(nested, "sub"), os.ModePerm))
+		writeFile(t, filepath.Join(nested, ".noteo.yml"), "")
+		writeFile(t, filepath.Join(nested, "sub", "a.md"), "a")
+		writeFile(t, filepath.Join(dir, "b.md"), "b")
+		// when
+		notes, errs := seq.Collect(repo.NotesSeq(context.Background()))
+		// then
+		assert.Empty(t, errs)
+		assert.Equal(t, []string{"b.md"}, paths(notes))
+	})
+}
+
 func assertSuccess(t *testing.T, ctx context.Context, notes <-chan *note.Note, success <-chan bool, errors <-chan error) {
 	var successClosed, errorClosed, notesClosed bool
 	for !successClosed || !errorClosed || !notesClosed {
@@ -208,6 +636,14 @@ func assertFileEquals(t *testing.T, file, expected string) {
 	assert.Equal(t, expected, string(content))
 }
 
+func paths(notes []*note.Note) []string {
+	var p []string
+	for _, n := range notes {
+		p = append(p, n.Path())
+	}
+	return p
+}
+
 func repo(t *testing.T) (dir string, repo *repository.Repository) {
 	dir, err := os.MkdirTemp("", "noteo-test")
 	require.NoError(t, err)
@@ -218,6 +654,12 @@ func repo(t *testing.T) (dir string, repo *repository.Repository) {
 	return dir, repo
 }
 
+func repoWithConfig(t *testing.T, config string) (string, *repository.Repository) {
+	dir, r := repo(t)
+	writeFile(t, filepath.Join(dir, ".noteo.yml"), config)
+	return dir, r
+}
+
 func writeFile(t *testing.T, filename, content string) {
 	require.NoError(t, os.WriteFile(filename, []byte(content), os.ModePerm))
 }
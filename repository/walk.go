@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/elgopher/noteo/ignore"
+)
+
+// IgnoreFile contains gitignore-style patterns of paths skipped by noteo. It can be placed in any directory of
+// the repository.
+const IgnoreFile = ".noteoignore"
+
+// walker visits files in the repository. Hidden directories and paths matched by IgnoreFile patterns are skipped.
+// Symbolic links are followed, but each directory is visited only once, which prevents loops. Symbolic links to
+// files and directories outside the root are skipped. Nested repositories are skipped too, because they have their
+// own settings.
+type walker struct {
+	root       string
+	realRoot   string // root with symbolic links evaluated
+	extensions []string
+	ignore     ignore.Matcher
+	visited    map[string]bool // real paths of visited directories
+	loaded     map[string]bool // directories which ignore files were loaded
+	nested     map[string]bool // directories checked for being a root of a nested repository
+}
+
+func (r *Repository) newWalker() (*walker, error) {
+	config, err := r.Config()
+	if err != nil {
+		return nil, err
+	}
+	realRoot, err := filepath.EvalSymlinks(r.root)
+	if err != nil {
+		return nil, err
+	}
+	w := &walker{
+		root:       r.root,
+		realRoot:   realRoot,
+		extensions: config.NoteExtensions(),
+		visited:    map[string]bool{},
+		loaded:     map[string]bool{},
+		nested:     map[string]bool{},
+	}
+	// hidden directories, such as .git, can be included back with a negated pattern, e.g. !.github/
+	w.ignore.AddPatterns(".*/")
+	return w, nil
+}
+
+// walk calls fn for dir, its subdirectories and files which are not skipped
+func (w *walker) walk(ctx context.Context, dir string, fn func(path string, info fs.FileInfo) error) error {
+	if err := w.loadIgnoreFilesAbove(dir); err != nil {
+		return err
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	return w.walkDir(ctx, dir, info, fn)
+}
+
+func (w *walker) walkDir(ctx context.Context, dir string, info fs.FileInfo, fn func(string, fs.FileInfo) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	realPath, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return err
+	}
+	if w.visited[realPath] || !w.inRoot(realPath) {
+		return nil
+	}
+	w.visited[realPath] = true
+	if err = w.loadIgnoreFile(dir); err != nil {
+		return err
+	}
+	if err = fn(dir, info); err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		path := filepath.Join(dir, entry.Name())
+		info, err = os.Stat(path) // follows symbolic links
+		if os.IsNotExist(err) {
+			continue // broken symbolic link
+		}
+		if err != nil {
+			return err
+		}
+		if w.ignored(path, info.IsDir()) {
+			continue
+		}
+		if info.IsDir() {
+			nested, err := w.isNestedRepo(path)
+			if err != nil {
+				return err
+			}
+			if nested {
+				continue
+			}
+		}
+		if entry.Type()&fs.ModeSymlink != 0 && !info.IsDir() {
+			realPath, err := filepath.EvalSymlinks(path)
+			if err != nil {
+				return err
+			}
+			if !w.inRoot(realPath) {
+				continue
+			}
+		}
+		if info.IsDir() {
+			err = w.walkDir(ctx, path, info, fn)
+		} else {
+			err = fn(path, info)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inRoot returns true when realPath, which has symbolic links evaluated, is the root or is inside it
+func (w *walker) inRoot(realPath string) bool {
+	rel, err := filepath.Rel(w.realRoot, realPath)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// skipped returns true when path would not be visited by walk, because it or one of its parent directories is
+// hidden or ignored
+func (w *walker) skipped(path string, isDir bool) (bool, error) {
+	if err := w.loadIgnoreFilesAbove(path); err != nil {
+		return false, err
+	}
+	for p := path; p != w.root && strings.HasPrefix(p, w.root); p = filepath.Dir(p) {
+		if w.ignored(p, isDir || p != path) {
+			return true, nil
+		}
+		if isDir || p != path {
+			if nested, err := w.isNestedRepo(p); err != nil || nested {
+				return nested, err
+			}
+		}
+	}
+	return false, nil
+}
+
+// isNestedRepo returns true when dir, which is not the root, is a root of another repository
+func (w *walker) isNestedRepo(dir string) (bool, error) {
+	if dir == w.root {
+		return false, nil
+	}
+	nested, ok := w.nested[dir]
+	if !ok {
+		var err error
+		if nested, err = isRootDir(dir); err != nil {
+			return false, err
+		}
+		w.nested[dir] = nested
+	}
+	return nested, nil
+}
+
+func (w *walker) ignored(path string, isDir bool) bool {
+	rel, err := filepath.Rel(w.root, path)
+	if err != nil {
+		return false
+	}
+	return w.ignore.Match(filepath.ToSlash(rel), isDir)
+}
+
+// isNote returns true for regular files with one of configured extensions. A directory such as foo.md is not a note.
+func (w *walker) isNote(path string, info fs.FileInfo) bool {
+	return info.Mode().IsRegular() && hasExtension(path, w.extensions)
+}
+
+// loadIgnoreFilesAbove loads ignore files from the root down to the parent directory of path
+func (w *walker) loadIgnoreFilesAbove(path string) error {
+	rel, err := filepath.Rel(w.root, filepath.Dir(path))
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return nil
+	}
+	dir := w.root
+	if err = w.loadIgnoreFile(dir); err != nil {
+		return err
+	}
+	if rel == "." {
+		return nil
+	}
+	for _, name := range strings.Split(rel, string(filepath.Separator)) {
+		dir = filepath.Join(dir, name)
+		if err = w.loadIgnoreFile(dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *walker) loadIgnoreFile(dir string) error {
+	rel, err := filepath.Rel(w.root, dir)
+	if err != nil {
+		return err
+	}
+	if w.loaded[rel] {
+		return nil
+	}
+	w.loaded[rel] = true
+	file, err := os.Open(filepath.Join(dir, IgnoreFile))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err = w.ignore.Add(filepath.ToSlash(rel), file); err != nil {
+		return fmt.Errorf("%s: %w", file.Name(), err)
+	}
+	return nil
+}
+
+func hasExtension(file string, extensions []string) bool {
+	ext := filepath.Ext(file)
+	for _, e := range extensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
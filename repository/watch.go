@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/elgopher/noteo/note"
+)
+
+type EventType string
+
+const (
+	NoteCreated  EventType = "created"
+	NoteModified EventType = "modified"
+	NoteRemoved  EventType = "removed"
+)
+
+// Event is a change of the note file. Note path is relative to the working directory, the same as in Notes.
+type Event struct {
+	Type EventType
+	Note *note.Note
+}
+
+// Watch emits events for notes created, modified or removed in the working directory and its subdirectories.
+// Uses file system notifications and falls back to polling when they are not available.
+func (r *Repository) Watch(ctx context.Context) (<-chan Event, <-chan error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return r.WatchPolling(ctx, time.Second)
+	}
+	if err = r.addWatchedDirs(watcher, r.dir); err != nil {
+		_ = watcher.Close()
+		return r.WatchPolling(ctx, time.Second)
+	}
+	w, err := r.newWalker()
+	if err != nil {
+		_ = watcher.Close()
+		return r.WatchPolling(ctx, time.Second)
+	}
+	events := make(chan Event)
+	errs := make(chan error)
+	go func() {
+		defer close(events)
+		defer close(errs)
+		defer watcher.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case err, ok := <-watcher.Errors:
+				if !ok || !sendError(ctx, errs, err) {
+					return
+				}
+			case e, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if e.Has(fsnotify.Create) {
+					if info, err := os.Stat(e.Name); err == nil && info.IsDir() {
+						if err := r.addWatchedDirs(watcher, e.Name); err != nil && !sendError(ctx, errs, err) {
+							return
+						}
+						continue
+					}
+				}
+				if name := filepath.Base(e.Name); name == IgnoreFile || name == configFileName {
+					// ignore files and nested repositories are checked once, so changes need a new walker
+					if w, err = r.newWalker(); err != nil {
+						sendError(ctx, errs, err)
+						return
+					}
+					continue
+				}
+				event, ok := r.eventFor(w, e)
+				if !ok {
+					continue
+				}
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return events, errs
+}
+
+// sendError returns false when ctx was done before err was received
+func sendError(ctx context.Context, errs chan<- error, err error) bool {
+	select {
+	case errs <- err:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// addWatchedDirs watches dir and its subdirectories, skipping the same directories as NotesSeq
+func (r *Repository) addWatchedDirs(watcher *fsnotify.Watcher, dir string) error {
+	w, err := r.newWalker()
+	if err != nil {
+		return err
+	}
+	if dir != r.dir {
+		if skipped, err := w.skipped(dir, true); err != nil || skipped {
+			return err
+		}
+	}
+	return w.walk(context.Background(), dir, func(path string, info os.FileInfo) error {
+		if info.IsDir() {
+			return watcher.Add(path)
+		}
+		return nil
+	})
+}
+
+func (r *Repository) eventFor(w *walker, e fsnotify.Event) (Event, bool) {
+	if !hasExtension(e.Name, w.extensions) {
+		return Event{}, false
+	}
+	if skipped, err := w.skipped(e.Name, false); err != nil || skipped {
+		return Event{}, false
+	}
+	relPath, err := filepath.Rel(r.dir, e.Name)
+	if err != nil {
+		return Event{}, false
+	}
+	switch {
+	case e.Has(fsnotify.Create):
+		return Event{Type: NoteCreated, Note: note.NewInDir(r.dir, relPath)}, true
+	case e.Has(fsnotify.Write):
+		return Event{Type: NoteModified, Note: note.NewInDir(r.dir, relPath)}, true
+	case e.Has(fsnotify.Remove), e.Has(fsnotify.Rename):
+		return Event{Type: NoteRemoved, Note: note.NewInDir(r.dir, relPath)}, true
+	}
+	return Event{}, false
+}
+
+// WatchPolling emits the same events as Watch, but detects changes by scanning the working directory
+// every interval.
+func (r *Repository) WatchPolling(ctx context.Context, interval time.Duration) (<-chan Event, <-chan error) {
+	events := make(chan Event)
+	errs := make(chan error)
+	// taken before returning, so changes made right after calling WatchPolling are not missed
+	previous, snapshotErr := r.snapshot(ctx)
+	go func() {
+		defer close(events)
+		defer close(errs)
+		if snapshotErr != nil && !sendError(ctx, errs, snapshotErr) {
+			return
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				current, err := r.snapshot(ctx)
+				if err != nil {
+					if !sendError(ctx, errs, err) {
+						return
+					}
+					continue
+				}
+				for _, event := range diffSnapshots(r.dir, previous, current) {
+					select {
+					case events <- event:
+					case <-ctx.Done():
+						return
+					}
+				}
+				previous = current
+			}
+		}
+	}()
+	return events, errs
+}
+
+// snapshot returns modification times of all notes in the working directory
+func (r *Repository) snapshot(ctx context.Context) (map[string]time.Time, error) {
+	snapshot := map[string]time.Time{}
+	var err error
+	r.NotesSeq(ctx)(func(n *note.Note, e error) bool {
+		if e != nil {
+			err = e
+			return true
+		}
+		if modified, e := n.Modified(); e == nil {
+			snapshot[n.Path()] = modified
+		}
+		return true
+	})
+	return snapshot, err
+}
+
+func diffSnapshots(dir string, previous, current map[string]time.Time) []Event {
+	var events []Event
+	for path, modified := range current {
+		previousModified, existed := previous[path]
+		switch {
+		case !existed:
+			events = append(events, Event{Type: NoteCreated, Note: note.NewInDir(dir, path)})
+		case !previousModified.Equal(modified):
+			events = append(events, Event{Type: NoteModified, Note: note.NewInDir(dir, path)})
+		}
+	}
+	for path := range previous {
+		if _, exists := current[path]; !exists {
+			events = append(events, Event{Type: NoteRemoved, Note: note.NewInDir(dir, path)})
+		}
+	}
+	return events
+}
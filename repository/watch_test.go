@@ -0,0 +1,86 @@
+package repository_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/noteo/repository"
+)
+
+func TestRepository_Watch(t *testing.T) {
+	watchers := map[string]func(ctx context.Context, repo *repository.Repository) (<-chan repository.Event, <-chan error){
+		"notifications": func(ctx context.Context, repo *repository.Repository) (<-chan repository.Event, <-chan error) {
+			return repo.Watch(ctx)
+		},
+		"polling": func(ctx context.Context, repo *repository.Repository) (<-chan repository.Event, <-chan error) {
+			return repo.WatchPolling(ctx, 10*time.Millisecond)
+		},
+	}
+	for name, watch := range watchers {
+		t.Run(name, func(t *testing.T) {
+			dir, repo := repo(t)
+			file := filepath.Join(dir, "note.md")
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			events, errs := watch(ctx, repo)
+			// when
+			writeFile(t, file, "text")
+			// then
+			assertEvent(t, ctx, events, errs, repository.NoteCreated, "note.md")
+			// when
+			require.NoError(t, os.Remove(file))
+			// then
+			assertEvent(t, ctx, events, errs, repository.NoteRemoved, "note.md")
+		})
+	}
+
+	t.Run("should skip files other than notes", func(t *testing.T) {
+		dir, repo := repo(t)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		events, errs := repo.WatchPolling(ctx, 10*time.Millisecond)
+		// when
+		writeFile(t, filepath.Join(dir, "file.txt"), "text")
+		writeFile(t, filepath.Join(dir, "note.md"), "text")
+		// then
+		assertEvent(t, ctx, events, errs, repository.NoteCreated, "note.md")
+	})
+
+	t.Run("should skip ignored notes", func(t *testing.T) {
+		dir, repo := repo(t)
+		writeFile(t, filepath.Join(dir, repository.IgnoreFile), "draft.md\n")
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		events, errs := repo.Watch(ctx)
+		// when
+		writeFile(t, filepath.Join(dir, "draft.md"), "text")
+		writeFile(t, filepath.Join(dir, "note.md"), "text")
+		// then
+		assertEvent(t, ctx, events, errs, repository.NoteCreated, "note.md")
+	})
+}
+
+func assertEvent(t *testing.T, ctx context.Context, events <-chan repository.Event, errs <-chan error,
+	expectedType repository.EventType, expectedPath string) {
+	for {
+		select {
+		case event := <-events:
+			if event.Type == repository.NoteModified && expectedType != repository.NoteModified {
+				continue // file creation can be reported as two events
+			}
+			assert.Equal(t, expectedType, event.Type)
+			assert.Equal(t, expectedPath, event.Note.Path())
+			return
+		case err := <-errs:
+			require.NoError(t, err)
+		case <-ctx.Done():
+			require.FailNow(t, "timeout")
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// Package seq provides iterators yielding values or errors in order, which replace pairs of channels
+package seq
+
+import (
+	"context"
+)
+
+// Seq is an iterator over values and errors. When error is not nil, value is a zero value. Iteration stops when yield
+// returns false.
+//
+// Seq has the same signature as iter.Seq2[T, error], so in Go 1.23+ it can be used in a for-range loop:
+//
+//	for note, err := range repo.NotesSeq(ctx) {
+//	}
+type Seq[T any] func(yield func(T, error) bool)
+
+// Collect returns all values and all errors
+func Collect[T any](s Seq[T]) ([]T, []error) {
+	var values []T
+	var errs []error
+	s(func(v T, err error) bool {
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			values = append(values, v)
+		}
+		return true
+	})
+	return values, errs
+}
+
+// Map converts values using function f. Errors are passed as they are.
+func Map[T, U any](s Seq[T], f func(T) U) Seq[U] {
+	return func(yield func(U, error) bool) {
+		s(func(v T, err error) bool {
+			if err != nil {
+				var zero U
+				return yield(zero, err)
+			}
+			return yield(f(v), nil)
+		})
+	}
+}
+
+// Concat yields values and errors of all sequences, one sequence after another
+func Concat[T any](seqs ...Seq[T]) Seq[T] {
+	return func(yield func(T, error) bool) {
+		for _, s := range seqs {
+			stopped := false
+			s(func(v T, err error) bool {
+				if !yield(v, err) {
+					stopped = true
+					return false
+				}
+				return true
+			})
+			if stopped {
+				return
+			}
+		}
+	}
+}
+
+// Eager iterates s to the end right away and returns Seq yielding the same values and errors, in the same order.
+// It is used when iterating s has side effects, which must be done even if returned Seq is not iterated.
+func Eager[T any](s Seq[T]) Seq[T] {
+	type item struct {
+		value T
+		err   error
+	}
+	var items []item
+	s(func(v T, err error) bool {
+		items = append(items, item{value: v, err: err})
+		return true
+	})
+	return func(yield func(T, error) bool) {
+		for _, i := range items {
+			if !yield(i.value, i.err) {
+				return
+			}
+		}
+	}
+}
+
+// FromChannel returns Seq yielding values received from the channel until it is closed or ctx is cancelled
+func FromChannel[T any](ctx context.Context, values <-chan T) Seq[T] {
+	return func(yield func(T, error) bool) {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-values:
+				if !ok || !yield(v, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Channels adapts Seq to a pair of channels, the way APIs in this module worked before Seq was introduced. Both
+// channels must be read concurrently until they are closed or ctx is cancelled.
+func Channels[T any](ctx context.Context, s Seq[T]) (<-chan T, <-chan error) {
+	values := make(chan T)
+	errs := make(chan error)
+	go func() {
+		defer close(values)
+		defer close(errs)
+		s(func(v T, err error) bool {
+			if err != nil {
+				select {
+				case errs <- err:
+					return true
+				case <-ctx.Done():
+					return false
+				}
+			}
+			select {
+			case values <- v:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+	}()
+	return values, errs
+}
@@ -0,0 +1,154 @@
+package seq_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/noteo/seq"
+)
+
+var errSecond = errors.New("second")
+
+// numbers yields 1, errSecond and 3
+func numbers(yield func(int, error) bool) {
+	_ = yield(1, nil) && yield(0, errSecond) && yield(3, nil)
+}
+
+func TestCollect(t *testing.T) {
+	// when
+	values, errs := seq.Collect[int](numbers)
+	// then
+	assert.Equal(t, []int{1, 3}, values)
+	assert.Equal(t, []error{errSecond}, errs)
+}
+
+func TestMap(t *testing.T) {
+	// when
+	values, errs := seq.Collect(seq.Map[int, string](numbers, strconv.Itoa))
+	// then
+	assert.Equal(t, []string{"1", "3"}, values)
+	assert.Equal(t, []error{errSecond}, errs)
+}
+
+func TestConcat(t *testing.T) {
+	t.Run("should yield all sequences in order", func(t *testing.T) {
+		// when
+		values, errs := seq.Collect(seq.Concat[int](numbers, numbers))
+		// then
+		assert.Equal(t, []int{1, 3, 1, 3}, values)
+		assert.Equal(t, []error{errSecond, errSecond}, errs)
+	})
+
+	t.Run("should stop when yield returns false", func(t *testing.T) {
+		var yielded []int
+		// when
+		seq.Concat[int](numbers, numbers)(func(v int, err error) bool {
+			yielded = append(yielded, v)
+			return len(yielded) < 4
+		})
+		// then
+		assert.Equal(t, []int{1, 0, 3, 1}, yielded)
+	})
+}
+
+func TestEager(t *testing.T) {
+	iterated := 0
+	counted := func(yield func(int, error) bool) {
+		iterated++
+		numbers(yield)
+	}
+	// when
+	s := seq.Eager[int](counted)
+	// then
+	assert.Equal(t, 1, iterated)
+	var yielded []int
+	var errs []error
+	s(func(v int, err error) bool {
+		yielded = append(yielded, v)
+		errs = append(errs, err)
+		return true
+	})
+	assert.Equal(t, []int{1, 0, 3}, yielded)
+	assert.Equal(t, []error{nil, errSecond, nil}, errs)
+	assert.Equal(t, 1, iterated)
+}
+
+func TestSeq_Stop(t *testing.T) {
+	var yielded []int
+	// when
+	numbers(func(v int, err error) bool {
+		yielded = append(yielded, v)
+		return false
+	})
+	// then
+	assert.Equal(t, []int{1}, yielded)
+}
+
+func TestFromChannel(t *testing.T) {
+	t.Run("should yield values until channel is closed", func(t *testing.T) {
+		ch := make(chan int, 2)
+		ch <- 1
+		ch <- 2
+		close(ch)
+		// when
+		values, errs := seq.Collect(seq.FromChannel(context.Background(), ch))
+		// then
+		assert.Equal(t, []int{1, 2}, values)
+		assert.Empty(t, errs)
+	})
+
+	t.Run("should stop when context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		// when
+		values, _ := seq.Collect(seq.FromChannel(ctx, make(chan int)))
+		// then
+		assert.Empty(t, values)
+	})
+}
+
+func TestChannels(t *testing.T) {
+	t.Run("should send values and errors", func(t *testing.T) {
+		values, errs := seq.Channels[int](context.Background(), numbers)
+		var receivedValues []int
+		var receivedErrors []error
+		// when
+		for values != nil || errs != nil {
+			select {
+			case v, ok := <-values:
+				if !ok {
+					values = nil
+					continue
+				}
+				receivedValues = append(receivedValues, v)
+			case err, ok := <-errs:
+				if !ok {
+					errs = nil
+					continue
+				}
+				receivedErrors = append(receivedErrors, err)
+			}
+		}
+		// then
+		assert.Equal(t, []int{1, 3}, receivedValues)
+		assert.Equal(t, []error{errSecond}, receivedErrors)
+	})
+
+	t.Run("should close channels when context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		values, errs := seq.Channels[int](ctx, numbers)
+		// when
+		cancel()
+		// then
+		for range errs {
+		}
+		for range values {
+		}
+		require.Error(t, ctx.Err())
+	})
+}
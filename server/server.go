@@ -0,0 +1,339 @@
+// Package server provides local HTTP/JSON API for notes in a repository
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/elgopher/noteo/note"
+	"github.com/elgopher/noteo/notes"
+	"github.com/elgopher/noteo/output/jayson"
+	"github.com/elgopher/noteo/repository"
+	"github.com/elgopher/noteo/seq"
+)
+
+// Query is a result of parsing list query parameters
+type Query struct {
+	Predicates []notes.Predicate
+	Less       notes.Less
+	Limit      int
+}
+
+// QueryParser parses query parameters of GET /notes, which are the same as ls flags, e.g. ?tag=idea&limit=10
+type QueryParser func(params map[string][]string) (Query, error)
+
+// Server is http.Handler for notes in a repository working directory. Paths of notes are relative to the current working directory.
+type Server struct {
+	repo        *repository.Repository
+	parseQuery  QueryParser
+	token       string
+	formatter   jayson.Formatter
+	mux         *http.ServeMux
+	maxBodySize int64
+}
+
+// New returns Server serving endpoints:
+//
+//	GET    /notes                     list notes, query parameters are the same as ls flags
+//	POST   /notes                     create note, request body is a note text
+//	GET    /notes/{file}              get note
+//	PUT    /tags?file={file}&tag={t}  set tag
+//	DELETE /tags?file={file}&tag={t}  remove tag
+//	POST   /move?source={s}&target={t} move note and update links
+//
+// When token is not empty, requests must have "Authorization: Bearer {token}" header. Requests with Host other than
+// a loopback address or with Origin of other site are rejected, so web pages cannot access notes.
+func New(repo *repository.Repository, parseQuery QueryParser, token string) *Server {
+	s := &Server{
+		repo:        repo,
+		parseQuery:  parseQuery,
+		token:       token,
+		mux:         http.NewServeMux(),
+		maxBodySize: 10 << 20,
+	}
+	s.mux.HandleFunc("/notes", s.notes)
+	s.mux.HandleFunc("/notes/", s.note)
+	s.mux.HandleFunc("/tags", s.tags)
+	s.mux.HandleFunc("/move", s.move)
+	return s
+}
+
+// ListenAndServe serves requests on a loopback address, such as 127.0.0.1:8375 or localhost:8375
+func (s *Server) ListenAndServe(addr string) error {
+	if err := validateLoopback(addr); err != nil {
+		return err
+	}
+	return http.ListenAndServe(addr, s)
+}
+
+func validateLoopback(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	if !isLoopback(host) {
+		return fmt.Errorf("address %s is not a loopback address. Use 127.0.0.1 or localhost", addr)
+	}
+	return nil
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := checkHostAndOrigin(r); err != nil {
+		writeError(w, http.StatusForbidden, err)
+		return
+	}
+	if !s.authorized(r) {
+		writeError(w, http.StatusUnauthorized, errors.New("missing or invalid token"))
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// checkHostAndOrigin rejects requests sent by web pages, including those using DNS rebinding
+func checkHostAndOrigin(r *http.Request) error {
+	if !isLoopback(hostname(r.Host)) {
+		return fmt.Errorf("host %s is not a loopback address", r.Host)
+	}
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return nil
+	}
+	u, err := url.Parse(origin)
+	if err != nil || !isLoopback(u.Hostname()) {
+		return fmt.Errorf("cross-origin request from %s is not allowed", origin)
+	}
+	return nil
+}
+
+// hostname returns host without port
+func hostname(hostPort string) string {
+	host, _, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		return strings.Trim(hostPort, "[]")
+	}
+	return host
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	if s.token == "" {
+		return true
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+func (s *Server) notes(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.list(w, r)
+	case http.MethodPost:
+		s.create(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+	}
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request) {
+	query, err := s.parseQuery(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	dirNotes := seq.Map(s.repo.NotesSeq(ctx), func(n *note.Note) notes.Note {
+		return n
+	})
+	sorted := notes.TopSeq(query.Limit, notes.FilterSeq(dirNotes, query.Predicates...), query.Less)
+	result := listResponse{Notes: []*jayson.Note{}}
+	sorted(func(n notes.Note, err error) bool {
+		if err == nil {
+			var converted *jayson.Note
+			if converted, err = s.formatter.Convert(n); err == nil {
+				result.Notes = append(result.Notes, converted)
+			}
+		}
+		if err != nil {
+			result.Warnings = append(result.Warnings, err.Error())
+		}
+		return true
+	})
+	writeJSON(w, http.StatusOK, result)
+}
+
+type listResponse struct {
+	Notes    []*jayson.Note `json:"notes"`
+	Warnings []string       `json:"warnings,omitempty"`
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request) {
+	text, err := io.ReadAll(io.LimitReader(r.Body, s.maxBodySize))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if len(text) == 0 {
+		writeError(w, http.StatusBadRequest, errors.New("empty note text"))
+		return
+	}
+	file, err := s.repo.Add(string(text))
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]string{"file": filepath.ToSlash(file)})
+}
+
+func (s *Server) note(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+	file, err := s.file(strings.TrimPrefix(r.URL.Path, "/notes/"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if _, err = os.Stat(filepath.Join(s.repo.WorkDir(), file)); err != nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("note %s not found", file))
+		return
+	}
+	converted, err := s.formatter.Convert(note.NewInDir(s.repo.WorkDir(), file))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, converted)
+}
+
+// file returns path of the note relative to the working directory. Files without note extension are rejected.
+func (s *Server) file(path string) (string, error) {
+	path, err := relativePath(path)
+	if err != nil {
+		return "", err
+	}
+	if err = s.repo.CheckExtension(path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// moveTarget returns path of the note or the existing directory relative to the working directory
+func (s *Server) moveTarget(path string) (string, error) {
+	path, err := relativePath(path)
+	if err != nil {
+		return "", err
+	}
+	if info, err := os.Stat(filepath.Join(s.repo.WorkDir(), path)); err == nil && info.IsDir() {
+		return path, nil
+	}
+	if err = s.repo.CheckExtension(path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// relativePath cleans path relative to the working directory. Paths outside the working directory are rejected.
+func relativePath(path string) (string, error) {
+	if path == "" {
+		return "", errors.New("missing file")
+	}
+	path = filepath.FromSlash(path)
+	if filepath.IsAbs(path) {
+		return "", fmt.Errorf("file %s is not relative", path)
+	}
+	path = filepath.Clean(path)
+	if path == ".." || strings.HasPrefix(path, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("file %s is outside of the working directory", path)
+	}
+	return path, nil
+}
+
+func (s *Server) tags(w http.ResponseWriter, r *http.Request) {
+	file, err := s.file(r.URL.Query().Get("file"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	t := r.URL.Query().Get("tag")
+	var updated bool
+	switch r.Method {
+	case http.MethodPut:
+		updated, err = s.repo.TagFileWith(file, t)
+	case http.MethodDelete:
+		updated, err = s.repo.UntagFile(file, t)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]bool{"updated": updated})
+}
+
+func (s *Server) move(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+	source, err := s.file(r.URL.Query().Get("source"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	target, err := s.moveTarget(r.URL.Query().Get("target"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	updatedNotes, err := s.repo.MoveSeq(r.Context(), source, target)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Errorf("move failed: %v", err))
+		return
+	}
+	result := moveResponse{Updated: []string{}}
+	updatedNotes(func(n *note.Note, err error) bool {
+		if err != nil {
+			result.Warnings = append(result.Warnings, err.Error())
+			return true
+		}
+		result.Updated = append(result.Updated, filepath.ToSlash(n.Path()))
+		return true
+	})
+	writeJSON(w, http.StatusOK, result)
+}
+
+type moveResponse struct {
+	Updated  []string `json:"updated"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
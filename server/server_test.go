@@ -0,0 +1,270 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/noteo/notes"
+	"github.com/elgopher/noteo/repository"
+	"github.com/elgopher/noteo/server"
+)
+
+func TestServer_List(t *testing.T) {
+	t.Run("should list all notes", func(t *testing.T) {
+		s, dir := newServer(t, "")
+		writeFile(t, dir, "a.md", "a")
+		writeFile(t, dir, "b.md", "b")
+		// when
+		response := request(s, http.MethodGet, "/notes", "")
+		// then
+		require.Equal(t, http.StatusOK, response.Code)
+		var body struct {
+			Notes []struct{ File string }
+		}
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+		assert.Len(t, body.Notes, 2)
+	})
+
+	t.Run("should filter notes using query parameters", func(t *testing.T) {
+		s, dir := newServer(t, "")
+		writeFile(t, dir, "a.md", "---\nTags: idea\n---\na")
+		writeFile(t, dir, "b.md", "b")
+		// when
+		response := request(s, http.MethodGet, "/notes?tag=idea", "")
+		// then
+		require.Equal(t, http.StatusOK, response.Code)
+		var body struct {
+			Notes []struct {
+				File string
+				Tags []string
+			}
+		}
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+		require.Len(t, body.Notes, 1)
+		assert.Equal(t, "a.md", body.Notes[0].File)
+		assert.Equal(t, []string{"idea"}, body.Notes[0].Tags)
+	})
+
+	t.Run("should return bad request for unsupported query parameter", func(t *testing.T) {
+		s, _ := newServer(t, "")
+		// when
+		response := request(s, http.MethodGet, "/notes?unknown=1", "")
+		// then
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+func TestServer_Get(t *testing.T) {
+	t.Run("should get note", func(t *testing.T) {
+		s, dir := newServer(t, "")
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "dir"), os.ModePerm))
+		writeFile(t, dir, filepath.Join("dir", "a.md"), "---\nTags: idea\n---\ntext")
+		// when
+		response := request(s, http.MethodGet, "/notes/dir/a.md", "")
+		// then
+		require.Equal(t, http.StatusOK, response.Code)
+		var body struct {
+			File string
+			Text string
+		}
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+		assert.Equal(t, filepath.Join("dir", "a.md"), body.File)
+		assert.Equal(t, "text", body.Text)
+	})
+
+	t.Run("should reject file which is not a note", func(t *testing.T) {
+		s, _ := newServer(t, "")
+		// when
+		response := request(s, http.MethodGet, "/notes/.noteo.yml", "")
+		// then
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("should return not found", func(t *testing.T) {
+		s, _ := newServer(t, "")
+		// when
+		response := request(s, http.MethodGet, "/notes/missing.md", "")
+		// then
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestServer_Create(t *testing.T) {
+	s, dir := newServer(t, "")
+	// when
+	response := request(s, http.MethodPost, "/notes", "new note")
+	// then
+	require.Equal(t, http.StatusCreated, response.Code)
+	assert.JSONEq(t, `{"file":"new-note.md"}`, response.Body.String())
+	assertFileEquals(t, dir, "new-note.md", "new note")
+}
+
+func TestServer_Tags(t *testing.T) {
+	t.Run("should set tag", func(t *testing.T) {
+		s, dir := newServer(t, "")
+		writeFile(t, dir, "a.md", "text")
+		// when
+		response := request(s, http.MethodPut, "/tags?file=a.md&tag=idea", "")
+		// then
+		require.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `{"updated":true}`, response.Body.String())
+		assertFileEquals(t, dir, "a.md", "---\nTags: idea\n---\ntext")
+	})
+
+	t.Run("should remove tag", func(t *testing.T) {
+		s, dir := newServer(t, "")
+		writeFile(t, dir, "a.md", "---\nTags: idea other\n---\ntext")
+		// when
+		response := request(s, http.MethodDelete, "/tags?file=a.md&tag=idea", "")
+		// then
+		require.Equal(t, http.StatusOK, response.Code)
+		assertFileEquals(t, dir, "a.md", "---\nTags: other\n---\ntext")
+	})
+
+	t.Run("should reject file outside of the working directory", func(t *testing.T) {
+		s, _ := newServer(t, "")
+		// when
+		response := request(s, http.MethodPut, "/tags?file=../secret.md&tag=idea", "")
+		// then
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+func TestServer_Move(t *testing.T) {
+	s, dir := newServer(t, "")
+	writeFile(t, dir, "source.md", "source")
+	writeFile(t, dir, "link.md", "[link](source.md)")
+	// when
+	response := request(s, http.MethodPost, "/move?source=source.md&target=target.md", "")
+	// then
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"updated":["link.md"]}`, response.Body.String())
+	assert.NoFileExists(t, filepath.Join(dir, "source.md"))
+	assert.FileExists(t, filepath.Join(dir, "target.md"))
+	assertFileEquals(t, dir, "link.md", "[link](target.md)")
+}
+
+func TestServer_MoveToDirectory(t *testing.T) {
+	s, dir := newServer(t, "")
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "archive"), os.ModePerm))
+	writeFile(t, dir, "source.md", "source")
+	// when
+	response := request(s, http.MethodPost, "/move?source=source.md&target=archive", "")
+	// then
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.FileExists(t, filepath.Join(dir, "archive", "source.md"))
+}
+
+func TestServer_Token(t *testing.T) {
+	t.Run("should reject request without token", func(t *testing.T) {
+		s, _ := newServer(t, "secret")
+		// when
+		response := request(s, http.MethodGet, "/notes", "")
+		// then
+		assert.Equal(t, http.StatusUnauthorized, response.Code)
+	})
+
+	t.Run("should accept request with token", func(t *testing.T) {
+		s, _ := newServer(t, "secret")
+		req := httptest.NewRequest(http.MethodGet, "http://localhost:8375/notes", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		response := httptest.NewRecorder()
+		// when
+		s.ServeHTTP(response, req)
+		// then
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+}
+
+func TestServer_HostAndOrigin(t *testing.T) {
+	t.Run("should reject request with host which is not loopback", func(t *testing.T) {
+		s, _ := newServer(t, "")
+		req := httptest.NewRequest(http.MethodGet, "http://attacker.example.com:8375/notes", nil)
+		response := httptest.NewRecorder()
+		// when
+		s.ServeHTTP(response, req)
+		// then
+		assert.Equal(t, http.StatusForbidden, response.Code)
+	})
+
+	t.Run("should reject cross-origin request", func(t *testing.T) {
+		s, _ := newServer(t, "")
+		req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:8375/notes", strings.NewReader("text"))
+		req.Header.Set("Origin", "https://attacker.example.com")
+		response := httptest.NewRecorder()
+		// when
+		s.ServeHTTP(response, req)
+		// then
+		assert.Equal(t, http.StatusForbidden, response.Code)
+	})
+
+	t.Run("should accept request from localhost origin", func(t *testing.T) {
+		s, _ := newServer(t, "")
+		req := httptest.NewRequest(http.MethodGet, "http://localhost:8375/notes", nil)
+		req.Header.Set("Origin", "http://localhost:8375")
+		response := httptest.NewRecorder()
+		// when
+		s.ServeHTTP(response, req)
+		// then
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+}
+
+func TestServer_ListenAndServe(t *testing.T) {
+	t.Run("should reject non loopback address", func(t *testing.T) {
+		s, _ := newServer(t, "")
+		// when
+		err := s.ListenAndServe("0.0.0.0:0")
+		// then
+		assert.Error(t, err)
+	})
+}
+
+// newServer creates a repository in a temporary directory and returns server and the directory. Working directory
+// of the process is not changed, so paths must be resolved against the repository.
+func newServer(t *testing.T, token string) (*server.Server, string) {
+	dir := t.TempDir()
+	_, err := repository.Init(dir)
+	require.NoError(t, err)
+	repo, err := repository.ForWorkDir(dir)
+	require.NoError(t, err)
+	return server.New(repo, tagQuery, token), dir
+}
+
+// tagQuery supports only "tag" query parameter
+func tagQuery(params map[string][]string) (server.Query, error) {
+	query := server.Query{Limit: 100, Less: notes.ModifiedDesc}
+	for name, values := range params {
+		if name != "tag" {
+			return query, assert.AnError
+		}
+		for _, value := range values {
+			query.Predicates = append(query.Predicates, notes.Tag(value))
+		}
+	}
+	return query, nil
+}
+
+func request(s *server.Server, method, target, body string) *httptest.ResponseRecorder {
+	response := httptest.NewRecorder()
+	s.ServeHTTP(response, httptest.NewRequest(method, "http://127.0.0.1:8375"+target, strings.NewReader(body)))
+	return response
+}
+
+func writeFile(t *testing.T, dir, filename, content string) {
+	require.NoError(t, os.WriteFile(filepath.Join(dir, filename), []byte(content), os.ModePerm))
+}
+
+func assertFileEquals(t *testing.T, dir, filename, expected string) {
+	bytes, err := os.ReadFile(filepath.Join(dir, filename))
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(bytes))
+}
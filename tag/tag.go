@@ -31,6 +31,20 @@ func (t Tag) Name() string {
 	return s
 }
 
+// WithName returns a tag with the same value but different name
+func (t Tag) WithName(name string) Tag {
+	s := t.tag
+	if strings.Contains(s, ":") {
+		return Tag{tag: name + s[strings.Index(s, ":"):]}
+	}
+	return Tag{tag: name}
+}
+
+// WithValue returns a tag with the same name but different value
+func (t Tag) WithValue(value string) Tag {
+	return Tag{tag: t.Name() + ":" + value}
+}
+
 func (t Tag) Value() (string, error) {
 	s := t.tag
 	if !strings.Contains(s, ":") {
@@ -53,6 +67,42 @@ func parseNumber(value string) (int, error) {
 	return int(num), err
 }
 
+func (t Tag) Float() (float64, error) {
+	value, err := t.Value()
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+// Duration parses value such as 2h30m or 45m
+func (t Tag) Duration() (time.Duration, error) {
+	value, err := t.Value()
+	if err != nil {
+		return 0, err
+	}
+	return time.ParseDuration(value)
+}
+
+// Bool parses value such as true, false, yes, no, on, off, 1 or 0
+func (t Tag) Bool() (bool, error) {
+	value, err := t.Value()
+	if err != nil {
+		return false, err
+	}
+	return parseBool(value)
+}
+
+func parseBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "yes", "y", "on":
+		return true, nil
+	case "no", "n", "off":
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
+
 func (t Tag) AbsoluteDate() (time.Time, error) {
 	value, err := t.Value()
 	if err != nil {
@@ -74,14 +124,114 @@ func (t Tag) MakeDateAbsolute() (Tag, error) {
 	if err != nil {
 		return t, err
 	}
-	if relativeDate.Hour() == 0 && relativeDate.Minute() == 0 && relativeDate.Second() == 0 && relativeDate.Nanosecond() == 0 {
-		return Tag{
-			tag: t.Name() + ":" + relativeDate.Format("2006-01-02"),
-		}, nil
+	return t.WithDate(relativeDate), nil
+}
+
+// WithDate returns a tag with the same name and date value. Midnight is formatted as date only (2006-01-02).
+func (t Tag) WithDate(d time.Time) Tag {
+	if d.Hour() == 0 && d.Minute() == 0 && d.Second() == 0 && d.Nanosecond() == 0 {
+		return t.WithValue(d.Format("2006-01-02"))
+	}
+	return t.WithValue(d.Format(time.RFC3339))
+}
+
+// Compare compares values of two tags. Values are compared as numbers, durations or dates, whichever type
+// both values have. Returns -1 if t value is lower than another, 0 if equal and +1 if greater.
+func (t Tag) Compare(another Tag) (int, error) {
+	for _, compare := range []func(a, b Tag) (int, bool){compareFloats, compareDurations, compareDates} {
+		if result, ok := compare(t, another); ok {
+			return result, nil
+		}
+	}
+	return 0, fmt.Errorf("values of tags %s and %s are not comparable", t, another)
+}
+
+// ValueEquals returns true when both tags have equal values. Numbers, durations and dates are compared as typed
+// values, so "priority:01" equals "priority:1". Booleans are compared as typed values only when both values are bool
+// words, such as "yes" and "true". Other values are compared as strings.
+func (t Tag) ValueEquals(another Tag) bool {
+	first, err := t.Value()
+	if err != nil {
+		return false
+	}
+	second, err := another.Value()
+	if err != nil {
+		return false
+	}
+	if result, err := t.Compare(another); err == nil {
+		return result == 0
+	}
+	if isBoolWord(first) && isBoolWord(second) {
+		return boolEquals(t, another)
+	}
+	return first == second
+}
+
+func isBoolWord(value string) bool {
+	switch strings.ToLower(value) {
+	case "true", "false", "yes", "no", "y", "n", "on", "off":
+		return true
+	}
+	return false
+}
+
+func boolEquals(a, b Tag) bool {
+	first, err := a.Bool()
+	if err != nil {
+		return false
+	}
+	second, err := b.Bool()
+	if err != nil {
+		return false
+	}
+	return first == second
+}
+
+func compareFloats(a, b Tag) (int, bool) {
+	first, err := a.Float()
+	if err != nil {
+		return 0, false
+	}
+	second, err := b.Float()
+	if err != nil {
+		return 0, false
+	}
+	return compareOrdered(first < second, first > second), true
+}
+
+func compareDurations(a, b Tag) (int, bool) {
+	first, err := a.Duration()
+	if err != nil {
+		return 0, false
+	}
+	second, err := b.Duration()
+	if err != nil {
+		return 0, false
+	}
+	return compareOrdered(first < second, first > second), true
+}
+
+func compareDates(a, b Tag) (int, bool) {
+	first, err := a.AbsoluteDate()
+	if err != nil {
+		return 0, false
+	}
+	second, err := b.AbsoluteDate()
+	if err != nil {
+		return 0, false
+	}
+	return compareOrdered(first.Before(second), first.After(second)), true
+}
+
+func compareOrdered(lower, greater bool) int {
+	switch {
+	case lower:
+		return -1
+	case greater:
+		return 1
+	default:
+		return 0
 	}
-	return Tag{
-		tag: t.Name() + ":" + relativeDate.Format(time.RFC3339),
-	}, nil
 }
 
 func (t Tag) String() string {
@@ -46,6 +46,14 @@ func TestTag_MakeDateAbsolute(t *testing.T) {
 			tag:         "deadline:today",
 			expectedTag: "deadline:2020-09-10",
 		},
+		"next friday": {
+			tag:         "deadline:next-friday",
+			expectedTag: "deadline:2020-09-11",
+		},
+		"in a week": {
+			tag:         "deadline:+7d",
+			expectedTag: "deadline:2020-09-17",
+		},
 	}
 	date.SetNow(func() time.Time {
 		return time.Date(2020, 9, 10, 16, 30, 11, 0, time.FixedZone("CEST", 60*60*2))
@@ -62,3 +70,73 @@ func TestTag_MakeDateAbsolute(t *testing.T) {
 		})
 	}
 }
+
+func TestTag_Compare(t *testing.T) {
+	tests := map[string]struct {
+		first, second string
+		expected      int
+	}{
+		"numbers":           {first: "p:1", second: "p:2", expected: -1},
+		"floats":            {first: "p:2.5", second: "p:2", expected: 1},
+		"equal numbers":     {first: "p:01", second: "p:1", expected: 0},
+		"durations":         {first: "estimate:2h30m", second: "estimate:45m", expected: 1},
+		"dates":             {first: "deadline:2020-01-01", second: "deadline:2020-02-01", expected: -1},
+		"negative floats":   {first: "t:-1.5", second: "t:-1", expected: -1},
+		"equal durations":   {first: "e:90m", second: "e:1h30m", expected: 0},
+		"number and float":  {first: "p:3", second: "p:2.99", expected: 1},
+		"different formats": {first: "d:2020-10-15T16:30:10+02:00", second: "d:2020-10-15", expected: 1},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := newTag(t, test.first).Compare(newTag(t, test.second))
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, result)
+		})
+	}
+
+	t.Run("should return error for values of different types", func(t *testing.T) {
+		_, err := newTag(t, "p:1").Compare(newTag(t, "p:2h"))
+		assert.Error(t, err)
+	})
+}
+
+func TestTag_Bool(t *testing.T) {
+	tests := map[string]bool{
+		"done:true": true, "done:yes": true, "done:1": true, "done:on": true,
+		"done:false": false, "done:no": false, "done:0": false, "done:off": false,
+	}
+	for given, expected := range tests {
+		t.Run(given, func(t *testing.T) {
+			b, err := newTag(t, given).Bool()
+			require.NoError(t, err)
+			assert.Equal(t, expected, b)
+		})
+	}
+}
+
+func TestTag_ValueEquals(t *testing.T) {
+	tests := map[string]struct {
+		first, second string
+		expected      bool
+	}{
+		"bool words":         {first: "done:yes", second: "done:true", expected: true},
+		"different bools":    {first: "done:on", second: "done:no", expected: false},
+		"number and bool":    {first: "priority:1", second: "priority:yes", expected: false},
+		"zero and bool":      {first: "priority:0", second: "priority:false", expected: false},
+		"numbers":            {first: "priority:1", second: "priority:01", expected: true},
+		"different strings":  {first: "status:open", second: "status:done", expected: false},
+		"tag without values": {first: "status", second: "status", expected: false},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			first, err := tag.New(test.first)
+			require.NoError(t, err)
+			second, err := tag.New(test.second)
+			require.NoError(t, err)
+			// when
+			equal := first.ValueEquals(second)
+			// then
+			assert.Equal(t, test.expected, equal)
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package tag
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValueType describes what kind of value a name:value tag can hold.
+type ValueType string
+
+const (
+	AnyValue      ValueType = ""
+	NumberValue   ValueType = "number"
+	FloatValue    ValueType = "float"
+	DurationValue ValueType = "duration"
+	BoolValue     ValueType = "bool"
+	DateValue     ValueType = "date"
+)
+
+// EnumValue returns a type accepting only given values, for example "enum(open,blocked,done)"
+func EnumValue(values ...string) ValueType {
+	return ValueType("enum(" + strings.Join(values, ",") + ")")
+}
+
+func ParseValueType(s string) (ValueType, error) {
+	valueType := ValueType(s)
+	switch valueType {
+	case AnyValue, NumberValue, FloatValue, DurationValue, BoolValue, DateValue:
+		return valueType, nil
+	}
+	if values, ok := valueType.EnumValues(); ok && len(values) > 0 {
+		return valueType, nil
+	}
+	return "", fmt.Errorf("unsupported tag value type: %s", s)
+}
+
+// EnumValues returns values allowed by enum type. Returns false if type is not an enum.
+func (v ValueType) EnumValues() ([]string, bool) {
+	s := string(v)
+	if !strings.HasPrefix(s, "enum(") || !strings.HasSuffix(s, ")") {
+		return nil, false
+	}
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "enum("), ")")
+	var values []string
+	for _, value := range strings.Split(s, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values, true
+}
+
+// Vocabulary is a controlled set of tag names with aliases and value types.
+type Vocabulary struct {
+	// Strict rejects tags which are not declared in Names or Aliases
+	Strict bool
+	// Names maps canonical tag name to its value type
+	Names map[string]ValueType
+	// Aliases maps alias to canonical tag name
+	Aliases map[string]string
+}
+
+// Canonical replaces alias with the canonical tag name. Value is preserved.
+func (v *Vocabulary) Canonical(t Tag) Tag {
+	canonicalName, ok := v.Aliases[t.Name()]
+	if !ok {
+		return t
+	}
+	return t.WithName(canonicalName)
+}
+
+// Normalize replaces alias with the canonical tag name and validates the tag against vocabulary.
+func (v *Vocabulary) Normalize(t Tag) (Tag, error) {
+	t = v.Canonical(t)
+	valueType, known := v.Names[t.Name()]
+	if !known {
+		if v.Strict {
+			return t, fmt.Errorf("unknown tag %s", t.Name())
+		}
+		return t, nil
+	}
+	if err := validateValue(t, valueType); err != nil {
+		return t, err
+	}
+	return t, nil
+}
+
+// ValueEquals is like Tag.ValueEquals, but values of tags declared as bool are always compared as booleans, so
+// "done:1" equals "done:yes".
+func (v *Vocabulary) ValueEquals(a, b Tag) bool {
+	if v != nil && v.Names[v.Canonical(a).Name()] == BoolValue {
+		return boolEquals(a, b)
+	}
+	return a.ValueEquals(b)
+}
+
+func validateValue(t Tag, valueType ValueType) error {
+	var err error
+	switch valueType {
+	case NumberValue:
+		_, err = t.Number()
+	case FloatValue:
+		_, err = t.Float()
+	case DurationValue:
+		_, err = t.Duration()
+	case BoolValue:
+		_, err = t.Bool()
+	case DateValue:
+		_, err = t.RelativeDate()
+	default:
+		if values, ok := valueType.EnumValues(); ok {
+			err = validateEnum(t, values)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("tag %s should have %s value: %v", t, valueType, err)
+	}
+	return nil
+}
+
+func validateEnum(t Tag, values []string) error {
+	value, err := t.Value()
+	if err != nil {
+		return err
+	}
+	for _, allowed := range values {
+		if value == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s is not one of %s", value, strings.Join(values, ", "))
+}
@@ -0,0 +1,104 @@
+package tag_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/noteo/tag"
+)
+
+func TestVocabulary_Normalize(t *testing.T) {
+	vocabulary := &tag.Vocabulary{
+		Names: map[string]tag.ValueType{
+			"bug":      tag.AnyValue,
+			"priority": tag.NumberValue,
+			"deadline": tag.DateValue,
+			"estimate": tag.DurationValue,
+			"done":     tag.BoolValue,
+			"status":   tag.EnumValue("open", "blocked"),
+		},
+		Aliases: map[string]string{
+			"bugs":   "bug",
+			"defect": "bug",
+			"prio":   "priority",
+		},
+	}
+
+	t.Run("should replace alias with canonical name", func(t *testing.T) {
+		tests := map[string]string{
+			"bugs":    "bug",
+			"defect":  "bug",
+			"bug":     "bug",
+			"prio:1":  "priority:1",
+			"unknown": "unknown",
+		}
+		for given, expected := range tests {
+			t.Run(given, func(t *testing.T) {
+				normalized, err := vocabulary.Normalize(newTag(t, given))
+				require.NoError(t, err)
+				assert.Equal(t, newTag(t, expected), normalized)
+			})
+		}
+	})
+
+	t.Run("should validate value type", func(t *testing.T) {
+		tags := []string{"priority:high", "prio:high", "deadline:someday", "deadline", "estimate:2", "done:maybe", "status:closed"}
+		for _, given := range tags {
+			t.Run(given, func(t *testing.T) {
+				_, err := vocabulary.Normalize(newTag(t, given))
+				assert.Error(t, err)
+			})
+		}
+	})
+
+	t.Run("should accept valid values", func(t *testing.T) {
+		tags := []string{"priority:1", "estimate:2h30m", "done:yes", "status:blocked"}
+		for _, given := range tags {
+			t.Run(given, func(t *testing.T) {
+				_, err := vocabulary.Normalize(newTag(t, given))
+				assert.NoError(t, err)
+			})
+		}
+	})
+
+	t.Run("should accept relative date", func(t *testing.T) {
+		_, err := vocabulary.Normalize(newTag(t, "deadline:tomorrow"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("should reject unknown tag in strict mode", func(t *testing.T) {
+		strict := *vocabulary
+		strict.Strict = true
+		_, err := strict.Normalize(newTag(t, "unknown"))
+		assert.Error(t, err)
+		_, err = strict.Normalize(newTag(t, "bugs"))
+		assert.NoError(t, err)
+	})
+}
+
+func newTag(t *testing.T, s string) tag.Tag {
+	tg, err := tag.New(s)
+	require.NoError(t, err)
+	return tg
+}
+
+func TestParseValueType(t *testing.T) {
+	t.Run("should parse enum", func(t *testing.T) {
+		valueType, err := tag.ParseValueType("enum(open, blocked)")
+		require.NoError(t, err)
+		values, ok := valueType.EnumValues()
+		assert.True(t, ok)
+		assert.Equal(t, []string{"open", "blocked"}, values)
+	})
+
+	t.Run("should return error for unsupported type", func(t *testing.T) {
+		for _, given := range []string{"text", "enum()", "enum(a"} {
+			t.Run(given, func(t *testing.T) {
+				_, err := tag.ParseValueType(given)
+				assert.Error(t, err)
+			})
+		}
+	})
+}
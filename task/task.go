@@ -0,0 +1,116 @@
+// Package task extracts Markdown task list items such as "- [ ] foo @due(2020-10-01)" from note bodies
+package task
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/elgopher/noteo/date"
+)
+
+type Task struct {
+	// Line is a line number in the file, starting from 1
+	Line int
+	Text string
+	Done bool
+	Tags []InlineTag
+}
+
+// InlineTag is a tag given in task text in the form of @name or @name(value)
+type InlineTag struct {
+	Name  string
+	Value string
+}
+
+var (
+	taskRegex      = regexp.MustCompile(`^(\s*(?:[-*+]|\d+[.)])\s+\[)([ xX])(]\s+)(.*)$`)
+	inlineTagRegex = regexp.MustCompile(`(?:^|\s)@([\p{L}\p{N}_-]+)(?:\(([^)]*)\))?`)
+	fenceRegex     = regexp.MustCompile("^\\s*(```+|~~~+)")
+)
+
+// Parse returns tasks found in the body, skipping those inside code fences. firstLine is the line number of
+// the first body line in the file.
+func Parse(body string, firstLine int) []Task {
+	var tasks []Task
+	fence := ""
+	for i, line := range strings.Split(body, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if match := fenceRegex.FindStringSubmatch(line); match != nil {
+			switch {
+			case fence == "":
+				fence = match[1]
+			case strings.HasPrefix(match[1], fence[:1]) && len(match[1]) >= len(fence):
+				fence = ""
+			}
+			continue
+		}
+		if fence != "" {
+			continue
+		}
+		match := taskRegex.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		tasks = append(tasks, Task{
+			Line: firstLine + i,
+			Text: match[4],
+			Done: match[2] != " ",
+			Tags: parseInlineTags(match[4]),
+		})
+	}
+	return tasks
+}
+
+func parseInlineTags(text string) []InlineTag {
+	var tags []InlineTag
+	for _, match := range inlineTagRegex.FindAllStringSubmatch(text, -1) {
+		tags = append(tags, InlineTag{Name: match[1], Value: match[2]})
+	}
+	return tags
+}
+
+// Tag returns inline tag with given name
+func (t Task) Tag(name string) (InlineTag, bool) {
+	for _, tag := range t.Tags {
+		if tag.Name == name {
+			return tag, true
+		}
+	}
+	return InlineTag{}, false
+}
+
+// Due returns date given in @due(date) inline tag
+func (t Task) Due() (time.Time, bool, error) {
+	due, ok := t.Tag("due")
+	if !ok {
+		return time.Time{}, false, nil
+	}
+	d, err := date.Parse(due.Value)
+	if err != nil {
+		return time.Time{}, true, fmt.Errorf("line %d: invalid due date: %v", t.Line, err)
+	}
+	return d, true, nil
+}
+
+// Complete ticks off the open task in a given line of the body. firstLine is the line number of the first body
+// line in the file.
+func Complete(body string, firstLine, line int) (string, error) {
+	lines := strings.Split(body, "\n")
+	i := line - firstLine
+	if i < 0 || i >= len(lines) {
+		return "", fmt.Errorf("line %d is not in the note body", line)
+	}
+	for _, t := range Parse(body, firstLine) {
+		if t.Line != line {
+			continue
+		}
+		if t.Done {
+			return "", fmt.Errorf("task in line %d is already done", line)
+		}
+		lines[i] = taskRegex.ReplaceAllString(lines[i], "${1}x${3}${4}")
+		return strings.Join(lines, "\n"), nil
+	}
+	return "", fmt.Errorf("line %d is not a task", line)
+}
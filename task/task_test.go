@@ -0,0 +1,71 @@
+package task_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/noteo/task"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("should parse tasks", func(t *testing.T) {
+		body := "# Title\n- [ ] open\n- [x] done\n* [X] done with capital\n  + [ ] nested\n1. [ ] numbered\n- [] not a task\n-[ ] not a task"
+		// when
+		tasks := task.Parse(body, 5)
+		// then
+		assert.Equal(t, []task.Task{
+			{Line: 6, Text: "open"},
+			{Line: 7, Text: "done", Done: true},
+			{Line: 8, Text: "done with capital", Done: true},
+			{Line: 9, Text: "nested"},
+			{Line: 10, Text: "numbered"},
+		}, tasks)
+	})
+
+	t.Run("should skip tasks in code fences", func(t *testing.T) {
+		body := "```md\n- [ ] in fence\n```\n- [ ] after\n~~~~\n```\n- [ ] in tilde fence\n~~~~\n- [ ] last"
+		// when
+		tasks := task.Parse(body, 1)
+		// then
+		require.Len(t, tasks, 2)
+		assert.Equal(t, "after", tasks[0].Text)
+		assert.Equal(t, "last", tasks[1].Text)
+	})
+
+	t.Run("should parse inline tags", func(t *testing.T) {
+		tasks := task.Parse("- [ ] call @bob about @due(2020-10-01), mail a@b.com", 1)
+		// then
+		require.Len(t, tasks, 1)
+		assert.Equal(t, []task.InlineTag{
+			{Name: "bob"},
+			{Name: "due", Value: "2020-10-01"},
+		}, tasks[0].Tags)
+		due, found, err := tasks[0].Due()
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, 2020, due.Year())
+	})
+}
+
+func TestComplete(t *testing.T) {
+	t.Run("should tick off task", func(t *testing.T) {
+		body := "text\r\n- [ ] first\r\n  * [ ] second\r\n"
+		// when
+		newBody, err := task.Complete(body, 3, 5)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "text\r\n- [ ] first\r\n  * [x] second\r\n", newBody)
+	})
+
+	t.Run("should return error", func(t *testing.T) {
+		body := "text\n- [x] done\n```\n- [ ] in fence\n```"
+		for name, line := range map[string]int{"not a task": 1, "done": 2, "in fence": 4, "out of body": 10, "front matter": 0} {
+			t.Run(name, func(t *testing.T) {
+				_, err := task.Complete(body, 1, line)
+				assert.Error(t, err)
+			})
+		}
+	})
+}